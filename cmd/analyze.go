@@ -2,11 +2,14 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 
 	"github.com/docbrown/cli/internal/config"
+	"github.com/docbrown/cli/internal/logging"
 	"github.com/docbrown/cli/internal/orchestrator"
 )
 
@@ -14,33 +17,89 @@ var analyzeCmd = &cobra.Command{
 	Use:   "analyze",
 	Short: "Analyze repository structure",
 	Long: `Scan and analyze the codebase structure to identify components,
-detect programming languages, and extract metadata.`,
+detect programming languages, and extract metadata.
+
+With --watch, analysis reruns whenever files that aren't excluded or
+ignored change, manifests included (add --generate to regenerate
+documentation instead). Only components whose files changed
+are regenerated; the rest come from the cache.`,
 	RunE: runAnalyze,
 }
 
+var (
+	analyzeJSON       bool
+	analyzePaths      []string
+	analyzeComponents []string
+	analyzeInclude    []string
+	analyzeExclude    []string
+	analyzeWatch      bool
+	analyzeGenerate   bool
+)
+
 func init() {
 	rootCmd.AddCommand(analyzeCmd)
+
+	analyzeCmd.Flags().BoolVar(&analyzeJSON, "json", false, "write the full analysis as JSON to stdout")
+	analyzeCmd.Flags().StringArrayVar(&analyzePaths, "path", nil, "only include components under this directory (repeatable)")
+	analyzeCmd.Flags().StringArrayVar(&analyzeComponents, "component", nil, "only include the named component (repeatable)")
+	analyzeCmd.Flags().StringArrayVar(&analyzeInclude, "include", nil, "add a file pattern to documentation.include_patterns for this run (repeatable)")
+	analyzeCmd.Flags().StringArrayVar(&analyzeExclude, "exclude", nil, "add a file pattern to documentation.exclude_patterns for this run (repeatable)")
+	analyzeCmd.Flags().BoolVar(&analyzeWatch, "watch", false, "keep running and reanalyze when files change (Ctrl-C to stop)")
+	analyzeCmd.Flags().BoolVar(&analyzeGenerate, "generate", false, "with --watch, regenerate documentation for changed components")
 }
 
 func runAnalyze(cmd *cobra.Command, args []string) error {
+	if analyzeGenerate && !analyzeWatch {
+		return fmt.Errorf("--generate requires --watch")
+	}
+	if analyzeWatch && analyzeJSON {
+		return fmt.Errorf("--json can't be combined with --watch")
+	}
+
 	// Load configuration
-	cfgMgr := config.NewManager()
+	cfgMgr := config.NewManager(cfgFile)
 	cfg, err := cfgMgr.Load()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
+	// Ad-hoc patterns extend the configured ones; excludes still win
+	cfg.Documentation.IncludePatterns = append(cfg.Documentation.IncludePatterns, analyzeInclude...)
+	cfg.Documentation.ExcludePatterns = append(cfg.Documentation.ExcludePatterns, analyzeExclude...)
+
+	// Keep stdout machine-readable: progress goes to stderr
+	if analyzeJSON {
+		logging.SetOutput(os.Stderr)
+	}
+	defer logging.SetOutput(os.Stdout)
+
 	// Create orchestrator
 	orch, err := orchestrator.NewOrchestrator(cfg)
 	if err != nil {
 		return fmt.Errorf("failed to create orchestrator: %w", err)
 	}
+	orch.SetTargets(analyzePaths, analyzeComponents)
+
+	if analyzeWatch {
+		ctx, cancel := runContext(0)
+		defer cancel()
+		return orch.ExecuteWatch(ctx, orchestrator.WatchOptions{Generate: analyzeGenerate})
+	}
 
 	// Execute analysis
 	ctx := context.Background()
-	if _, err := orch.ExecuteAnalyze(ctx); err != nil {
+	structure, err := orch.ExecuteAnalyze(ctx)
+	if err != nil {
 		return err
 	}
 
+	if analyzeJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(structure); err != nil {
+			return fmt.Errorf("failed to encode analysis: %w", err)
+		}
+	}
+
 	return nil
 }
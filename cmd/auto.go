@@ -1,17 +1,35 @@
 package cmd
 
 import (
-	"context"
+	"encoding/json"
 	"fmt"
+	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/docbrown/cli/internal/config"
+	"github.com/docbrown/cli/internal/logging"
+	"github.com/docbrown/cli/internal/notify"
 	"github.com/docbrown/cli/internal/orchestrator"
 )
 
 var (
-	autoProvider string
+	autoProvider         string
+	autoModel            string
+	autoStream           bool
+	autoSince            string
+	autoDeterministic    bool
+	autoMock             bool
+	autoProviderFallback []string
+	autoOutput           string
+	autoJSON             bool
+	autoTimeout          time.Duration
+	autoResume           bool
+	autoPaths            []string
+	autoComponents       []string
+	autoInclude          []string
+	autoExclude          []string
 )
 
 var autoCmd = &cobra.Command{
@@ -29,12 +47,26 @@ This is the recommended way to use DocBrown.`,
 func init() {
 	rootCmd.AddCommand(autoCmd)
 
-	autoCmd.Flags().StringVar(&autoProvider, "provider", "", "LLM provider (anthropic/ollama/auto)")
+	autoCmd.Flags().StringVar(&autoProvider, "provider", "", "LLM provider (anthropic/ollama/openai/auto)")
+	autoCmd.Flags().StringVar(&autoModel, "model", "", "model to use for this run (overrides the provider's configured model)")
+	autoCmd.Flags().BoolVar(&autoStream, "stream", false, "stream LLM output and show live progress")
+	autoCmd.Flags().StringVar(&autoOutput, "output", "", "output directory (overrides documentation.output_dir; may be outside the repository)")
+	autoCmd.Flags().BoolVar(&autoJSON, "json", false, "write the run summary, including per-component usage, as JSON to stdout")
+	autoCmd.Flags().BoolVar(&autoResume, "resume", true, "skip components finished by an earlier run; --resume=false regenerates everything")
+	autoCmd.Flags().DurationVar(&autoTimeout, "timeout", 0, "overall deadline for the run, e.g. 30m (overrides performance.timeout)")
+	autoCmd.Flags().StringVar(&autoSince, "since", "", "only regenerate components with files changed since this git ref (ignores the cache)")
+	autoCmd.Flags().BoolVar(&autoDeterministic, "deterministic", false, "byte-stable output: temperature 0, fixed seed, sorted data and a commit-based timestamp")
+	autoCmd.Flags().BoolVar(&autoMock, "mock", false, "use the offline mock provider (canned responses, no LLM calls)")
+	autoCmd.Flags().StringSliceVar(&autoProviderFallback, "provider-fallback", nil, "providers to fall back to, in order, when the provider is unavailable, e.g. anthropic,openai (overrides llm.fallback)")
+	autoCmd.Flags().StringArrayVar(&autoPaths, "path", nil, "only generate components under this directory (repeatable); others keep their docs and cache")
+	autoCmd.Flags().StringArrayVar(&autoComponents, "component", nil, "only generate the named component (repeatable)")
+	autoCmd.Flags().StringArrayVar(&autoInclude, "include", nil, "add a file pattern to documentation.include_patterns for this run (repeatable)")
+	autoCmd.Flags().StringArrayVar(&autoExclude, "exclude", nil, "add a file pattern to documentation.exclude_patterns for this run (repeatable)")
 }
 
 func runAuto(cmd *cobra.Command, args []string) error {
 	// Load configuration
-	cfgMgr := config.NewManager()
+	cfgMgr := config.NewManager(cfgFile)
 	cfg, err := cfgMgr.Load()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
@@ -44,17 +76,73 @@ func runAuto(cmd *cobra.Command, args []string) error {
 	if autoProvider != "" {
 		cfg.LLM.Provider = autoProvider
 	}
+	if autoModel != "" {
+		cfg.LLM.Model = autoModel
+	}
+	if autoOutput != "" {
+		cfg.Documentation.OutputDir = autoOutput
+	}
+	if autoStream {
+		cfg.LLM.Stream = true
+	}
+	if autoTimeout > 0 {
+		cfg.Performance.Timeout = autoTimeout
+	}
+	if autoDeterministic {
+		cfg.Documentation.Deterministic = true
+	}
+	if cmd.Flags().Changed("provider-fallback") {
+		cfg.LLM.Fallback = autoProviderFallback
+	}
+	if autoMock {
+		// Offline runs never fall back to a real provider
+		cfg.LLM.Provider = "mock"
+		cfg.LLM.Fallback = nil
+	}
+	// Ad-hoc patterns extend the configured ones; excludes still win
+	cfg.Documentation.IncludePatterns = append(cfg.Documentation.IncludePatterns, autoInclude...)
+	cfg.Documentation.ExcludePatterns = append(cfg.Documentation.ExcludePatterns, autoExclude...)
+
+	// Keep stdout machine-readable: progress goes to stderr
+	if autoJSON {
+		logging.SetOutput(os.Stderr)
+	}
+	defer logging.SetOutput(os.Stdout)
 
 	// Create orchestrator
 	orch, err := orchestrator.NewOrchestrator(cfg)
 	if err != nil {
 		return fmt.Errorf("failed to create orchestrator: %w", err)
 	}
+	orch.SetTargets(autoPaths, autoComponents)
 
 	// Execute auto workflow
-	ctx := context.Background()
-	if err := orch.ExecuteAuto(ctx); err != nil {
-		return err
+	ctx, cancel := runContext(cfg.Performance.Timeout)
+	defer cancel()
+
+	opts := orchestrator.GenerateOptions{
+		Since:  autoSince,
+		Resume: autoResume,
+	}
+	summary, err := orch.ExecuteAuto(ctx, opts)
+	if err != nil {
+		return cancelledError(cmd, ctx, err)
+	}
+
+	sendNotification(cfg, notify.Summary{
+		Event:        notify.EventGenerated,
+		Components:   summary.Components,
+		QualityScore: summary.QualityScore,
+		Provider:     summary.Provider,
+		Cost:         summary.Cost,
+	})
+
+	if autoJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(summary); err != nil {
+			return fmt.Errorf("failed to encode summary: %w", err)
+		}
 	}
 
 	return nil
@@ -7,6 +7,7 @@ import (
 
 	"github.com/docbrown/cli/internal/cache"
 	"github.com/docbrown/cli/internal/config"
+	"github.com/docbrown/cli/internal/logging"
 )
 
 var cacheCmd = &cobra.Command{
@@ -36,7 +37,7 @@ func init() {
 }
 
 func runCacheShow(cmd *cobra.Command, args []string) error {
-	cfgMgr := config.NewManager()
+	cfgMgr := config.NewManager(cfgFile)
 	cfg, err := cfgMgr.Load()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
@@ -85,7 +86,7 @@ func runCacheShow(cmd *cobra.Command, args []string) error {
 }
 
 func runCacheClear(cmd *cobra.Command, args []string) error {
-	cfgMgr := config.NewManager()
+	cfgMgr := config.NewManager(cfgFile)
 	cfg, err := cfgMgr.Load()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
@@ -101,9 +102,9 @@ func runCacheClear(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to clear cache: %w", err)
 	}
 
-	fmt.Println("✓ Cache cleared")
-	fmt.Println()
-	fmt.Println("Next run will regenerate all components.")
+	logging.Infof("✓ Cache cleared")
+	logging.Blank()
+	logging.Infof("Next run will regenerate all components.")
 
 	return nil
 }
@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docbrown/cli/internal/cache"
+	"github.com/docbrown/cli/internal/config"
+	"github.com/docbrown/cli/internal/logging"
+)
+
+var (
+	cleanCache bool
+	cleanForce bool
+)
+
+var cleanCmd = &cobra.Command{
+	Use:   "clean",
+	Short: "Remove generated documentation",
+	Long: `Remove documentation generated by DocBrown. Only files in the output
+directory carrying the DocBrown attribution marker, and files written by
+the last generation run, are removed, so hand-written docs are kept.
+Directories left empty are removed too.`,
+	RunE: runClean,
+}
+
+func init() {
+	rootCmd.AddCommand(cleanCmd)
+
+	cleanCmd.Flags().BoolVar(&cleanCache, "cache", false, "also clear the generation cache")
+	cleanCmd.Flags().BoolVar(&cleanForce, "force", false, "don't prompt for confirmation")
+}
+
+func runClean(cmd *cobra.Command, args []string) error {
+	// Load configuration
+	cfgMgr := config.NewManager(cfgFile)
+	cfg, err := cfgMgr.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	marker := generatedMarker(cfg)
+	outputDir := filepath.Clean(cfg.Documentation.OutputDir)
+
+	// Files from the last generation run, which may no longer carry the
+	// marker if they were edited
+	var manifestFiles []string
+	if files, err := cache.LoadManifest(filepath.Join(cfg.Cache.Dir, cache.ManifestFile)); err == nil {
+		manifestFiles = files
+	} else if !os.IsNotExist(err) {
+		logging.Warnf("⚠ Ignoring generated file list: %v", err)
+	}
+
+	targets, skipped := cleanTargets(outputDir, marker, manifestFiles)
+
+	for _, path := range skipped {
+		logging.Warnf("⚠ Skipping %s", path)
+	}
+
+	if len(targets) == 0 && !cleanCache {
+		logging.Infof("✓ Nothing to clean")
+		return nil
+	}
+
+	if !cleanForce {
+		fmt.Println("The following will be removed:")
+		for _, path := range targets {
+			fmt.Printf("  - %s\n", path)
+		}
+		if cleanCache {
+			fmt.Printf("  - %s (cache)\n", filepath.Join(cfg.Cache.Dir, "cache.yaml"))
+		}
+		fmt.Println()
+
+		if !confirm("Continue?") {
+			fmt.Println("Aborted")
+			return nil
+		}
+	}
+
+	for _, path := range targets {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+		removeEmptyDirs(filepath.Dir(path), outputDir)
+	}
+	if len(targets) > 0 {
+		logging.Infof("✓ Removed %d generated files", len(targets))
+	}
+
+	if cleanCache {
+		cacheMgr := cache.NewManager(
+			cfg.Cache.Dir+"/cache.yaml",
+			cfg.Cache.Enabled,
+			cfg.Cache.TTL,
+		)
+
+		if err := cacheMgr.Clear(); err != nil {
+			return fmt.Errorf("failed to clear cache: %w", err)
+		}
+		logging.Infof("✓ Cache cleared")
+	}
+
+	return nil
+}
+
+// generatedMarker returns the attribution text DocBrown writes into generated files
+func generatedMarker(cfg *config.Config) string {
+	if cfg.Documentation.GeneratedBy != "" {
+		return cfg.Documentation.GeneratedBy
+	}
+	return "Generated by DocBrown"
+}
+
+// cleanTargets returns the generated files to remove: files under
+// outputDir carrying the marker, and files listed in the generation
+// manifest. Other files are left alone, along with the whole tree when the
+// output directory is the repository root.
+func cleanTargets(outputDir, marker string, manifestFiles []string) (targets, skipped []string) {
+	seen := make(map[string]bool)
+	add := func(path string) {
+		path = filepath.Clean(path)
+		if !seen[path] {
+			seen[path] = true
+			targets = append(targets, path)
+		}
+	}
+
+	if outputDir == "." || outputDir == string(filepath.Separator) {
+		skipped = append(skipped, outputDir+" (output directory is the repository root; only files from the last run are removed)")
+	} else if info, err := os.Stat(outputDir); err == nil && info.IsDir() {
+		var unmarked int
+		filepath.WalkDir(outputDir, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil || entry.IsDir() {
+				return nil
+			}
+			if fileHasMarker(path, marker) {
+				add(path)
+			} else {
+				unmarked++
+			}
+			return nil
+		})
+		if unmarked > 0 {
+			skipped = append(skipped, fmt.Sprintf("%d files in %s without the DocBrown marker", unmarked, outputDir))
+		}
+	}
+
+	for _, path := range manifestFiles {
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			add(path)
+		}
+	}
+
+	sort.Strings(targets)
+	return targets, skipped
+}
+
+// removeEmptyDirs removes dir and its parents, up to and including
+// outputDir, while they are empty. Directories outside outputDir are kept.
+func removeEmptyDirs(dir, outputDir string) {
+	for {
+		rel, err := filepath.Rel(outputDir, dir)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return
+		}
+		if os.Remove(dir) != nil {
+			return // Not empty
+		}
+		if rel == "." {
+			return
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+// fileHasMarker checks whether a file contains the marker
+func fileHasMarker(path, marker string) bool {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	return strings.Contains(string(content), marker)
+}
+
+// confirm asks a yes/no question on stdin, defaulting to no
+func confirm(question string) bool {
+	fmt.Printf("%s [y/N]: ", question)
+
+	reader := bufio.NewReader(os.Stdin)
+	answer, err := reader.ReadString('\n')
+	if err != nil {
+		fmt.Println()
+		return false // Non-interactive or closed stdin
+	}
+
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCleanKeepsHandWrittenFiles(t *testing.T) {
+	root := t.TempDir()
+	outputDir := filepath.Join(root, "docs")
+	marker := "Generated by DocBrown"
+
+	files := map[string]string{
+		"docs/docs/index.md":           marker,
+		"docs/docs/components/api.md":  marker,
+		"docs/mkdocs.yml":              "# " + marker,
+		"docs/docs/guides/edited.md":   "marker removed by hand",
+		"docs/notes/hand-written.md":   "notes",
+		"docs/docs/components/team.md": "hand-written",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	manifest := []string{
+		filepath.Join(root, "docs/docs/guides/edited.md"),
+		filepath.Join(root, "docs/docs/components/gone.md"), // already removed
+	}
+	targets, _ := cleanTargets(outputDir, marker, manifest)
+
+	want := []string{
+		filepath.Join(root, "docs/docs/components/api.md"),
+		filepath.Join(root, "docs/docs/guides/edited.md"),
+		filepath.Join(root, "docs/docs/index.md"),
+		filepath.Join(root, "docs/mkdocs.yml"),
+	}
+	if len(targets) != len(want) {
+		t.Fatalf("targets = %v, want %v", targets, want)
+	}
+	for i := range want {
+		if targets[i] != want[i] {
+			t.Fatalf("targets = %v, want %v", targets, want)
+		}
+	}
+
+	for _, path := range targets {
+		if err := os.Remove(path); err != nil {
+			t.Fatal(err)
+		}
+		removeEmptyDirs(filepath.Dir(path), outputDir)
+	}
+
+	for _, kept := range []string{"docs/notes/hand-written.md", "docs/docs/components/team.md"} {
+		if _, err := os.Stat(filepath.Join(root, kept)); err != nil {
+			t.Errorf("%s was removed", kept)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(root, "docs/docs/guides")); !os.IsNotExist(err) {
+		t.Error("empty docs/docs/guides was kept")
+	}
+}
+
+func TestCleanRootOutputDir(t *testing.T) {
+	targets, skipped := cleanTargets(".", "Generated by DocBrown", nil)
+	if len(targets) != 0 || len(skipped) != 1 {
+		t.Errorf("targets = %v, skipped = %v; want the repository root skipped", targets, skipped)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"gopkg.in/yaml.v3"
 
 	"github.com/docbrown/cli/internal/config"
+	"github.com/docbrown/cli/internal/logging"
 )
 
 var configCmd = &cobra.Command{
@@ -30,6 +31,17 @@ var configGetCmd = &cobra.Command{
 	RunE:  runConfigGet,
 }
 
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check the configuration for errors",
+	Long: `Load the merged configuration and report every problem found: unknown
+provider or push strategy, empty output directory, missing template,
+negative limits and durations that don't parse. Exits non-zero if the
+configuration is invalid, so it can run in CI.`,
+	Args: cobra.NoArgs,
+	RunE: runConfigValidate,
+}
+
 var configSetCmd = &cobra.Command{
 	Use:   "set <key> <value>",
 	Short: "Set configuration value",
@@ -43,10 +55,11 @@ func init() {
 	configCmd.AddCommand(configShowCmd)
 	configCmd.AddCommand(configGetCmd)
 	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configValidateCmd)
 }
 
 func runConfigShow(cmd *cobra.Command, args []string) error {
-	cfgMgr := config.NewManager()
+	cfgMgr := config.NewManager(cfgFile)
 	cfg, err := cfgMgr.Load()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
@@ -55,6 +68,15 @@ func runConfigShow(cmd *cobra.Command, args []string) error {
 	fmt.Println("Configuration:")
 	fmt.Println()
 
+	// Don't echo a token taken from the environment or encrypted_pat, or a
+	// webhook URL, which embeds its own credentials
+	if cfg.Git.PAT != "" {
+		cfg.Git.PAT = "********"
+	}
+	if cfg.Notifications.WebhookURL != "" {
+		cfg.Notifications.WebhookURL = "********"
+	}
+
 	data, err := yaml.Marshal(cfg)
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
@@ -68,7 +90,7 @@ func runConfigShow(cmd *cobra.Command, args []string) error {
 func runConfigGet(cmd *cobra.Command, args []string) error {
 	key := args[0]
 
-	cfgMgr := config.NewManager()
+	cfgMgr := config.NewManager(cfgFile)
 	if _, err := cfgMgr.Load(); err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
@@ -83,11 +105,46 @@ func runConfigGet(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runConfigValidate(cmd *cobra.Command, args []string) error {
+	// Problems are listed below; usage text would only add noise
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+
+	cfgMgr := config.NewManager(cfgFile)
+	if _, err := cfgMgr.Load(); err != nil {
+		fmt.Println("✗ Configuration could not be loaded:")
+		fmt.Printf("  - %s\n", err)
+		return fmt.Errorf("configuration is invalid")
+	}
+
+	err := cfgMgr.Validate()
+	if err == nil {
+		fmt.Println("✓ Configuration is valid")
+		return nil
+	}
+
+	problems := []error{err}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		problems = joined.Unwrap()
+	}
+
+	count := fmt.Sprintf("%d problems", len(problems))
+	if len(problems) == 1 {
+		count = "1 problem"
+	}
+	fmt.Printf("✗ Configuration has %s:\n", count)
+	for _, problem := range problems {
+		fmt.Printf("  - %s\n", problem)
+	}
+
+	return fmt.Errorf("configuration is invalid")
+}
+
 func runConfigSet(cmd *cobra.Command, args []string) error {
 	key := args[0]
 	value := args[1]
 
-	cfgMgr := config.NewManager()
+	cfgMgr := config.NewManager(cfgFile)
 	if _, err := cfgMgr.Load(); err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
@@ -96,11 +153,14 @@ func runConfigSet(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to set value: %w", err)
 	}
 
+	// Saving drops the plaintext token, but it is still in git history
+	committedPAT(cfgMgr)
+
 	if err := cfgMgr.Save(); err != nil {
 		return fmt.Errorf("failed to save config: %w", err)
 	}
 
-	fmt.Printf("✓ Set %s = %s\n", key, value)
+	logging.Infof("✓ Set %s = %s", key, value)
 
 	return nil
 }
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docbrown/cli/internal/logging"
+)
+
+// runContext returns a context that is cancelled on Ctrl-C or SIGTERM and,
+// if timeout is set, once it expires
+func runContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	if timeout <= 0 {
+		return ctx, stop
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	return ctx, func() {
+		cancel()
+		stop()
+	}
+}
+
+// cancelledError replaces the error from a run that was interrupted or timed
+// out with a single clean message
+func cancelledError(cmd *cobra.Command, ctx context.Context, err error) error {
+	if err == nil || ctx.Err() == nil {
+		return err
+	}
+
+	// The cause is explained below; usage text would only add noise
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+
+	logging.Blank()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		logging.Warnf("⚠ Timed out; completed components were saved and will be skipped next run")
+		return fmt.Errorf("timed out")
+	}
+	logging.Warnf("⚠ Cancelled; completed components were saved and will be skipped next run")
+	return fmt.Errorf("cancelled")
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docbrown/cli/internal/config"
+	"github.com/docbrown/cli/internal/orchestrator"
+)
+
+var (
+	costProvider string
+	costNoCache  bool
+)
+
+var costCmd = &cobra.Command{
+	Use:   "cost",
+	Short: "Estimate generation cost",
+	Long: `Estimate the tokens and cost of generating documentation without
+calling the LLM. Components the cache would skip are not counted, so the
+estimate reflects an incremental run.`,
+	RunE: runCost,
+}
+
+func init() {
+	rootCmd.AddCommand(costCmd)
+
+	costCmd.Flags().StringVar(&costProvider, "provider", "", "LLM provider to price (anthropic/ollama/openai/auto)")
+	costCmd.Flags().BoolVar(&costNoCache, "no-cache", false, "estimate a full regeneration, ignoring the cache")
+}
+
+func runCost(cmd *cobra.Command, args []string) error {
+	// Load configuration
+	cfgMgr := config.NewManager(cfgFile)
+	cfg, err := cfgMgr.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Apply CLI overrides
+	if costProvider != "" {
+		cfg.LLM.Provider = costProvider
+	}
+	if costNoCache {
+		cfg.Cache.Enabled = false
+	}
+
+	// Create orchestrator
+	orch, err := orchestrator.NewOrchestrator(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create orchestrator: %w", err)
+	}
+
+	return orch.ExecuteCost(context.Background())
+}
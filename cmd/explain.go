@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docbrown/cli/internal/config"
+	"github.com/docbrown/cli/internal/logging"
+	"github.com/docbrown/cli/internal/orchestrator"
+)
+
+var (
+	explainProvider string
+	explainModel    string
+)
+
+var explainCmd = &cobra.Command{
+	Use:   "explain <path>",
+	Short: "Document a single file or directory",
+	Long: `Send one file, or the files in one directory, to the LLM and print the
+resulting markdown to stdout. Nothing is written or cached, which makes it
+handy for ad-hoc documentation during code review.
+
+Files are selected, size-limited and redacted exactly as in 'generate'.`,
+	Example: `  docbrown explain internal/cache/manager.go
+  docbrown explain services/billing --provider anthropic > billing.md`,
+	Args: cobra.ExactArgs(1),
+	RunE: runExplain,
+}
+
+func init() {
+	rootCmd.AddCommand(explainCmd)
+
+	explainCmd.Flags().StringVar(&explainProvider, "provider", "", "LLM provider (anthropic/ollama/openai/mock/auto)")
+	explainCmd.Flags().StringVar(&explainModel, "model", "", "model to use (overrides the provider's configured model)")
+}
+
+func runExplain(cmd *cobra.Command, args []string) error {
+	// Load configuration
+	cfgMgr := config.NewManager(cfgFile)
+	cfg, err := cfgMgr.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Apply CLI overrides
+	if explainProvider != "" {
+		cfg.LLM.Provider = explainProvider
+	}
+	if explainModel != "" {
+		cfg.LLM.Model = explainModel
+	}
+
+	// Keep stdout for the markdown: progress goes to stderr
+	logging.SetOutput(os.Stderr)
+	defer logging.SetOutput(os.Stdout)
+
+	orch, err := orchestrator.NewOrchestrator(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create orchestrator: %w", err)
+	}
+
+	ctx, cancel := runContext(cfg.Performance.Timeout)
+	defer cancel()
+
+	docs, err := orch.ExecuteExplain(ctx, args[0])
+	if err != nil {
+		return cancelledError(cmd, ctx, fmt.Errorf("failed to explain %s: %w", args[0], err))
+	}
+
+	fmt.Fprintln(os.Stdout, strings.TrimRight(docs, "\n"))
+	return nil
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docbrown/cli/internal/config"
+	"github.com/docbrown/cli/internal/fileutil"
+	"github.com/docbrown/cli/internal/logging"
+	"github.com/docbrown/cli/internal/preview"
+)
+
+var (
+	exportFormat     string
+	exportOutputFile string
+	exportTitle      string
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export generated documentation as a single HTML file or zip",
+	Long: `Export the generated documentation for readers who don't use Backstage.
+
+--format html renders every markdown page in the output directory into one
+self-contained HTML file, with links between pages turned into in-page
+anchors and images embedded. --format zip archives the docs tree as-is.`,
+	Example: `  docbrown export
+  docbrown export --format zip --output-file docs.zip`,
+	RunE: runExport,
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+
+	exportCmd.Flags().StringVar(&exportFormat, "format", "html", "export format (html/zip)")
+	exportCmd.Flags().StringVarP(&exportOutputFile, "output-file", "o", "", "destination file (default: <output_dir>.html or .zip)")
+	exportCmd.Flags().StringVar(&exportTitle, "title", "", "page title for HTML exports (default: repository name)")
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	// Load configuration
+	cfgMgr := config.NewManager(cfgFile)
+	cfg, err := cfgMgr.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	format := strings.ToLower(exportFormat)
+	if format != "html" && format != "zip" {
+		return fmt.Errorf("unsupported export format %q (use html or zip)", exportFormat)
+	}
+
+	outputDir := filepath.Clean(cfg.Documentation.OutputDir)
+	if info, err := os.Stat(outputDir); err != nil || !info.IsDir() {
+		return fmt.Errorf("output directory %s not found (run 'docbrown generate' first)", outputDir)
+	}
+
+	outputFile := exportOutputFile
+	if outputFile == "" {
+		outputFile = filepath.Base(outputDir) + "." + format
+	}
+
+	// A previous export inside the docs tree would end up in the next one
+	if rel, err := filepath.Rel(outputDir, outputFile); err == nil && !strings.HasPrefix(rel, "..") {
+		return fmt.Errorf("output file %s must be outside the output directory %s", outputFile, outputDir)
+	}
+
+	var buf bytes.Buffer
+	switch format {
+	case "html":
+		title := exportTitle
+		if title == "" {
+			if dir, err := os.Getwd(); err == nil {
+				title = filepath.Base(dir)
+			}
+		}
+		err = preview.ExportHTML(outputDir, title, &buf)
+	case "zip":
+		err = preview.ExportZip(outputDir, &buf)
+	}
+	if err != nil {
+		return fmt.Errorf("export failed: %w", err)
+	}
+
+	if err := fileutil.WriteFileAtomic(outputFile, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", outputFile, err)
+	}
+
+	logging.Infof("✓ Exported %s to %s", outputDir, outputFile)
+	return nil
+}
@@ -1,8 +1,8 @@
 package cmd
 
 import (
-	"context"
 	"fmt"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -11,9 +11,23 @@ import (
 )
 
 var (
-	generateProvider string
-	generateTemplate string
-	genNoCache       bool
+	generateProvider    string
+	generateTemplate    string
+	generateModel       string
+	genNoCache          bool
+	genStream           bool
+	genDryRun           bool
+	genSince            string
+	genDeterministic    bool
+	genMock             bool
+	genProviderFallback []string
+	genOutput           string
+	genTimeout          time.Duration
+	genResume           bool
+	genPaths            []string
+	genComponents       []string
+	genInclude          []string
+	genExclude          []string
 )
 
 var generateCmd = &cobra.Command{
@@ -27,14 +41,28 @@ and the configured template.`,
 func init() {
 	rootCmd.AddCommand(generateCmd)
 
-	generateCmd.Flags().StringVar(&generateProvider, "provider", "", "LLM provider (anthropic/ollama/auto)")
+	generateCmd.Flags().StringVar(&generateProvider, "provider", "", "LLM provider (anthropic/ollama/openai/auto)")
+	generateCmd.Flags().StringVar(&generateModel, "model", "", "model to use for this run (overrides the provider's configured model)")
 	generateCmd.Flags().StringVar(&generateTemplate, "template", "", "documentation template")
 	generateCmd.Flags().BoolVar(&genNoCache, "no-cache", false, "disable cache, regenerate all")
+	generateCmd.Flags().BoolVar(&genStream, "stream", false, "stream LLM output and show live progress")
+	generateCmd.Flags().BoolVar(&genDryRun, "dry-run", false, "show what would be generated without calling the LLM or writing files")
+	generateCmd.Flags().StringVar(&genOutput, "output", "", "output directory (overrides documentation.output_dir; may be outside the repository)")
+	generateCmd.Flags().BoolVar(&genResume, "resume", true, "skip components finished by an earlier run; --resume=false regenerates everything")
+	generateCmd.Flags().DurationVar(&genTimeout, "timeout", 0, "overall deadline for the run, e.g. 30m (overrides performance.timeout)")
+	generateCmd.Flags().StringVar(&genSince, "since", "", "only regenerate components with files changed since this git ref (ignores the cache)")
+	generateCmd.Flags().BoolVar(&genDeterministic, "deterministic", false, "byte-stable output: temperature 0, fixed seed, sorted data and a commit-based timestamp")
+	generateCmd.Flags().BoolVar(&genMock, "mock", false, "use the offline mock provider (canned responses, no LLM calls)")
+	generateCmd.Flags().StringSliceVar(&genProviderFallback, "provider-fallback", nil, "providers to fall back to, in order, when the provider is unavailable, e.g. anthropic,openai (overrides llm.fallback)")
+	generateCmd.Flags().StringArrayVar(&genPaths, "path", nil, "only generate components under this directory (repeatable); others keep their docs and cache")
+	generateCmd.Flags().StringArrayVar(&genComponents, "component", nil, "only generate the named component (repeatable)")
+	generateCmd.Flags().StringArrayVar(&genInclude, "include", nil, "add a file pattern to documentation.include_patterns for this run (repeatable)")
+	generateCmd.Flags().StringArrayVar(&genExclude, "exclude", nil, "add a file pattern to documentation.exclude_patterns for this run (repeatable)")
 }
 
 func runGenerate(cmd *cobra.Command, args []string) error {
 	// Load configuration
-	cfgMgr := config.NewManager()
+	cfgMgr := config.NewManager(cfgFile)
 	cfg, err := cfgMgr.Load()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
@@ -44,23 +72,57 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 	if generateProvider != "" {
 		cfg.LLM.Provider = generateProvider
 	}
+	if generateModel != "" {
+		cfg.LLM.Model = generateModel
+	}
 	if generateTemplate != "" {
 		cfg.Documentation.Template = generateTemplate
 	}
+	if genOutput != "" {
+		cfg.Documentation.OutputDir = genOutput
+	}
 	if genNoCache {
 		cfg.Cache.Enabled = false
 	}
+	if genStream {
+		cfg.LLM.Stream = true
+	}
+	if genTimeout > 0 {
+		cfg.Performance.Timeout = genTimeout
+	}
+	if genDeterministic {
+		cfg.Documentation.Deterministic = true
+	}
+	if cmd.Flags().Changed("provider-fallback") {
+		cfg.LLM.Fallback = genProviderFallback
+	}
+	if genMock {
+		// Offline runs never fall back to a real provider
+		cfg.LLM.Provider = "mock"
+		cfg.LLM.Fallback = nil
+	}
+	// Ad-hoc patterns extend the configured ones; excludes still win
+	cfg.Documentation.IncludePatterns = append(cfg.Documentation.IncludePatterns, genInclude...)
+	cfg.Documentation.ExcludePatterns = append(cfg.Documentation.ExcludePatterns, genExclude...)
 
 	// Create orchestrator
 	orch, err := orchestrator.NewOrchestrator(cfg)
 	if err != nil {
 		return fmt.Errorf("failed to create orchestrator: %w", err)
 	}
+	orch.SetTargets(genPaths, genComponents)
 
 	// Execute generation
-	ctx := context.Background()
-	if err := orch.ExecuteGenerate(ctx); err != nil {
-		return err
+	ctx, cancel := runContext(cfg.Performance.Timeout)
+	defer cancel()
+
+	opts := orchestrator.GenerateOptions{
+		DryRun: genDryRun,
+		Since:  genSince,
+		Resume: genResume,
+	}
+	if _, err := orch.ExecuteGenerate(ctx, opts); err != nil {
+		return cancelledError(cmd, ctx, err)
 	}
 
 	return nil
@@ -8,6 +8,7 @@ import (
 	"gopkg.in/yaml.v3"
 
 	"github.com/docbrown/cli/internal/config"
+	"github.com/docbrown/cli/internal/logging"
 )
 
 var (
@@ -29,18 +30,18 @@ func init() {
 
 	initCmd.Flags().BoolVar(&initForce, "force", false, "overwrite existing .docbrown.yaml")
 	initCmd.Flags().StringVar(&initTemplate, "template", "backstage", "template to use")
-	initCmd.Flags().StringVar(&initProvider, "provider", "auto", "LLM provider (auto/anthropic/ollama)")
+	initCmd.Flags().StringVar(&initProvider, "provider", "auto", "LLM provider (auto/anthropic/ollama/openai)")
 }
 
 func runInit(cmd *cobra.Command, args []string) error {
-	fmt.Println("Initializing DocBrown...")
-	fmt.Println()
+	logging.Infof("Initializing DocBrown...")
+	logging.Blank()
 
 	// Check if git repository
 	if !isGitRepo() {
 		return fmt.Errorf("not a git repository (run 'git init' first)")
 	}
-	fmt.Println("✓ Git repository detected")
+	logging.Infof("✓ Git repository detected")
 
 	// Check if config already exists
 	configPath := ".docbrown.yaml"
@@ -50,7 +51,7 @@ func runInit(cmd *cobra.Command, args []string) error {
 
 	// Detect repository type
 	repoType := detectRepoType()
-	fmt.Printf("✓ Detected: %s\n", repoType)
+	logging.Infof("✓ Detected: %s", repoType)
 
 	// Create config with defaults
 	cfg := config.DefaultConfig()
@@ -67,17 +68,17 @@ func runInit(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to write config: %w", err)
 	}
 
-	fmt.Println()
-	fmt.Println("✓ Created .docbrown.yaml with recommended settings:")
-	fmt.Printf("  - Template: %s\n", initTemplate)
-	fmt.Printf("  - Provider: %s\n", initProvider)
-	fmt.Printf("  - Output: %s\n", cfg.Documentation.OutputDir)
-	fmt.Println()
-
-	fmt.Println("Next steps:")
-	fmt.Println("  1. Set API key: export ANTHROPIC_API_KEY=sk-...")
-	fmt.Println("     (or use local Ollama: no key needed)")
-	fmt.Println("  2. Run: docbrown auto")
+	logging.Blank()
+	logging.Infof("✓ Created .docbrown.yaml with recommended settings:")
+	logging.Infof("  - Template: %s", initTemplate)
+	logging.Infof("  - Provider: %s", initProvider)
+	logging.Infof("  - Output: %s", cfg.Documentation.OutputDir)
+	logging.Blank()
+
+	logging.Infof("Next steps:")
+	logging.Infof("  1. Set API key: export ANTHROPIC_API_KEY=sk-...")
+	logging.Infof("     (or use local Ollama: no key needed)")
+	logging.Infof("  2. Run: docbrown auto")
 
 	return nil
 }
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/docbrown/cli/internal/cache"
+	"github.com/docbrown/cli/internal/config"
+	"github.com/docbrown/cli/internal/git"
+	"github.com/docbrown/cli/internal/logging"
+	"github.com/docbrown/cli/internal/notify"
+	"github.com/docbrown/cli/internal/validator"
+)
+
+// sendNotification posts summary to notifications.webhook_url, if set.
+// Failures only warn: a notification never fails the run.
+func sendNotification(cfg *config.Config, summary notify.Summary) {
+	notifier := notify.NewNotifier(cfg.Notifications.WebhookURL, cfg.Notifications.Format, cfg.Notifications.Timeout)
+	if notifier == nil {
+		return
+	}
+
+	if summary.Repo == "" {
+		summary.Repo = currentRepoName(cfg)
+	}
+
+	// The run's context may already be cancelled or past its deadline
+	if err := notifier.Notify(context.Background(), summary); err != nil {
+		logging.Warnf("⚠ Failed to send notification: %v", err)
+		return
+	}
+	logging.Infof("✓ Sent %s notification", cfg.Notifications.Format)
+}
+
+// lastRunSummary describes the last generation from its manifest and saved
+// validation results, for notifications sent outside 'auto'
+func lastRunSummary(cfg *config.Config) notify.Summary {
+	var summary notify.Summary
+
+	if manifest, err := cache.ReadManifest(filepath.Join(cfg.Cache.Dir, cache.ManifestFile)); err == nil {
+		summary.Components = len(manifest.Components)
+	}
+	if _, results, err := validator.LoadResults(filepath.Join(cfg.Cache.Dir, cache.ValidationFile)); err == nil {
+		summary.QualityScore = results.QualityScore
+	}
+
+	return summary
+}
+
+// currentRepoName returns the repository name from the git remote, falling
+// back to the working directory's name
+func currentRepoName(cfg *config.Config) string {
+	if gitOps, err := git.NewOperations(cfg.Git.Remote, cfg.Git.BaseBranch); err == nil {
+		if remoteURL, err := gitOps.GetRemoteURL(); err == nil {
+			return repoName(remoteURL)
+		}
+	}
+
+	dir, _ := os.Getwd()
+	return filepath.Base(dir)
+}
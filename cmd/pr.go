@@ -1,23 +1,41 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/docbrown/cli/internal/cache"
 	"github.com/docbrown/cli/internal/config"
 	"github.com/docbrown/cli/internal/git"
 	"github.com/docbrown/cli/internal/git/platforms"
+	"github.com/docbrown/cli/internal/logging"
+	"github.com/docbrown/cli/internal/notify"
+	"github.com/docbrown/cli/internal/template"
+	"github.com/docbrown/cli/internal/validator"
 )
 
 var (
-	prBranch     string
-	prTitle      string
-	prBody       string
-	prPAT        string
-	pushDirect   bool
-	forcePR      bool
+	prBranch    string
+	prTitle     string
+	prBody      string
+	prPAT       string
+	pushDirect  bool
+	forcePR     bool
+	prDraft     bool
+	prAutoMerge bool
+	prLabels    []string
+	prReplace   bool
+	prReviewers []string
+	prAssignees []string
+	prCommitMsg string
 )
 
 var prCmd = &cobra.Command{
@@ -37,16 +55,49 @@ func init() {
 	prCmd.Flags().StringVar(&prPAT, "pat", "", "personal access token")
 	prCmd.Flags().BoolVar(&pushDirect, "push-direct", false, "push directly to base branch (skip PR)")
 	prCmd.Flags().BoolVar(&forcePR, "force-pr", false, "always create PR even if no docs exist")
+	prCmd.Flags().BoolVar(&prDraft, "draft", false, "create the PR as a draft")
+	prCmd.Flags().BoolVar(&prAutoMerge, "auto-merge", false, "enable auto-merge on the PR once checks pass (GitHub and GitLab)")
+	prCmd.Flags().StringArrayVar(&prLabels, "label", nil, "add a label to the PR (repeatable; appended to git.pr_labels)")
+	prCmd.Flags().BoolVar(&prReplace, "replace-labels", false, "use only the --label values instead of appending to git.pr_labels")
+	prCmd.Flags().StringSliceVar(&prReviewers, "reviewer", nil, "request a review from this user (repeatable)")
+	prCmd.Flags().StringSliceVar(&prAssignees, "assignee", nil, "assign the PR to this user (repeatable)")
+	prCmd.Flags().StringVar(&prCommitMsg, "commit-message", "", "commit message for the documentation commit")
 }
 
 func runPR(cmd *cobra.Command, args []string) error {
 	// Load configuration
-	cfgMgr := config.NewManager()
+	cfgMgr := config.NewManager(cfgFile)
 	cfg, err := cfgMgr.Load()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
+	// Pushing would publish a token committed in the config file
+	if committedPAT(cfgMgr) {
+		return fmt.Errorf("refusing to push with a plaintext PAT committed in %s", cfgMgr.ConfigFile())
+	}
+
+	// Override config with flags
+	if prDraft {
+		cfg.Git.DraftPR = true
+	}
+	if prAutoMerge {
+		cfg.Git.AutoMerge = true
+	}
+	if prReplace {
+		cfg.Git.PRLabels = nil
+	}
+	cfg.Git.PRLabels = mergeLabels(cfg.Git.PRLabels, prLabels)
+	if len(prReviewers) > 0 {
+		cfg.Git.Reviewers = prReviewers
+	}
+	if len(prAssignees) > 0 {
+		cfg.Git.Assignees = prAssignees
+	}
+	if prCommitMsg != "" {
+		cfg.Git.CommitMessage = prCommitMsg
+	}
+
 	// Get PAT
 	token := prPAT
 	if token == "" {
@@ -61,6 +112,10 @@ func runPR(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to initialize git: %w", err)
 	}
+	gitOps.SetGitHubHost(cfg.Git.GitHubHost)
+	gitOps.SetGitLabHost(cfg.Git.GitLabHost)
+	gitOps.SetCommitAuthor(cfg.Git.CommitAuthorName, cfg.Git.CommitAuthorEmail)
+	gitOps.SetSigningKey(cfg.Git.SigningKey)
 
 	// Determine strategy
 	strategy := "pr"
@@ -69,8 +124,8 @@ func runPR(cmd *cobra.Command, args []string) error {
 	}
 	strategy = gitOps.DeterminePushStrategy(strategy, forcePR)
 
-	fmt.Println("Creating pull request...")
-	fmt.Println()
+	logging.Infof("Creating pull request...")
+	logging.Blank()
 
 	// Detect platform
 	platformName, err := gitOps.DetectPlatform()
@@ -78,60 +133,69 @@ func runPR(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to detect platform: %w", err)
 	}
 
-	fmt.Printf("✓ Platform: %s\n", platformName)
+	logging.Infof("✓ Platform: %s", platformName)
 
 	remoteURL, err := gitOps.GetRemoteURL()
 	if err != nil {
 		return err
 	}
 
-	fmt.Printf("✓ Remote: %s\n", remoteURL)
-	fmt.Printf("✓ Strategy: %s\n", strategy)
-	fmt.Println()
+	logging.Infof("✓ Remote: %s", remoteURL)
+
+	baseBranch, err := gitOps.GetBaseBranch()
+	if err != nil {
+		return err
+	}
+	cfg.Git.BaseBranch = baseBranch
+	logging.Infof("✓ Base branch: %s", baseBranch)
+	logging.Infof("✓ Strategy: %s", strategy)
+	logging.Blank()
 
 	if strategy == "direct" {
-		return runDirectPush(gitOps, token)
+		return runDirectPush(gitOps, token, cfg)
 	}
 
 	return runPRCreation(gitOps, platformName, remoteURL, token, cfg)
 }
 
-func runDirectPush(gitOps *git.Operations, token string) error {
-	fmt.Println("📝 Pushing directly to base branch...")
+func runDirectPush(gitOps *git.Operations, token string, cfg *config.Config) error {
+	logging.Infof("📝 Pushing directly to base branch...")
 
-	// Stage files
-	filesToStage := []string{
-		"docs/",
-		"mkdocs.yml",
-		"catalog-info.yaml",
+	// Stage the files written by the last generation
+	filesToStage, err := generatedFilesToStage(cfg)
+	if err != nil {
+		return err
 	}
 
 	if err := gitOps.StageFiles(filesToStage); err != nil {
 		return fmt.Errorf("failed to stage files: %w", err)
 	}
 
-	fmt.Println("✓ Staged files")
+	logging.Infof("✓ Staged %d files", len(filesToStage))
 
 	// Commit
-	commitMsg := `docs: Add generated documentation
+	commitMsg := cfg.Git.CommitMessage
+	if commitMsg == "" {
+		commitMsg = `docs: Add generated documentation
 
 🤖 Generated with DocBrown`
+	}
 
 	hash, err := gitOps.Commit(commitMsg)
 	if err != nil {
 		return fmt.Errorf("failed to commit: %w", err)
 	}
 
-	fmt.Printf("✓ Committed: %s\n", hash[:7])
+	logging.Infof("✓ Committed: %s", hash[:7])
 
 	// Push
 	if err := gitOps.PushDirect(token); err != nil {
 		return fmt.Errorf("failed to push: %w", err)
 	}
 
-	fmt.Println("✓ Pushed to remote")
-	fmt.Println()
-	fmt.Println("✅ Documentation pushed successfully")
+	logging.Infof("✓ Pushed to remote")
+	logging.Blank()
+	logging.Infof("✅ Documentation pushed successfully")
 
 	return nil
 }
@@ -143,59 +207,128 @@ func runPRCreation(gitOps *git.Operations, platformName, remoteURL, token string
 		branchName = fmt.Sprintf("%s-%s", cfg.Git.BranchPrefix, time.Now().Format("20060102"))
 	}
 
-	fmt.Printf("Creating branch: %s\n", branchName)
+	// The files written by the last generation
+	filesToStage, err := generatedFilesToStage(cfg)
+	if err != nil {
+		return err
+	}
+
+	// Render the body up front so a broken template fails before anything is pushed
+	body, err := prBodyText(cfg, prTemplateData{
+		RepoName:     repoName(remoteURL),
+		RemoteURL:    remoteURL,
+		Branch:       branchName,
+		BaseBranch:   cfg.Git.BaseBranch,
+		ChangedFiles: filesToStage,
+		FileCount:    len(filesToStage),
+	})
+	if err != nil {
+		return err
+	}
+
+	logging.Infof("Creating branch: %s", branchName)
 
 	// Create and checkout branch
 	if err := gitOps.CreateAndCheckoutBranch(branchName); err != nil {
 		return fmt.Errorf("failed to create branch: %w", err)
 	}
 
-	fmt.Println("✓ Created branch")
-
-	// Stage files
-	filesToStage := []string{
-		"docs/",
-		"mkdocs.yml",
-		"catalog-info.yaml",
-	}
+	logging.Infof("✓ Created branch")
 
 	if err := gitOps.StageFiles(filesToStage); err != nil {
 		return fmt.Errorf("failed to stage files: %w", err)
 	}
 
-	fmt.Println("✓ Staged files")
+	logging.Infof("✓ Staged %d files", len(filesToStage))
 
 	// Commit
-	commitMsg := `docs: Update documentation
+	commitMsg := cfg.Git.CommitMessage
+	if commitMsg == "" {
+		commitMsg = `docs: Update documentation
 
 🤖 Generated with DocBrown`
+	}
 
 	hash, err := gitOps.Commit(commitMsg)
 	if err != nil {
 		return fmt.Errorf("failed to commit: %w", err)
 	}
 
-	fmt.Printf("✓ Committed: %s\n", hash[:7])
+	logging.Infof("✓ Committed: %s", hash[:7])
 
 	// Push
 	if err := gitOps.Push(branchName, token); err != nil {
 		return fmt.Errorf("failed to push: %w", err)
 	}
 
-	fmt.Println("✓ Pushed to remote")
+	logging.Infof("✓ Pushed to remote")
 
 	// Create PR
-	fmt.Println()
-	fmt.Println("Creating pull request...")
+	logging.Blank()
+	logging.Infof("Creating pull request...")
 
-	platform, err := platforms.NewPlatform(platformName, remoteURL, token)
+	platform, err := platforms.NewPlatform(platformName, remoteURL, token, cfg.Git.APIBase)
 	if err != nil {
 		return fmt.Errorf("failed to create platform client: %w", err)
 	}
 
-	body := prBody
-	if body == "" {
-		body = `## Summary
+	// An open PR from the same branch is updated instead of duplicated
+	prURL, updated, err := platforms.CreateOrUpdatePR(platform, platforms.PROptions{
+		Title:      prTitle,
+		Body:       body,
+		Branch:     branchName,
+		BaseBranch: cfg.Git.BaseBranch,
+		Labels:     cfg.Git.PRLabels,
+		Draft:      cfg.Git.DraftPR,
+		Reviewers:  cfg.Git.Reviewers,
+		Assignees:  cfg.Git.Assignees,
+		AutoMerge:  cfg.Git.AutoMerge,
+	})
+
+	// The PR exists even if auto-merge couldn't be enabled
+	var autoMergeErr *platforms.AutoMergeError
+	if err != nil && !errors.As(err, &autoMergeErr) {
+		if updated {
+			return fmt.Errorf("failed to update PR: %w", err)
+		}
+		return fmt.Errorf("failed to create PR: %w", err)
+	}
+
+	if updated {
+		logging.Infof("✓ Updated existing PR: %s", prURL)
+	} else {
+		logging.Infof("✓ PR created: %s", prURL)
+	}
+	if autoMergeErr != nil {
+		logging.Warnf("⚠ Could not enable auto-merge: %s", autoMergeErr.Reason)
+		logging.Warnf("   The PR must be merged manually")
+	} else if cfg.Git.AutoMerge {
+		logging.Infof("✓ Auto-merge enabled; the PR merges once checks pass")
+	}
+
+	notification := lastRunSummary(cfg)
+	notification.Event = notify.EventPRCreated
+	if updated {
+		notification.Event = notify.EventPRUpdated
+	}
+	notification.Repo = repoName(remoteURL)
+	notification.PRURL = prURL
+	sendNotification(cfg, notification)
+
+	logging.Blank()
+	if updated {
+		logging.Infof("✅ Pull request updated successfully")
+	} else {
+		logging.Infof("✅ Pull request created successfully")
+	}
+	logging.Blank()
+	logging.Infof("Next: Review and merge the PR")
+
+	return nil
+}
+
+// defaultPRBody is rendered when neither --body nor git.pr_template is set
+const defaultPRBody = `## Summary
 
 This PR updates the documentation using DocBrown.
 
@@ -204,27 +337,150 @@ This PR updates the documentation using DocBrown.
 - Updated component documentation
 - Refreshed architecture overview
 - Updated getting started guide
-
+{{if .Validation}}
+{{.Validation}}{{end}}
 🤖 Generated with [DocBrown](https://github.com/docbrown/cli)`
+
+// prTemplateData is the data available to git.pr_template
+type prTemplateData struct {
+	RepoName       string
+	RemoteURL      string
+	Branch         string
+	BaseBranch     string
+	Components     []string
+	ComponentCount int
+	ChangedFiles   []string
+	FileCount      int
+	QualityScore   float64
+	MarkdownIssues int
+	BrokenLinks    int
+	Validation     string // markdown summary of the validation results
+	Generated      time.Time
+}
+
+// prBodyText returns the PR body: --body, then git.pr_template rendered
+// with data, then the default. The template is a file path, or inline
+// text when it spans lines or contains template actions.
+func prBodyText(cfg *config.Config, data prTemplateData) (string, error) {
+	if prBody != "" {
+		return prBody, nil
 	}
 
-	prURL, err := platform.CreatePR(platforms.PROptions{
-		Title:      prTitle,
-		Body:       body,
-		Branch:     branchName,
-		BaseBranch: cfg.Git.BaseBranch,
-		Labels:     cfg.Git.PRLabels,
-	})
+	name, text := "default", defaultPRBody
+	if cfg.Git.PRTemplate != "" {
+		name, text = "pr_template", cfg.Git.PRTemplate
+		if !strings.ContainsAny(text, "\n{") {
+			content, err := os.ReadFile(text)
+			if err != nil {
+				return "", fmt.Errorf("failed to read pr_template: %w", err)
+			}
+			name, text = text, string(content)
+		}
+	}
 
+	// Fill in what the last generation recorded
+	var generated time.Time
+	if manifest, err := cache.ReadManifest(filepath.Join(cfg.Cache.Dir, cache.ManifestFile)); err == nil {
+		data.Components = manifest.Components
+		data.ComponentCount = len(manifest.Components)
+		data.Generated = manifest.Generated
+		generated = manifest.Generated
+	}
+
+	v, results, err := prValidation(cfg, generated)
+	if err == nil {
+		data.QualityScore = results.QualityScore
+		data.MarkdownIssues = len(results.MarkdownErrors)
+		data.BrokenLinks = len(results.BrokenLinks)
+		data.Validation = v.FormatMarkdown(results)
+	} else {
+		logging.Warnf("⚠ Could not validate documentation for the PR body: %v", err)
+	}
+
+	body, err := template.RenderText(name, text, data)
 	if err != nil {
-		return fmt.Errorf("failed to create PR: %w", err)
+		return "", fmt.Errorf("invalid pr_template: %w", err)
 	}
+	return body, nil
+}
 
-	fmt.Printf("✓ PR created: %s\n", prURL)
-	fmt.Println()
-	fmt.Println("✅ Pull request created successfully")
-	fmt.Println()
-	fmt.Println("Next: Review and merge the PR")
+// prValidation returns the results saved by the last validate or auto run
+// if they describe the current generation, and validates afresh otherwise
+func prValidation(cfg *config.Config, generated time.Time) (*validator.Validator, *validator.ValidationResults, error) {
+	v := newValidator(cfg)
 
-	return nil
+	savedFor, results, err := validator.LoadResults(filepath.Join(cfg.Cache.Dir, cache.ValidationFile))
+	if err == nil && !generated.IsZero() && savedFor.Equal(generated) {
+		logging.Debugf("Using saved validation results for the PR body")
+		return v, results, nil
+	}
+
+	results, err = v.Validate()
+	return v, results, err
+}
+
+// repoName returns the repository name from a remote URL
+func repoName(remoteURL string) string {
+	return strings.TrimSuffix(path.Base(strings.TrimSuffix(remoteURL, "/")), ".git")
+}
+
+// mergeLabels appends extra labels to the configured ones, dropping empty
+// labels and duplicates
+func mergeLabels(configured, extra []string) []string {
+	var labels []string
+	seen := make(map[string]bool)
+	for _, label := range append(slices.Clone(configured), extra...) {
+		label = strings.TrimSpace(label)
+		if label == "" || seen[label] {
+			continue
+		}
+		seen[label] = true
+		labels = append(labels, label)
+	}
+	return labels
+}
+
+// generatedFilesToStage returns the files recorded by the last generation,
+// relative to the repository root. Files outside the repository (e.g. from
+// --output) or deleted since are skipped.
+func generatedFilesToStage(cfg *config.Config) ([]string, error) {
+	files, err := cache.LoadManifest(filepath.Join(cfg.Cache.Dir, cache.ManifestFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no generated files recorded (run 'docbrown generate' first)")
+		}
+		return nil, err
+	}
+
+	root, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+
+	var toStage []string
+	for _, file := range files {
+		rel := filepath.Clean(file)
+		if filepath.IsAbs(rel) {
+			if rel, err = filepath.Rel(root, rel); err != nil {
+				rel = ".."
+			}
+		}
+		if rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			logging.Warnf("⚠ Skipping %s (outside the repository)", file)
+			continue
+		}
+
+		if _, err := os.Stat(rel); err != nil {
+			logging.Warnf("⚠ Skipping %s (no longer exists)", file)
+			continue
+		}
+
+		toStage = append(toStage, filepath.ToSlash(rel))
+	}
+
+	if len(toStage) == 0 {
+		return nil, fmt.Errorf("none of the generated files can be staged (run 'docbrown generate' first)")
+	}
+
+	return toStage, nil
 }
@@ -1,7 +1,10 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"os/signal"
 
 	"github.com/spf13/cobra"
 
@@ -22,14 +25,24 @@ var providerStatusCmd = &cobra.Command{
 	RunE:  runProviderStatus,
 }
 
+var providerPullCmd = &cobra.Command{
+	Use:   "pull [model]",
+	Short: "Download an Ollama model",
+	Long: `Download a model into the configured Ollama server, showing progress.
+Defaults to llm.ollama.model when no model is given.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runProviderPull,
+}
+
 func init() {
 	rootCmd.AddCommand(providerCmd)
 	providerCmd.AddCommand(providerStatusCmd)
+	providerCmd.AddCommand(providerPullCmd)
 }
 
 func runProviderStatus(cmd *cobra.Command, args []string) error {
 	// Load configuration
-	cfgMgr := config.NewManager()
+	cfgMgr := config.NewManager(cfgFile)
 	cfg, err := cfgMgr.Load()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
@@ -61,22 +74,113 @@ func runProviderStatus(cmd *cobra.Command, args []string) error {
 	fmt.Printf("  Estimated cost: ~$0.50/repo\n")
 	fmt.Println()
 
+	// OpenAI
+	fmt.Println("OpenAI (Cloud):")
+	fmt.Printf("  Status: %s\n", status["openai"])
+	if cfg.LLM.OpenAI.APIKey != "" {
+		fmt.Println("  API Key: Configured")
+	} else {
+		fmt.Println("  API Key: Not configured")
+	}
+	fmt.Printf("  Model: %s\n", cfg.LLM.OpenAI.Model)
+	fmt.Printf("  Base URL: %s\n", cfg.LLM.OpenAI.BaseURL)
+	fmt.Println()
+
 	// Recommendation
 	if status["ollama"] == "available" {
 		fmt.Println("Recommendation: Using Ollama (free, available)")
 	} else if status["anthropic"] == "configured" {
 		fmt.Println("Recommendation: Using Anthropic (Ollama not available)")
+	} else if status["openai"] == "configured" {
+		fmt.Println("Recommendation: Using OpenAI (Ollama not available)")
 	} else {
 		fmt.Println("⚠ No provider available")
 		fmt.Println()
 		fmt.Println("To use Ollama:")
 		fmt.Println("  1. Install: https://ollama.ai/download")
-		fmt.Println("  2. Run: ollama pull qwen2.5-coder:latest")
+		fmt.Printf("  2. Run: docbrown provider pull %s\n", cfg.LLM.Ollama.Model)
 		fmt.Println()
 		fmt.Println("To use Anthropic:")
 		fmt.Println("  1. Get API key: https://console.anthropic.com")
 		fmt.Println("  2. Set: export ANTHROPIC_API_KEY=sk-...")
+		fmt.Println()
+		fmt.Println("To use OpenAI:")
+		fmt.Println("  1. Get API key: https://platform.openai.com/api-keys")
+		fmt.Println("  2. Set: export OPENAI_API_KEY=sk-...")
+	}
+
+	return nil
+}
+
+func runProviderPull(cmd *cobra.Command, args []string) error {
+	// Load configuration
+	cfgMgr := config.NewManager(cfgFile)
+	cfg, err := cfgMgr.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	model := cfg.LLM.Ollama.Model
+	if len(args) > 0 {
+		model = args[0]
 	}
 
+	provider := llm.NewOllamaProvider(
+		cfg.LLM.Ollama.Endpoint,
+		model,
+		cfg.LLM.Ollama.ContextSize,
+		cfg.LLM.Ollama.Timeout,
+	)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	fmt.Printf("Pulling %s from %s\n", model, cfg.LLM.Ollama.Endpoint)
+
+	status := ""
+	inProgress := false
+	err = provider.Pull(ctx, model, func(p llm.PullProgress) {
+		// Byte counts for one layer are redrawn in place
+		if p.Total > 0 {
+			fmt.Printf("\r  %s: %3d%% (%s / %s)", p.Status,
+				p.Completed*100/p.Total, formatBytes(p.Completed), formatBytes(p.Total))
+			inProgress = true
+			status = p.Status
+			return
+		}
+
+		if p.Status == status || p.Status == "success" {
+			return
+		}
+		if inProgress {
+			fmt.Println()
+			inProgress = false
+		}
+		fmt.Printf("  %s\n", p.Status)
+		status = p.Status
+	})
+	if inProgress {
+		fmt.Println()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to pull %s: %w", model, err)
+	}
+
+	fmt.Printf("✓ Pulled %s\n", model)
 	return nil
 }
+
+// formatBytes renders a byte count in human-readable units
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -1,16 +1,17 @@
 package cmd
 
 import (
-	"fmt"
-	"os"
-
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
+
+	"github.com/docbrown/cli/internal/logging"
 )
 
 var (
-	cfgFile string
-	verbose bool
+	cfgFile  string
+	verbose  bool
+	logLevel string
+	quiet    bool
+	jsonLogs bool
 )
 
 var rootCmd = &cobra.Command{
@@ -23,6 +24,14 @@ It analyzes code structure, generates comprehensive markdown documentation,
 and seamlessly integrates with Git workflows through automatic PR creation
 or direct push.`,
 	Version: "1.0.0",
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// --verbose is shorthand for debug unless a level was chosen explicitly
+		level := logLevel
+		if verbose && !cmd.Flags().Changed("log-level") {
+			level = "debug"
+		}
+		return logging.Setup(level, quiet, jsonLogs)
+	},
 }
 
 func Execute() error {
@@ -30,32 +39,9 @@ func Execute() error {
 }
 
 func init() {
-	cobra.OnInitialize(initConfig)
-
-	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is .docbrown.yaml)")
-	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output")
-}
-
-func initConfig() {
-	if cfgFile != "" {
-		viper.SetConfigFile(cfgFile)
-	} else {
-		// Look for config in current directory
-		viper.AddConfigPath(".")
-		viper.SetConfigName(".docbrown")
-		viper.SetConfigType("yaml")
-
-		// Also look for global config
-		home, err := os.UserHomeDir()
-		if err == nil {
-			viper.AddConfigPath(home + "/.docbrown")
-		}
-	}
-
-	viper.SetEnvPrefix("DOCBROWN")
-	viper.AutomaticEnv()
-
-	if err := viper.ReadInConfig(); err == nil && verbose {
-		fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
-	}
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file to use instead of .docbrown.yaml")
+	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output (same as --log-level debug)")
+	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "info", "log level (debug/info/warn/error)")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "only log errors")
+	rootCmd.PersistentFlags().BoolVar(&jsonLogs, "json-logs", false, "write logs as JSON lines for CI")
 }
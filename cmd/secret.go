@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docbrown/cli/internal/config"
+	"github.com/docbrown/cli/internal/git"
+	"github.com/docbrown/cli/internal/logging"
+)
+
+var secretCmd = &cobra.Command{
+	Use:   "secret",
+	Short: "Manage encrypted secrets",
+	Long:  `Store secrets in the config file encrypted, so it can be committed safely.`,
+}
+
+var secretSetPATCmd = &cobra.Command{
+	Use:   "set-pat",
+	Short: "Encrypt a personal access token into the config file",
+	Long: `Prompt for a personal access token and a passphrase, and store the token
+encrypted as git.encrypted_pat. Any plaintext git.pat is removed.
+
+The passphrase is read from ` + config.PassphraseEnv + ` if set. Set the same
+variable when running other commands and the token is decrypted
+automatically when git.pat is empty. Pipe the token on stdin for
+non-interactive use.`,
+	Example: `  docbrown secret set-pat
+  echo "$TOKEN" | ` + config.PassphraseEnv + `=... docbrown secret set-pat`,
+	Args: cobra.NoArgs,
+	RunE: runSecretSetPAT,
+}
+
+func init() {
+	rootCmd.AddCommand(secretCmd)
+	secretCmd.AddCommand(secretSetPATCmd)
+}
+
+func runSecretSetPAT(cmd *cobra.Command, args []string) error {
+	cfgMgr := config.NewManager(cfgFile)
+	if _, err := cfgMgr.Load(); err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+
+	pat, err := readSecret(reader, "Personal access token: ")
+	if err != nil {
+		return err
+	}
+	if pat == "" {
+		return fmt.Errorf("no token given")
+	}
+
+	passphrase := os.Getenv(config.PassphraseEnv)
+	if passphrase == "" {
+		if passphrase, err = readSecret(reader, "Passphrase: "); err != nil {
+			return err
+		}
+		confirmation, err := readSecret(reader, "Confirm passphrase: ")
+		if err != nil {
+			return err
+		}
+		if passphrase != confirmation {
+			return fmt.Errorf("passphrases do not match")
+		}
+	}
+
+	encrypted, err := config.EncryptPAT(pat, passphrase)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt PAT: %w", err)
+	}
+
+	if err := cfgMgr.Set("git.encrypted_pat", encrypted); err != nil {
+		return err
+	}
+	committedPAT(cfgMgr)
+	if err := cfgMgr.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	logging.Infof("✓ Stored encrypted PAT in git.encrypted_pat")
+	logging.Infof("   Set %s to use it", config.PassphraseEnv)
+	return nil
+}
+
+// readSecret reads one line from stdin. On a terminal the prompt is shown
+// and echo is turned off while typing, where stty is available.
+func readSecret(reader *bufio.Reader, prompt string) (string, error) {
+	interactive := false
+	if info, err := os.Stdin.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+		interactive = true
+		fmt.Fprint(os.Stderr, prompt)
+		if setEcho(false) == nil {
+			defer func() {
+				setEcho(true)
+				fmt.Fprintln(os.Stderr)
+			}()
+		}
+	}
+
+	line, err := reader.ReadString('\n')
+	if err != nil && (line == "" || interactive) {
+		return "", fmt.Errorf("failed to read %s: %w", strings.TrimSuffix(strings.ToLower(prompt), ": "), err)
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// setEcho turns terminal echo on or off
+func setEcho(on bool) error {
+	mode := "-echo"
+	if on {
+		mode = "echo"
+	}
+
+	stty := exec.Command("stty", mode)
+	stty.Stdin = os.Stdin
+	return stty.Run()
+}
+
+// committedPAT reports whether the repository config file holds a real
+// plaintext token and is tracked by git, and warns prominently if so
+func committedPAT(cfgMgr *config.Manager) bool {
+	path := cfgMgr.ConfigFile()
+	if !config.LooksLikePAT(config.FilePAT(path)) {
+		return false
+	}
+
+	gitOps, err := git.NewOperations("", "")
+	if err != nil || !gitOps.IsTracked(path) {
+		return false
+	}
+
+	logging.Blank()
+	logging.Warnf("⚠ ============================================================")
+	logging.Warnf("⚠ %s contains a plaintext git.pat and is tracked by git.", path)
+	logging.Warnf("⚠ Anyone with access to the repository can read the token.")
+	logging.Warnf("⚠ Revoke it, remove it from the file, and use GITHUB_TOKEN /")
+	logging.Warnf("⚠ GITLAB_TOKEN / BITBUCKET_TOKEN or 'docbrown secret set-pat'.")
+	logging.Warnf("⚠ ============================================================")
+	logging.Blank()
+	return true
+}
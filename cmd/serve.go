@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docbrown/cli/internal/config"
+	"github.com/docbrown/cli/internal/logging"
+	"github.com/docbrown/cli/internal/preview"
+)
+
+var (
+	servePort   int
+	serveNoOpen bool
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Preview generated documentation locally",
+	Long: `Serve the generated documentation over HTTP for local preview.
+
+If mkdocs is installed and mkdocs.yml exists, this runs 'mkdocs serve'.
+Otherwise a built-in server renders the markdown in the output
+directory to HTML on the fly.`,
+	RunE: runServe,
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().IntVar(&servePort, "port", 8000, "port to listen on")
+	serveCmd.Flags().BoolVar(&serveNoOpen, "no-open", false, "don't open the browser")
+}
+
+func runServe(cmd *cobra.Command, args []string) error {
+	// Load configuration
+	cfgMgr := config.NewManager(cfgFile)
+	cfg, err := cfgMgr.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	outputDir := cfg.Documentation.OutputDir
+	if info, err := os.Stat(outputDir); err != nil || !info.IsDir() {
+		return fmt.Errorf("output directory %s not found (run 'docbrown generate' first)", outputDir)
+	}
+
+	addr := net.JoinHostPort("127.0.0.1", strconv.Itoa(servePort))
+	url := "http://" + addr + "/"
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if mkdocs, err := exec.LookPath("mkdocs"); err == nil {
+		if _, err := os.Stat("mkdocs.yml"); err == nil {
+			logging.Infof("📄 Serving with mkdocs at %s", url)
+			openAfterStart(url)
+			return runMkdocs(ctx, mkdocs, addr)
+		}
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	server := &http.Server{Handler: preview.NewServer(outputDir)}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	logging.Infof("📄 Serving %s at %s (Ctrl+C to stop)", outputDir, url)
+	openAfterStart(url)
+
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("server failed: %w", err)
+	}
+
+	logging.Infof("✓ Server stopped")
+	return nil
+}
+
+// runMkdocs runs 'mkdocs serve' until it exits or the context is cancelled
+func runMkdocs(ctx context.Context, mkdocs, addr string) error {
+	mkdocsCmd := exec.CommandContext(ctx, mkdocs, "serve", "--dev-addr", addr)
+	mkdocsCmd.Stdout = os.Stdout
+	mkdocsCmd.Stderr = os.Stderr
+
+	if err := mkdocsCmd.Run(); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("mkdocs serve failed: %w", err)
+	}
+	return nil
+}
+
+// openAfterStart opens the browser shortly after the server starts, unless
+// disabled with --no-open
+func openAfterStart(url string) {
+	if serveNoOpen {
+		return
+	}
+
+	go func() {
+		time.Sleep(500 * time.Millisecond)
+		if err := openBrowser(url); err != nil {
+			logging.Warnf("⚠ Could not open browser: %v", err)
+		}
+	}()
+}
+
+// openBrowser opens url in the default browser
+func openBrowser(url string) error {
+	var browserCmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		browserCmd = exec.Command("open", url)
+	case "windows":
+		browserCmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		browserCmd = exec.Command("xdg-open", url)
+	}
+	return browserCmd.Start()
+}
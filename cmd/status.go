@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docbrown/cli/internal/cache"
+	"github.com/docbrown/cli/internal/config"
+	"github.com/docbrown/cli/internal/llm"
+	"github.com/docbrown/cli/internal/validator"
+)
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Summarize the project's DocBrown setup",
+	Long: `Show, in one place, the config file in use, the active LLM provider and
+whether it is reachable, the cache, the generated docs and when they were
+last generated and validated. A good first command in a new clone.`,
+	Args: cobra.NoArgs,
+	RunE: runStatus,
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+}
+
+func runStatus(cmd *cobra.Command, args []string) error {
+	cfgMgr := config.NewManager(cfgFile)
+	cfg, err := cfgMgr.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	fmt.Println("DocBrown Status:")
+	fmt.Println()
+
+	// Config
+	if _, err := os.Stat(cfgMgr.ConfigFile()); err == nil {
+		fmt.Printf("  Config:      ✓ %s\n", cfgMgr.ConfigFile())
+	} else {
+		fmt.Printf("  Config:      ✗ no %s (using defaults; run 'docbrown init')\n", cfgMgr.ConfigFile())
+	}
+
+	// Provider
+	fmt.Printf("  Provider:    %s\n", providerSummary(cfg))
+
+	// Cache
+	fmt.Printf("  Cache:       %s\n", cacheSummary(cfg))
+
+	// Docs
+	manifest, manifestErr := cache.ReadManifest(filepath.Join(cfg.Cache.Dir, cache.ManifestFile))
+	fmt.Printf("  Docs:        %s\n", docsSummary(cfg.Documentation.OutputDir))
+	if manifestErr == nil {
+		fmt.Printf("  Generated:   %s (%d files, %d components)\n",
+			formatAge(manifest.Generated), len(manifest.Files), len(manifest.Components))
+	} else {
+		fmt.Println("  Generated:   never (run 'docbrown generate')")
+	}
+
+	// Validation
+	if savedFor, results, err := validator.LoadResults(filepath.Join(cfg.Cache.Dir, cache.ValidationFile)); err == nil {
+		line := fmt.Sprintf("%.1f/10.0 %s", results.QualityScore, validator.Grade(results.QualityScore))
+		if manifestErr == nil && !savedFor.Equal(manifest.Generated) {
+			line += " (for an earlier generation; run 'docbrown validate')"
+		}
+		fmt.Printf("  Quality:     %s\n", line)
+	} else {
+		fmt.Println("  Quality:     not validated (run 'docbrown validate')")
+	}
+
+	return nil
+}
+
+// providerSummary describes the configured provider and whether it can be used
+func providerSummary(cfg *config.Config) string {
+	if cfg.LLM.Provider == "mock" {
+		return "mock (offline canned responses)"
+	}
+
+	status := llm.CheckProviderStatus(cfg)
+	ready := func(name string) bool {
+		return status[name] == "available" || status[name] == "configured"
+	}
+	model := func(name string) string {
+		if cfg.LLM.Model != "" {
+			return cfg.LLM.Model
+		}
+		switch name {
+		case "ollama":
+			return cfg.LLM.Ollama.Model
+		case "anthropic":
+			return cfg.LLM.Anthropic.Model
+		case "openai":
+			return cfg.LLM.OpenAI.Model
+		}
+		return ""
+	}
+
+	name := cfg.LLM.Provider
+	prefix := ""
+	if name == "auto" {
+		// Same order as provider detection
+		name = ""
+		for _, candidate := range []string{"ollama", "anthropic", "openai"} {
+			if ready(candidate) {
+				name = candidate
+				break
+			}
+		}
+		if name == "" {
+			return "✗ auto: no provider available (see 'docbrown provider status')"
+		}
+		prefix = "auto → "
+	}
+
+	if _, ok := status[name]; !ok {
+		return fmt.Sprintf("✗ unknown provider %q", name)
+	}
+
+	mark := "✓"
+	if !ready(name) {
+		mark = "✗"
+	}
+	return fmt.Sprintf("%s %s%s (%s) - %s", mark, prefix, name, model(name), status[name])
+}
+
+// cacheSummary describes the cache contents
+func cacheSummary(cfg *config.Config) string {
+	if !cfg.Cache.Enabled {
+		return "disabled"
+	}
+
+	cacheMgr := cache.NewManager(cfg.Cache.Dir+"/cache.yaml", cfg.Cache.Enabled, cfg.Cache.TTL)
+	if err := cacheMgr.Load(); err != nil {
+		return fmt.Sprintf("not initialized (%s)", cfg.Cache.Dir)
+	}
+
+	stats := cacheMgr.GetStats()
+	components, _ := stats["components"].(int)
+	if components == 0 {
+		return fmt.Sprintf("empty (%s)", cfg.Cache.Dir)
+	}
+	unchanged, _ := stats["unchanged"].(int)
+	stale, _ := stats["stale"].(int)
+	return fmt.Sprintf("%d components (%d up to date, %d stale)", components, unchanged, stale)
+}
+
+// docsSummary describes the output directory
+func docsSummary(dir string) string {
+	if _, err := os.Stat(dir); err != nil {
+		return fmt.Sprintf("✗ %s does not exist", dir)
+	}
+
+	count := 0
+	filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err == nil && !entry.IsDir() {
+			count++
+		}
+		return nil
+	})
+	return fmt.Sprintf("✓ %s (%d files)", dir, count)
+}
+
+// formatAge formats a time and how long ago it was
+func formatAge(t time.Time) string {
+	age := time.Since(t)
+	var ago string
+	switch {
+	case age < time.Minute:
+		ago = "just now"
+	case age < time.Hour:
+		ago = fmt.Sprintf("%dm ago", int(age.Minutes()))
+	case age < 48*time.Hour:
+		ago = fmt.Sprintf("%dh ago", int(age.Hours()))
+	default:
+		ago = fmt.Sprintf("%dd ago", int(age.Hours()/24))
+	}
+	return fmt.Sprintf("%s, %s", t.Local().Format("2006-01-02 15:04"), ago)
+}
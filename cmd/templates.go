@@ -2,9 +2,14 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
 
+	"github.com/docbrown/cli/internal/config"
+	"github.com/docbrown/cli/internal/git"
 	"github.com/docbrown/cli/internal/template"
 )
 
@@ -21,6 +26,16 @@ var templatesListCmd = &cobra.Command{
 	RunE:  runTemplatesList,
 }
 
+var templatesValidateCmd = &cobra.Command{
+	Use:   "validate <name>",
+	Short: "Check a template for errors",
+	Long: `Check a template for errors before generating with it: missing or
+invalid template files, unknown foreach collections, and output paths or
+templates that reference fields which don't exist.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTemplatesValidate,
+}
+
 var templatesShowCmd = &cobra.Command{
 	Use:   "show <name>",
 	Short: "Show template details",
@@ -29,14 +44,56 @@ var templatesShowCmd = &cobra.Command{
 	RunE:  runTemplatesShow,
 }
 
+var templatesAddCmd = &cobra.Command{
+	Use:   "add <git-url|path>",
+	Short: "Install a template from a git repository or local directory",
+	Long: `Install a template into ~/.docbrown/templates so every repository can use
+it. The source is a git URL or a local directory containing template.yaml.
+Use --path when the template is in a subdirectory of the repository.
+
+Templates in the repository's template directory take precedence over
+installed ones of the same name.`,
+	Example: `  docbrown templates add https://github.com/acme/docbrown-templates.git --path acme
+  docbrown templates add ../shared/templates/acme --name acme`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTemplatesAdd,
+}
+
+var (
+	templatesAddName  string
+	templatesAddPath  string
+	templatesAddRef   string
+	templatesAddForce bool
+)
+
 func init() {
 	rootCmd.AddCommand(templatesCmd)
 	templatesCmd.AddCommand(templatesListCmd)
 	templatesCmd.AddCommand(templatesShowCmd)
+	templatesCmd.AddCommand(templatesValidateCmd)
+	templatesCmd.AddCommand(templatesAddCmd)
+
+	templatesAddCmd.Flags().StringVar(&templatesAddName, "name", "", "name to install the template as (default: the directory or repository name)")
+	templatesAddCmd.Flags().StringVar(&templatesAddPath, "path", "", "subdirectory of the source that contains template.yaml")
+	templatesAddCmd.Flags().StringVar(&templatesAddRef, "ref", "", "branch or tag to fetch (git sources only)")
+	templatesAddCmd.Flags().BoolVar(&templatesAddForce, "force", false, "replace an installed template of the same name")
+}
+
+// templateEngine creates a template engine searching the configured
+// template directories
+func templateEngine() (*template.Engine, error) {
+	cfg, err := config.NewManager(cfgFile).Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	return template.NewEngine(cfg.Documentation.TemplateSearchPaths()...), nil
 }
 
 func runTemplatesList(cmd *cobra.Command, args []string) error {
-	engine := template.NewEngine("templates")
+	engine, err := templateEngine()
+	if err != nil {
+		return err
+	}
 
 	templates, err := engine.ListTemplates()
 	if err != nil {
@@ -51,8 +108,8 @@ func runTemplatesList(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	for _, name := range templates {
-		fmt.Printf("  - %s\n", name)
+	for _, tmpl := range templates {
+		fmt.Printf("  - %s (%s)\n", tmpl.Name, tmpl.Location)
 	}
 
 	fmt.Println()
@@ -64,7 +121,10 @@ func runTemplatesList(cmd *cobra.Command, args []string) error {
 func runTemplatesShow(cmd *cobra.Command, args []string) error {
 	name := args[0]
 
-	engine := template.NewEngine("templates")
+	engine, err := templateEngine()
+	if err != nil {
+		return err
+	}
 
 	tmpl, err := engine.LoadTemplate(name)
 	if err != nil {
@@ -74,6 +134,7 @@ func runTemplatesShow(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Template: %s\n", tmpl.Name)
 	fmt.Printf("Version: %s\n", tmpl.Version)
 	fmt.Printf("Description: %s\n", tmpl.Description)
+	fmt.Printf("Location: %s\n", tmpl.Path)
 	fmt.Println()
 
 	fmt.Println("Files:")
@@ -86,3 +147,93 @@ func runTemplatesShow(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+func runTemplatesValidate(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	engine, err := templateEngine()
+	if err != nil {
+		return err
+	}
+
+	problems, err := engine.ValidateTemplate(name)
+	if err != nil {
+		return fmt.Errorf("failed to validate template: %w", err)
+	}
+
+	if len(problems) == 0 {
+		fmt.Printf("✓ Template %s is valid\n", name)
+		return nil
+	}
+
+	fmt.Printf("✗ Template %s has %d problems:\n", name, len(problems))
+	for _, problem := range problems {
+		fmt.Printf("  - %s\n", problem)
+	}
+
+	return fmt.Errorf("template %s is invalid", name)
+}
+
+func runTemplatesAdd(cmd *cobra.Command, args []string) error {
+	source := args[0]
+
+	root := source
+	if isGitSource(source) {
+		tmp, err := os.MkdirTemp("", "docbrown-template-")
+		if err != nil {
+			return fmt.Errorf("failed to create temporary directory: %w", err)
+		}
+		defer os.RemoveAll(tmp)
+
+		fmt.Printf("Fetching %s\n", source)
+		if err := git.Clone(source, templatesAddRef, tmp); err != nil {
+			return fmt.Errorf("failed to fetch template: %w", err)
+		}
+		root = tmp
+	} else if templatesAddRef != "" {
+		return fmt.Errorf("--ref only applies to git sources")
+	}
+
+	dir := filepath.Join(root, templatesAddPath)
+
+	name := templatesAddName
+	if name == "" {
+		name = templateSourceName(source, templatesAddPath)
+		if !template.ValidName(name) {
+			return fmt.Errorf("cannot derive a template name from %s; use --name", source)
+		}
+	}
+
+	dest, err := template.Install(dir, name, templatesAddForce)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Installed template %s in %s\n", name, dest)
+	fmt.Printf("Use: docbrown generate --template %s\n", name)
+
+	return nil
+}
+
+// isGitSource reports whether a templates add source is a git URL rather
+// than a local directory
+func isGitSource(source string) bool {
+	if info, err := os.Stat(source); err == nil && info.IsDir() {
+		return false
+	}
+	return strings.Contains(source, "://") || strings.HasPrefix(source, "git@") || strings.HasSuffix(source, ".git")
+}
+
+// templateSourceName derives a template name from the subdirectory, or
+// else the last element of the source path or URL
+func templateSourceName(source, subdir string) string {
+	if subdir != "" {
+		return filepath.Base(filepath.Clean(subdir))
+	}
+
+	source = strings.TrimRight(source, "/")
+	if i := strings.LastIndexAny(source, "/:"); i >= 0 {
+		source = source[i+1:]
+	}
+	return strings.TrimSuffix(source, ".git")
+}
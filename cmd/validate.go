@@ -3,15 +3,22 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/docbrown/cli/internal/cache"
 	"github.com/docbrown/cli/internal/config"
+	"github.com/docbrown/cli/internal/logging"
+	"github.com/docbrown/cli/internal/template"
 	"github.com/docbrown/cli/internal/validator"
 )
 
 var (
-	validateStrict bool
+	validateStrict        bool
+	validateExternalLinks bool
+	validateFormat        string
 )
 
 var validateCmd = &cobra.Command{
@@ -26,11 +33,13 @@ func init() {
 	rootCmd.AddCommand(validateCmd)
 
 	validateCmd.Flags().BoolVar(&validateStrict, "strict", false, "fail on warnings")
+	validateCmd.Flags().BoolVar(&validateExternalLinks, "check-external-links", false, "also check http(s) links (slow, needs network)")
+	validateCmd.Flags().StringVar(&validateFormat, "format", "text", "output format (text/json/sarif)")
 }
 
 func runValidate(cmd *cobra.Command, args []string) error {
 	// Load configuration
-	cfgMgr := config.NewManager()
+	cfgMgr := config.NewManager(cfgFile)
 	cfg, err := cfgMgr.Load()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
@@ -41,11 +50,26 @@ func runValidate(cmd *cobra.Command, args []string) error {
 		cfg.Quality.StrictMode = true
 	}
 
-	fmt.Println("Validating documentation...")
-	fmt.Println()
+	switch validateFormat {
+	case "text", "json", "sarif":
+	default:
+		return fmt.Errorf("unsupported format: %s (use text, json or sarif)", validateFormat)
+	}
+
+	// Keep stdout machine-readable for json/sarif
+	out := os.Stdout
+	if validateFormat != "text" {
+		out = os.Stderr
+	}
+
+	fmt.Fprintln(out, "Validating documentation...")
+	fmt.Fprintln(out)
 
 	// Create validator
-	v := validator.NewValidator(cfg.Documentation.OutputDir, cfg.Quality.StrictMode)
+	v := newValidator(cfg)
+	if validateExternalLinks {
+		v.EnableExternalLinks(10 * time.Second)
+	}
 
 	// Validate
 	results, err := v.Validate()
@@ -53,12 +77,34 @@ func runValidate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("validation failed: %w", err)
 	}
 
+	// Keep the results for the PR description
+	if manifest, err := cache.ReadManifest(filepath.Join(cfg.Cache.Dir, cache.ManifestFile)); err == nil {
+		if err := validator.SaveResults(filepath.Join(cfg.Cache.Dir, cache.ValidationFile), manifest.Generated, results); err != nil {
+			logging.Warnf("⚠ Failed to save validation results: %v", err)
+		}
+	}
+
 	// Display results
-	fmt.Println(v.FormatResults(results))
+	switch validateFormat {
+	case "json":
+		data, err := v.FormatJSON(results)
+		if err != nil {
+			return fmt.Errorf("failed to format results: %w", err)
+		}
+		fmt.Println(string(data))
+	case "sarif":
+		data, err := v.FormatSARIF(results)
+		if err != nil {
+			return fmt.Errorf("failed to format results: %w", err)
+		}
+		fmt.Println(string(data))
+	default:
+		fmt.Println(v.FormatResults(results))
+	}
 
 	// Check minimum score
 	if results.QualityScore < cfg.Quality.MinScore {
-		fmt.Printf("\n⚠ Quality score %.1f is below minimum %.1f\n",
+		fmt.Fprintf(out, "\n⚠ Quality score %.1f is below minimum %.1f\n",
 			results.QualityScore, cfg.Quality.MinScore)
 
 		if cfg.Quality.StrictMode {
@@ -69,14 +115,26 @@ func runValidate(cmd *cobra.Command, args []string) error {
 	// Fail in strict mode if there are errors
 	if cfg.Quality.StrictMode {
 		if len(results.MarkdownErrors) > 0 || len(results.BrokenLinks) > 0 || !results.CatalogValid {
-			fmt.Println("\n✗ Validation failed (strict mode)")
+			fmt.Fprintln(out, "\n✗ Validation failed (strict mode)")
 			os.Exit(1)
 		}
 	}
 
 	if results.QualityScore >= cfg.Quality.MinScore {
-		fmt.Println("\n✅ Documentation quality meets requirements")
+		fmt.Fprintln(out, "\n✅ Documentation quality meets requirements")
 	}
 
 	return nil
 }
+
+// newValidator creates a validator for the output directory, set up for the
+// configured template's layout
+func newValidator(cfg *config.Config) *validator.Validator {
+	v := validator.NewValidator(cfg.Documentation.OutputDir, cfg.Quality.StrictMode)
+	tmpl, _ := template.NewEngine(cfg.Documentation.TemplateSearchPaths()...).LoadTemplate(cfg.Documentation.Template)
+	v.SetLayout(validator.LayoutForTemplate(tmpl))
+	if cfg.Quality.SpellCheck {
+		v.EnableSpellCheck(cfg.Quality.SpellAllowlist)
+	}
+	return v
+}
@@ -2,46 +2,106 @@ package analyzer
 
 import (
 	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/docbrown/cli/internal/logging"
 )
 
 // Analyzer is the main analyzer that orchestrates scanning and detection
 type Analyzer struct {
 	rootPath        string
+	includePatterns []string
 	excludePatterns []string
 	scanner         *Scanner
 	detector        *Detector
 	metadata        *MetadataExtractor
+
+	// targetPaths and targetNames restrict the result to matching
+	// components. Empty means every component.
+	targetPaths []string
+	targetNames []string
+
+	external *externalAnalyzer // nil when no external analyzer is configured
 }
 
 // NewAnalyzer creates a new analyzer
-func NewAnalyzer(rootPath string, excludePatterns []string) *Analyzer {
+func NewAnalyzer(rootPath string, includePatterns, excludePatterns []string) *Analyzer {
+	detector := NewDetector(rootPath)
+	detector.SetExcludePatterns(excludePatterns)
+
 	return &Analyzer{
 		rootPath:        rootPath,
+		includePatterns: includePatterns,
 		excludePatterns: excludePatterns,
-		scanner:         NewScanner(rootPath, excludePatterns),
-		detector:        NewDetector(rootPath),
+		scanner:         NewScanner(rootPath, includePatterns, excludePatterns),
+		detector:        detector,
 		metadata:        NewMetadataExtractor(rootPath),
 	}
 }
 
+// SetSensitivePatterns sets patterns for files that must never be scanned
+func (a *Analyzer) SetSensitivePatterns(patterns []string) {
+	a.scanner.SetSensitivePatterns(patterns)
+}
+
+// SetGeneratedPatterns skips generated and minified files in scanning and
+// component file lists
+func (a *Analyzer) SetGeneratedPatterns(patterns []string, inspect bool) {
+	a.scanner.SetGeneratedPatterns(patterns, inspect)
+	a.detector.SetGeneratedPatterns(patterns, inspect)
+}
+
+// SetRespectGitignore makes scanning honor the repository's .gitignore
+func (a *Analyzer) SetRespectGitignore(respect bool) {
+	a.scanner.SetRespectGitignore(respect)
+}
+
+// SetIncludeDevDependencies includes development-only dependencies in the analysis
+func (a *Analyzer) SetIncludeDevDependencies(include bool) {
+	a.metadata.SetIncludeDevDependencies(include)
+}
+
+// SetTargets restricts analysis results to the components under any of paths
+// or named in names. The whole repository is still scanned, so component
+// names (and the cache keys derived from them) don't change.
+func (a *Analyzer) SetTargets(paths, names []string) {
+	a.targetPaths = paths
+	a.targetNames = names
+}
+
+// Excludes reports whether the exclude patterns or ignore files rule out
+// path, given relative to the repository root. Files outside the include
+// patterns aren't excluded, since detection still reads manifests such as
+// go.mod and Dockerfile. Ignore files are read by Analyze, so call it first.
+func (a *Analyzer) Excludes(path string, isDir bool) bool {
+	return a.scanner.shouldExclude(filepath.Join(a.rootPath, path), isDir)
+}
+
 // Analyze performs a full analysis of the repository
 func (a *Analyzer) Analyze() (*RepoStructure, error) {
 	// Step 1: Scan the repository
-	fmt.Println("Scanning repository...")
+	logging.Infof("Scanning repository...")
 	structure, err := a.scanner.Scan()
 	if err != nil {
 		return nil, fmt.Errorf("scan failed: %w", err)
 	}
+	if structure.SensitiveFiles > 0 {
+		logging.Infof("Skipped %d sensitive files", structure.SensitiveFiles)
+	}
+	if structure.GeneratedFiles > 0 {
+		logging.Infof("Skipped %d generated or minified files", structure.GeneratedFiles)
+	}
 
 	// Step 2: Detect components
-	fmt.Println("Detecting components...")
+	logging.Infof("Detecting components...")
 	components, err := a.detector.DetectComponents(structure)
 	if err != nil {
 		return nil, fmt.Errorf("component detection failed: %w", err)
 	}
 
 	// Step 3: Extract metadata for each component
-	fmt.Println("Extracting metadata...")
+	logging.Infof("Extracting metadata...")
 	for i := range components {
 		// Extract dependencies
 		components[i].Dependencies = a.metadata.ExtractDependencies(&components[i])
@@ -50,13 +110,81 @@ func (a *Analyzer) Analyze() (*RepoStructure, error) {
 		a.metadata.ExtractMetadata(&components[i])
 	}
 
+	// Step 4: Merge in the external analyzer's components
+	if a.external != nil {
+		logging.Infof("Running external analyzer...")
+		result, err := a.external.run(a.rootPath)
+		if err != nil {
+			return nil, fmt.Errorf("external analyzer: %w", err)
+		}
+		components = a.mergeExternal(structure, components, result)
+		logging.Infof("External analyzer reported %d components", len(result.Components))
+	}
+
+	// Step 5: Link components that import each other
+	a.metadata.ExtractInternalDependencies(components)
+
+	// Step 6: Keep only the targeted components
+	structure.AllComponents = components
+	if len(a.targetPaths) > 0 || len(a.targetNames) > 0 {
+		targeted := a.filterTargets(components)
+		if len(targeted) == 0 {
+			return nil, fmt.Errorf("no components match %s", a.describeTargets())
+		}
+		logging.Infof("Targeting %d of %d components (%s)", len(targeted), len(components), a.describeTargets())
+		components = targeted
+	}
+
 	structure.Components = components
 
-	fmt.Printf("Found %d components\n", len(components))
+	logging.Infof("Found %d components", len(components))
 	for _, comp := range components {
-		fmt.Printf("  - %s (%s, %s) - %d dependencies, %d endpoints\n",
+		logging.Infof("  - %s (%s, %s) - %d dependencies, %d endpoints",
 			comp.Name, comp.Type, comp.Language, len(comp.Dependencies), len(comp.Endpoints))
 	}
 
 	return structure, nil
 }
+
+// filterTargets returns the components selected by SetTargets
+func (a *Analyzer) filterTargets(components []Component) []Component {
+	var targeted []Component
+	for _, comp := range components {
+		if a.isTarget(comp) {
+			targeted = append(targeted, comp)
+		}
+	}
+	return targeted
+}
+
+// isTarget reports whether a component is named, lies under a target path,
+// or contains one (so a path inside a service selects that service)
+func (a *Analyzer) isTarget(comp Component) bool {
+	for _, name := range a.targetNames {
+		if comp.Name == name {
+			return true
+		}
+	}
+
+	dir := filepath.ToSlash(filepath.Clean(comp.Path))
+	for _, target := range a.targetPaths {
+		target = filepath.ToSlash(filepath.Clean(target))
+		if target == "." || dir == target || strings.HasPrefix(dir, target+"/") ||
+			(dir != "." && strings.HasPrefix(target, dir+"/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// describeTargets formats the targets for messages
+func (a *Analyzer) describeTargets() string {
+	var parts []string
+	if len(a.targetPaths) > 0 {
+		parts = append(parts, "path "+strings.Join(a.targetPaths, ", "))
+	}
+	if len(a.targetNames) > 0 {
+		parts = append(parts, "component "+strings.Join(a.targetNames, ", "))
+	}
+	return strings.Join(parts, "; ")
+}
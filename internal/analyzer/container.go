@@ -0,0 +1,275 @@
+package analyzer
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// composeFileNames are the Compose file names recognised in a component directory
+var composeFileNames = []string{"compose.yaml", "compose.yml", "docker-compose.yaml", "docker-compose.yml"}
+
+// envReference matches $VAR, ${VAR}, ${VAR:-default} and ${VAR-default}
+var envReference = regexp.MustCompile(`\$(?:\{([A-Za-z_][A-Za-z0-9_]*)(?::?-([^}]*))?\}|([A-Za-z_][A-Za-z0-9_]*))`)
+
+// composeFile is the subset of a Compose file we use
+type composeFile struct {
+	Services map[string]composeService `yaml:"services"`
+}
+
+type composeService struct {
+	Image  string      `yaml:"image"`
+	Build  yaml.Node   `yaml:"build"`
+	Ports  []yaml.Node `yaml:"ports"`
+	Expose []yaml.Node `yaml:"expose"`
+}
+
+// ExtractContainer reads a component's Dockerfile and Compose file to find
+// the ports it listens on, its base image and its published container image
+func (m *MetadataExtractor) ExtractContainer(comp *Component) {
+	var ports []int
+
+	if content, err := os.ReadFile(filepath.Join(comp.Path, "Dockerfile")); err == nil {
+		var exposed []int
+		comp.BaseImage, exposed = parseDockerfile(string(content))
+		ports = append(ports, exposed...)
+	}
+
+	for _, name := range composeFileNames {
+		content, err := os.ReadFile(filepath.Join(comp.Path, name))
+		if err != nil {
+			continue
+		}
+
+		var compose composeFile
+		if err := yaml.Unmarshal(content, &compose); err != nil {
+			continue
+		}
+
+		if svc, ok := compose.service(comp.Name); ok {
+			if svc.Image != "" {
+				comp.Image = expandEnv(svc.Image, nil)
+			}
+			ports = append(ports, svc.containerPorts()...)
+		}
+		break
+	}
+
+	comp.Ports = uniquePorts(ports)
+}
+
+// parseDockerfile returns the final stage's base image and the exposed ports.
+// Variables are resolved from ARG and ENV defaults declared earlier in the file.
+func parseDockerfile(content string) (string, []int) {
+	vars := make(map[string]string)
+	stages := make(map[string]string) // stage alias -> image
+	baseImage := ""
+	var ports []int
+
+	for _, line := range dockerfileInstructions(content) {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		args := fields[1:]
+
+		switch strings.ToUpper(fields[0]) {
+		case "FROM":
+			// Skip flags like --platform=linux/amd64
+			for len(args) > 0 && strings.HasPrefix(args[0], "--") {
+				args = args[1:]
+			}
+			if len(args) == 0 {
+				continue
+			}
+
+			image := expandEnv(args[0], vars)
+			if parent, ok := stages[image]; ok {
+				image = parent // FROM an earlier stage
+			}
+			if len(args) >= 3 && strings.EqualFold(args[1], "as") {
+				stages[args[2]] = image
+			}
+			baseImage = image
+			ports = nil // only the final stage's EXPOSE applies
+
+		case "ARG", "ENV":
+			parseDockerVars(strings.ToUpper(fields[0]), args, vars)
+
+		case "EXPOSE":
+			for _, arg := range args {
+				if port, ok := parsePort(expandEnv(arg, vars)); ok {
+					ports = append(ports, port)
+				}
+			}
+		}
+	}
+
+	return baseImage, ports
+}
+
+// dockerfileInstructions joins continuation lines and drops comments
+func dockerfileInstructions(content string) []string {
+	var instructions []string
+	var current strings.Builder
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasSuffix(line, "\\") {
+			current.WriteString(strings.TrimSuffix(line, "\\") + " ")
+			continue
+		}
+
+		current.WriteString(line)
+		instructions = append(instructions, current.String())
+		current.Reset()
+	}
+
+	if current.Len() > 0 {
+		instructions = append(instructions, current.String())
+	}
+	return instructions
+}
+
+// parseDockerVars records ARG/ENV defaults ("KEY=value" pairs, or the legacy
+// "ENV KEY value" form)
+func parseDockerVars(instruction string, args []string, vars map[string]string) {
+	if instruction == "ENV" && len(args) >= 2 && !strings.Contains(args[0], "=") {
+		vars[args[0]] = expandEnv(strings.Join(args[1:], " "), vars)
+		return
+	}
+
+	for _, arg := range args {
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok {
+			continue // ARG without a default
+		}
+		vars[key] = expandEnv(strings.Trim(value, `"'`), vars)
+	}
+}
+
+// service finds the Compose service for a component: the one named after
+// it, the one built from the component directory, or the only service
+func (c composeFile) service(name string) (composeService, bool) {
+	if svc, ok := c.Services[name]; ok {
+		return svc, true
+	}
+
+	for _, svc := range c.Services {
+		if svc.buildContext() == "." {
+			return svc, true
+		}
+	}
+
+	if len(c.Services) == 1 {
+		for _, svc := range c.Services {
+			return svc, true
+		}
+	}
+
+	return composeService{}, false
+}
+
+// buildContext returns the build context of a service ("build: ." or "build: {context: .}")
+func (s composeService) buildContext() string {
+	switch s.Build.Kind {
+	case yaml.ScalarNode:
+		return filepath.Clean(s.Build.Value)
+	case yaml.MappingNode:
+		var build struct {
+			Context string `yaml:"context"`
+		}
+		if err := s.Build.Decode(&build); err == nil && build.Context != "" {
+			return filepath.Clean(build.Context)
+		}
+	}
+	return ""
+}
+
+// containerPorts returns the ports the service listens on inside the container
+func (s composeService) containerPorts() []int {
+	var ports []int
+
+	for _, node := range s.Ports {
+		var target string
+		switch node.Kind {
+		case yaml.ScalarNode:
+			// "8080", "8080:80", "127.0.0.1:8080:80/tcp", "${PORT:-3000}:3000"
+			spec := expandEnv(node.Value, nil)
+			target = spec[strings.LastIndex(spec, ":")+1:]
+		case yaml.MappingNode:
+			var long struct {
+				Target string `yaml:"target"`
+			}
+			if err := node.Decode(&long); err != nil {
+				continue
+			}
+			target = expandEnv(long.Target, nil)
+		}
+
+		if port, ok := parsePort(target); ok {
+			ports = append(ports, port)
+		}
+	}
+
+	for _, node := range s.Expose {
+		if port, ok := parsePort(expandEnv(node.Value, nil)); ok {
+			ports = append(ports, port)
+		}
+	}
+
+	return ports
+}
+
+// expandEnv substitutes variable references using vars, falling back to the
+// reference's own default. Unresolved references are left in place.
+func expandEnv(value string, vars map[string]string) string {
+	return envReference.ReplaceAllStringFunc(value, func(ref string) string {
+		match := envReference.FindStringSubmatch(ref)
+		name := match[1] + match[3]
+
+		if v, ok := vars[name]; ok {
+			return v
+		}
+		if match[2] != "" {
+			return match[2]
+		}
+		return ref
+	})
+}
+
+// parsePort parses "8080", "8080/tcp" or the start of a range like "8000-8010".
+// Values that still contain unresolved variables are rejected.
+func parsePort(value string) (int, bool) {
+	value, _, _ = strings.Cut(value, "/")
+	value, _, _ = strings.Cut(value, "-")
+
+	port, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || port <= 0 || port > 65535 {
+		return 0, false
+	}
+	return port, true
+}
+
+// uniquePorts removes duplicate ports, keeping the first occurrence
+func uniquePorts(ports []int) []int {
+	seen := make(map[int]bool, len(ports))
+	var unique []int
+	for _, port := range ports {
+		if !seen[port] {
+			seen[port] = true
+			unique = append(unique, port)
+		}
+	}
+	return unique
+}
@@ -3,12 +3,16 @@ package analyzer
 import (
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 )
 
 // Detector detects components in a repository
 type Detector struct {
-	rootPath string
+	rootPath         string
+	exclude          []string
+	generated        []string
+	inspectGenerated bool
 }
 
 // NewDetector creates a new detector
@@ -18,6 +22,19 @@ func NewDetector(rootPath string) *Detector {
 	}
 }
 
+// SetExcludePatterns leaves excluded files out of component file lists and
+// drops components whose directory is excluded
+func (d *Detector) SetExcludePatterns(patterns []string) {
+	d.exclude = patterns
+}
+
+// SetGeneratedPatterns leaves generated and minified files out of
+// component file lists (see Scanner.SetGeneratedPatterns)
+func (d *Detector) SetGeneratedPatterns(patterns []string, inspect bool) {
+	d.generated = patterns
+	d.inspectGenerated = inspect
+}
+
 // DetectComponents detects all components in the repository
 func (d *Detector) DetectComponents(structure *RepoStructure) ([]Component, error) {
 	var components []Component
@@ -37,6 +54,15 @@ func (d *Detector) DetectComponents(structure *RepoStructure) ([]Component, erro
 		components = append(components, d.detectByDirectory()...)
 	}
 
+	// Drop components in excluded directories
+	kept := components[:0]
+	for _, comp := range components {
+		if !d.isExcluded(comp.Path) {
+			kept = append(kept, comp)
+		}
+	}
+	components = kept
+
 	// Enrich components with metadata
 	for i := range components {
 		d.enrichComponent(&components[i], structure)
@@ -80,7 +106,7 @@ func (d *Detector) detectSingleComponent() *Component {
 	// Check for Go module
 	if d.fileExists("go.mod") {
 		comp.Language = "go"
-		comp.Type = d.detectGoComponentType()
+		d.analyzeGo(comp, d.rootPath)
 		comp.Name = d.extractGoModuleName()
 		return comp
 	}
@@ -109,14 +135,41 @@ func (d *Detector) detectSingleComponent() *Component {
 		return comp
 	}
 
-	// Check for Java project
+	// Check for Java or Kotlin project
 	if d.fileExists("pom.xml") || d.fileExists("build.gradle") || d.fileExists("build.gradle.kts") {
 		comp.Language = "java"
+		if d.fileExists("src/main/kotlin") {
+			comp.Language = "kotlin"
+		}
+		comp.Type = "service"
+		comp.Name = filepath.Base(d.rootPath)
+		return comp
+	}
+
+	// Check for PHP Composer project
+	if d.fileExists("composer.json") {
+		comp.Language = "php"
 		comp.Type = "service"
 		comp.Name = filepath.Base(d.rootPath)
 		return comp
 	}
 
+	// Check for Swift package
+	if d.fileExists("Package.swift") {
+		comp.Language = "swift"
+		comp.Type = "library"
+		comp.Name = filepath.Base(d.rootPath)
+		return comp
+	}
+
+	// Check for C/C++ project
+	if d.fileExists("vcpkg.json") || d.fileExists("conanfile.txt") || d.fileExists("CMakeLists.txt") {
+		comp.Language = "cpp"
+		comp.Type = "library"
+		comp.Name = filepath.Base(d.rootPath)
+		return comp
+	}
+
 	// Check for Ruby gem/Rails app
 	if d.fileExists("Gemfile") {
 		comp.Language = "ruby"
@@ -185,14 +238,32 @@ func (d *Detector) analyzeDirectory(dir string) *Component {
 		comp.Language = "ruby"
 	} else if d.dirHasFile(dir, "*.cs") {
 		comp.Language = "csharp"
+	} else if d.dirHasFile(dir, "*.kt") || d.dirHasFile(dir, "build.gradle.kts") {
+		comp.Language = "kotlin"
+	} else if d.dirHasFile(dir, "*.php") || d.dirHasFile(dir, "composer.json") {
+		comp.Language = "php"
+	} else if d.dirHasFile(dir, "*.swift") || d.dirHasFile(dir, "Package.swift") {
+		comp.Language = "swift"
+	} else if d.dirHasFile(dir, "*.cpp") || d.dirHasFile(dir, "vcpkg.json") ||
+		d.dirHasFile(dir, "conanfile.txt") || d.dirHasFile(dir, "CMakeLists.txt") {
+		comp.Language = "cpp"
+	} else if d.dirHasFile(dir, "*.c") {
+		comp.Language = "c"
+	} else if d.dirHasFile(dir, "tsconfig.json") {
+		// Frontends usually keep their sources under src/
+		comp.Language = "typescript"
+	} else if d.dirHasFile(dir, "package.json") {
+		comp.Language = "javascript"
 	}
 
-	// Detect type
-	if d.dirHasFile(dir, "Dockerfile") {
+	// Detect type; Go packages are parsed rather than guessed from file names
+	if comp.Language == "go" {
+		d.analyzeGo(comp, dir)
+	} else if d.dirHasFile(dir, "Dockerfile") {
 		comp.Type = "service"
 	} else if d.dirHasFile(dir, "package.json") {
 		// Check if it's a frontend
-		if d.dirContains(dir, "react") || d.dirContains(dir, "vue") || d.dirContains(dir, "angular") {
+		if frontendFramework(dir) != "" || d.dirContains(dir, "react") || d.dirContains(dir, "vue") || d.dirContains(dir, "angular") {
 			comp.Type = "frontend"
 		} else {
 			comp.Type = "service"
@@ -204,10 +275,29 @@ func (d *Detector) analyzeDirectory(dir string) *Component {
 	return comp
 }
 
+// analyzeGo collects a Go component's files and parses them once for its
+// type and, for libraries, its exported functions
+func (d *Detector) analyzeGo(comp *Component, dir string) {
+	comp.Files = d.collectComponentFiles(dir)
+
+	paths := make([]string, len(comp.Files))
+	for i, file := range comp.Files {
+		paths[i] = filepath.Join(d.rootPath, file)
+	}
+
+	analysis := analyzeGoFiles(dir, paths)
+	comp.Type = analysis.componentType()
+	if comp.Type == "library" {
+		comp.Functions = analysis.functions
+	}
+}
+
 // enrichComponent enriches a component with additional metadata
 func (d *Detector) enrichComponent(comp *Component, structure *RepoStructure) {
-	// Collect files
-	comp.Files = d.collectComponentFiles(comp.Path)
+	// Collect files, unless they were collected to detect the type
+	if comp.Files == nil {
+		comp.Files = d.collectComponentFiles(comp.Path)
+	}
 
 	// Check for tests
 	comp.HasTests = d.hasTests(comp.Path)
@@ -239,18 +329,33 @@ func (d *Detector) collectComponentFiles(path string) []string {
 			return nil
 		}
 
-		// Only include source files
-		if isSourceFile(filePath) {
+		// Only include hand-written source files
+		if isSourceFile(filePath) && !d.isExcluded(filePath) {
 			relPath, _ := filepath.Rel(d.rootPath, filePath)
-			files = append(files, relPath)
+			if !IsGenerated(filePath, relPath, d.generated, d.inspectGenerated) {
+				files = append(files, relPath)
+			}
 		}
 
 		return nil
 	})
 
+	// Keep file lists stable for the cache and JSON output
+	sort.Strings(files)
+
 	return files
 }
 
+// isExcluded reports whether path, or a directory containing it, matches
+// an exclude pattern. The repository root is never excluded.
+func (d *Detector) isExcluded(path string) bool {
+	relPath, err := filepath.Rel(d.rootPath, path)
+	if err != nil || relPath == "." {
+		return false
+	}
+	return matchAny(relPath, d.exclude)
+}
+
 // hasTests checks if a component has tests
 func (d *Detector) hasTests(path string) bool {
 	hasTests := false
@@ -311,6 +416,7 @@ func isSourceFile(path string) bool {
 	sourceExts := []string{
 		".go", ".py", ".js", ".ts", ".jsx", ".tsx",
 		".java", ".rs", ".rb", ".php", ".c", ".cpp",
+		".kt", ".swift",
 	}
 
 	for _, sourceExt := range sourceExts {
@@ -324,13 +430,6 @@ func isSourceFile(path string) bool {
 
 // Placeholder detection methods (to be implemented)
 
-func (d *Detector) detectGoComponentType() string {
-	if d.fileExists("cmd") || d.fileExists("main.go") {
-		return "service"
-	}
-	return "library"
-}
-
 func (d *Detector) detectPythonComponentType() string {
 	if d.fileExists("Dockerfile") {
 		return "service"
@@ -342,6 +441,9 @@ func (d *Detector) detectNodeComponentType() string {
 	if d.fileExists("Dockerfile") {
 		return "service"
 	}
+	if frontendFramework(d.rootPath) != "" {
+		return "frontend"
+	}
 	return "library"
 }
 
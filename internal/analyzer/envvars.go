@@ -0,0 +1,198 @@
+package analyzer
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// envExampleFiles are the files that document a component's environment.
+// .env itself is never read because it holds real values.
+var envExampleFiles = []string{".env.example", ".env.sample", ".env.template"}
+
+// envPatterns match environment variable reads per file extension. The first
+// non-empty group is the variable name; an optional last group is its default.
+var envPatterns = map[string][]*regexp.Regexp{
+	".go": {
+		regexp.MustCompile(`os\.(?:Getenv|LookupEnv)\(\s*"([A-Za-z_][A-Za-z0-9_]*)"\s*\)`),
+		// Helpers like getEnv("PORT", "8080") or envOr("PORT", "8080")
+		regexp.MustCompile(`\b(?:[Gg]et|[Mm]ust)?[Ee]nv\w*\(\s*"([A-Za-z_][A-Za-z0-9_]*)"\s*,\s*"([^"]*)"\s*\)`),
+	},
+	".js":  jsEnvPatterns,
+	".ts":  jsEnvPatterns,
+	".mjs": jsEnvPatterns,
+	".cjs": jsEnvPatterns,
+	".jsx": jsEnvPatterns,
+	".tsx": jsEnvPatterns,
+	".py": {
+		regexp.MustCompile(`os\.environ\[\s*['"]([A-Za-z_][A-Za-z0-9_]*)['"]\s*\]`),
+		regexp.MustCompile(`os\.(?:environ\.get|getenv)\(\s*['"]([A-Za-z_][A-Za-z0-9_]*)['"](?:\s*,\s*['"]([^'"]*)['"])?`),
+	},
+}
+
+var jsEnvPatterns = []*regexp.Regexp{
+	// process.env.PORT || "8080" and process.env["PORT"] ?? '8080'
+	regexp.MustCompile(`process\.env(?:\.([A-Za-z_][A-Za-z0-9_]*)|\[\s*['"]([A-Za-z_][A-Za-z0-9_]*)['"]\s*\])(?:\s*(?:\|\||\?\?)\s*['"` + "`" + `]([^'"` + "`" + `]*)['"` + "`" + `])?`),
+}
+
+// envVar collects what is known about one environment variable
+type envVar struct {
+	defaultValue string
+	comment      string
+}
+
+// ExtractConfiguration finds the environment variables a component reads and
+// describes each with its default and any comment from an .env example file
+func (m *MetadataExtractor) ExtractConfiguration(comp *Component) map[string]string {
+	vars := make(map[string]*envVar)
+
+	record := func(name, defaultValue, comment string) {
+		v, ok := vars[name]
+		if !ok {
+			v = &envVar{}
+			vars[name] = v
+		}
+		if v.defaultValue == "" {
+			v.defaultValue = defaultValue
+		}
+		if v.comment == "" {
+			v.comment = comment
+		}
+	}
+
+	for _, name := range envExampleFiles {
+		content, err := os.ReadFile(filepath.Join(comp.Path, name))
+		if err != nil {
+			continue
+		}
+		for _, entry := range parseEnvExample(string(content)) {
+			record(entry.name, entry.defaultValue, entry.comment)
+		}
+	}
+
+	for _, file := range comp.Files {
+		if isTestFile(file) {
+			continue
+		}
+
+		patterns, ok := envPatterns[strings.ToLower(filepath.Ext(file))]
+		if !ok {
+			continue
+		}
+
+		content, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+
+		for _, re := range patterns {
+			for _, match := range re.FindAllStringSubmatch(string(content), -1) {
+				name, defaultValue := envMatch(match)
+				if name != "" {
+					record(name, defaultValue, "")
+				}
+			}
+		}
+	}
+
+	if len(vars) == 0 {
+		return nil
+	}
+
+	// Template maps are rendered in sorted key order
+	config := make(map[string]string, len(vars))
+	for name, v := range vars {
+		config[name] = v.describe()
+	}
+	return config
+}
+
+// envMatch returns the variable name and default from a pattern match. The
+// JavaScript pattern has two alternative name groups, so take the first
+// non-empty group as the name and the last group as the default.
+func envMatch(match []string) (string, string) {
+	for i, group := range match[1:] {
+		if group == "" {
+			continue
+		}
+
+		rest := match[i+2:]
+		if len(rest) == 0 {
+			return group, ""
+		}
+		return group, rest[len(rest)-1]
+	}
+	return "", ""
+}
+
+// describe formats a variable's comment and default for the docs
+func (v *envVar) describe() string {
+	switch {
+	case v.comment != "" && v.defaultValue != "":
+		return v.comment + " (default: `" + v.defaultValue + "`)"
+	case v.defaultValue != "":
+		return "Default: `" + v.defaultValue + "`"
+	default:
+		return v.comment
+	}
+}
+
+// envExampleEntry is one variable from an .env example file
+type envExampleEntry struct {
+	name         string
+	defaultValue string
+	comment      string
+}
+
+// parseEnvExample parses KEY=value lines. A trailing "# comment", or the
+// comment lines directly above the entry, describe the variable.
+func parseEnvExample(content string) []envExampleEntry {
+	var entries []envExampleEntry
+	var pending []string
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "" {
+			pending = nil
+			continue
+		}
+
+		if strings.HasPrefix(line, "#") {
+			pending = append(pending, strings.TrimSpace(strings.TrimLeft(line, "#")))
+			continue
+		}
+
+		line = strings.TrimPrefix(line, "export ")
+		name, value, ok := strings.Cut(line, "=")
+		if !ok {
+			pending = nil
+			continue
+		}
+
+		comment := strings.Join(pending, " ")
+		pending = nil
+
+		// Only unquoted values can carry a trailing comment
+		value = strings.TrimSpace(value)
+		if quoted := strings.Trim(value, `"'`); len(quoted) != len(value) {
+			value = quoted
+		} else if v, c, found := strings.Cut(value, " #"); found {
+			value = strings.TrimSpace(v)
+			if comment == "" {
+				comment = strings.TrimSpace(c)
+			}
+		}
+
+		entries = append(entries, envExampleEntry{
+			name:         strings.TrimSpace(name),
+			defaultValue: value,
+			comment:      comment,
+		})
+	}
+
+	return entries
+}
@@ -0,0 +1,220 @@
+package analyzer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/docbrown/cli/internal/logging"
+)
+
+// defaultExternalTimeout bounds the external analyzer when no timeout is set
+const defaultExternalTimeout = time.Minute
+
+// validComponentTypes are the component types an external analyzer may report
+var validComponentTypes = []string{"service", "library", "frontend", "cli"}
+
+// externalAnalyzer runs an organization's own analysis tool and merges its
+// components with the detected ones
+type externalAnalyzer struct {
+	command string
+	replace bool // use only the external components, dropping detected ones
+	timeout time.Duration
+}
+
+// externalResult is the JSON an external analyzer writes to stdout. Each
+// component is a Component object; fields it sets override detection.
+type externalResult struct {
+	Components      []json.RawMessage `json:"components"`
+	PrimaryLanguage string            `json:"primary_language"`
+}
+
+// SetExternalCommand runs command with the repository path as its last
+// argument after detection. Its components override detected ones with the
+// same name or path and are added otherwise; with replace they are the only
+// components. The command is split on whitespace and run without a shell.
+func (a *Analyzer) SetExternalCommand(command string, replace bool, timeout time.Duration) {
+	if strings.TrimSpace(command) == "" {
+		a.external = nil
+		return
+	}
+	if timeout <= 0 {
+		timeout = defaultExternalTimeout
+	}
+
+	a.external = &externalAnalyzer{
+		command: command,
+		replace: replace,
+		timeout: timeout,
+	}
+}
+
+// run executes the external analyzer and decodes its output
+func (e *externalAnalyzer) run(rootPath string) (*externalResult, error) {
+	root, err := filepath.Abs(rootPath)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+	defer cancel()
+
+	args := strings.Fields(e.command)
+	cmd := exec.CommandContext(ctx, args[0], append(args[1:], root)...)
+	cmd.Dir = root
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("%s timed out after %s", args[0], e.timeout)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s failed: %w: %s", args[0], err, msg)
+		}
+		return nil, fmt.Errorf("%s failed: %w", args[0], err)
+	}
+
+	return decodeExternalResult(stdout.Bytes())
+}
+
+// decodeExternalResult parses and validates external analyzer output.
+// Unknown fields are rejected so typos don't silently do nothing.
+func decodeExternalResult(output []byte) (*externalResult, error) {
+	var result externalResult
+	if err := strictUnmarshal(output, &result); err != nil {
+		return nil, fmt.Errorf("invalid output: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	for i, raw := range result.Components {
+		var comp Component
+		if err := strictUnmarshal(raw, &comp); err != nil {
+			return nil, fmt.Errorf("invalid output: components[%d]: %w", i, err)
+		}
+		if comp.Name == "" {
+			return nil, fmt.Errorf("invalid output: components[%d]: name is required", i)
+		}
+		if seen[comp.Name] {
+			return nil, fmt.Errorf("invalid output: components[%d]: duplicate name %q", i, comp.Name)
+		}
+		seen[comp.Name] = true
+		if comp.Type != "" && !slices.Contains(validComponentTypes, comp.Type) {
+			return nil, fmt.Errorf("invalid output: components[%d] (%s): type must be one of %s, got %q",
+				i, comp.Name, strings.Join(validComponentTypes, ", "), comp.Type)
+		}
+		if clean := filepath.ToSlash(filepath.Clean(comp.Path)); filepath.IsAbs(comp.Path) || clean == ".." || strings.HasPrefix(clean, "../") {
+			return nil, fmt.Errorf("invalid output: components[%d] (%s): path must be relative to the repository, got %q",
+				i, comp.Name, comp.Path)
+		}
+	}
+
+	return &result, nil
+}
+
+// strictUnmarshal decodes data into v, rejecting unknown fields and
+// trailing data
+func strictUnmarshal(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+	if decoder.More() {
+		return fmt.Errorf("unexpected data after the JSON object")
+	}
+	return nil
+}
+
+// mergeExternal applies external analyzer output to the detected
+// components. An external component matching a detected one by name or path
+// starts from it; others get their files and metadata extracted like
+// detected ones. The fields present in the output are then applied on top.
+func (a *Analyzer) mergeExternal(structure *RepoStructure, components []Component, result *externalResult) []Component {
+	if result.PrimaryLanguage != "" {
+		structure.PrimaryLanguage = result.PrimaryLanguage
+	}
+
+	merged := components
+	if a.external.replace {
+		merged = nil
+	}
+
+	for _, raw := range result.Components {
+		var comp Component
+		json.Unmarshal(raw, &comp) // validated by decodeExternalResult
+
+		// Detected paths include the root path; external ones are relative to it
+		path := ""
+		if comp.Path != "" {
+			path = filepath.Join(a.rootPath, comp.Path)
+		}
+
+		detected := -1
+		for i := range components {
+			if components[i].Name == comp.Name || (path != "" && filepath.Clean(components[i].Path) == path) {
+				detected = i
+				break
+			}
+		}
+
+		var base Component
+		if detected >= 0 {
+			base = components[detected]
+		} else {
+			base = a.newExternalComponent(comp)
+		}
+
+		// Only the fields present in the output override detection
+		json.Unmarshal(raw, &base)
+		if path != "" {
+			base.Path = path
+		}
+
+		if detected >= 0 && !a.external.replace {
+			merged[detected] = base
+		} else {
+			merged = append(merged, base)
+		}
+	}
+
+	return merged
+}
+
+// newExternalComponent builds a component the detector didn't find,
+// filling in what the external analyzer left out
+func (a *Analyzer) newExternalComponent(comp Component) Component {
+	relPath := comp.Path
+	comp.Path = filepath.Join(a.rootPath, relPath)
+	if _, err := os.Stat(comp.Path); err != nil {
+		logging.Warnf("⚠ External analyzer component %s: path %q does not exist", comp.Name, relPath)
+		return comp
+	}
+
+	comp.Files = a.detector.collectComponentFiles(comp.Path)
+	comp.HasTests = a.detector.hasTests(comp.Path)
+	if comp.Language == "" {
+		for _, file := range comp.Files {
+			if language := DetectLanguage(file); language != "" {
+				comp.Language = language
+				break
+			}
+		}
+	}
+	if comp.Type == "" {
+		comp.Type = "library"
+	}
+
+	comp.Dependencies = a.metadata.ExtractDependencies(&comp)
+	a.metadata.ExtractMetadata(&comp)
+
+	return comp
+}
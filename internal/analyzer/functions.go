@@ -0,0 +1,320 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/docbrown/cli/internal/template"
+)
+
+// maxFunctions caps the exported functions recorded per component
+const maxFunctions = 200
+
+var (
+	pyDefPattern       = regexp.MustCompile(`(?m)^(async[ \t]+)?def[ \t]+([A-Za-z]\w*)[ \t]*\(([^)]*)\)[ \t]*(?:->[ \t]*([^:\n]+?))?[ \t]*:`)
+	pyDocstringPattern = regexp.MustCompile(`\A\s*(?:"""([\s\S]*?)"""|'''([\s\S]*?)''')`)
+	tsFuncPattern      = regexp.MustCompile(`(?m)^export[ \t]+(?:declare[ \t]+)?(?:default[ \t]+)?(async[ \t]+)?function[ \t]*\*?[ \t]*(\w+)[ \t]*(?:<[^>(]*>)?[ \t]*\(([^)]*)\)(?:[ \t]*:[ \t]*([^{;\n]+))?`)
+	tsArrowPattern     = regexp.MustCompile(`(?m)^export[ \t]+const[ \t]+(\w+)[ \t]*(?::[^=\n]+)?=[ \t]*(async[ \t]+)?\(([^)]*)\)(?:[ \t]*:[ \t]*([^=\n{]+?))?[ \t]*=>`)
+)
+
+// ExtractFunctions extracts the exported/public functions of a component's
+// files: Go via its AST, Python and TypeScript/JavaScript via declaration
+// parsing
+func (m *MetadataExtractor) ExtractFunctions(comp *Component) []template.FunctionData {
+	// Go libraries were parsed when the detector determined their type
+	if comp.Language == "go" && comp.Functions != nil {
+		return comp.Functions
+	}
+
+	var paths []string
+	for _, file := range comp.Files {
+		paths = append(paths, filepath.Join(m.rootPath, file))
+	}
+
+	var functions []template.FunctionData
+
+	switch comp.Language {
+	case "go":
+		return analyzeGoFiles(comp.Path, paths).functions
+	case "python":
+		for _, file := range paths {
+			if strings.ToLower(filepath.Ext(file)) == ".py" && isPublicSourceFile(file) {
+				functions = append(functions, pythonFunctions(file)...)
+			}
+		}
+	case "typescript", "javascript":
+		for _, file := range paths {
+			switch strings.ToLower(filepath.Ext(file)) {
+			case ".ts", ".tsx", ".js", ".jsx", ".mjs":
+				if isPublicSourceFile(file) {
+					functions = append(functions, tsFunctions(file)...)
+				}
+			}
+		}
+	}
+
+	return sortFunctions(functions)
+}
+
+// sortFunctions orders functions by name, drops duplicate names (e.g.
+// TypeScript overloads) and applies the per-component cap
+func sortFunctions(functions []template.FunctionData) []template.FunctionData {
+	sort.SliceStable(functions, func(i, j int) bool {
+		return functions[i].Name < functions[j].Name
+	})
+
+	var result []template.FunctionData
+	for i, fn := range functions {
+		if i > 0 && fn.Name == functions[i-1].Name {
+			continue
+		}
+		result = append(result, fn)
+	}
+
+	if len(result) > maxFunctions {
+		result = result[:maxFunctions]
+	}
+	return result
+}
+
+// isPublicSourceFile reports whether a file is part of a package's public
+// surface: not a test, not private (_module.py) and not generated output
+func isPublicSourceFile(path string) bool {
+	if isTestFile(path) {
+		return false
+	}
+
+	base := filepath.Base(path)
+	if strings.HasPrefix(base, "test_") || (strings.HasPrefix(base, "_") && base != "__init__.py") {
+		return false
+	}
+
+	for _, part := range strings.Split(filepath.ToSlash(path), "/") {
+		if part == "dist" || part == "build" || part == "__pycache__" {
+			return false
+		}
+	}
+	return true
+}
+
+// pythonFunctions extracts public top-level functions and their docstrings
+func pythonFunctions(path string) []template.FunctionData {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	src := string(content)
+
+	module := strings.TrimSuffix(filepath.Base(path), ".py")
+	if module == "__init__" {
+		module = filepath.Base(filepath.Dir(path))
+	}
+
+	var functions []template.FunctionData
+	for _, match := range pyDefPattern.FindAllStringSubmatchIndex(src, -1) {
+		name := src[match[4]:match[5]]
+		params := collapseSpace(src[match[6]:match[7]])
+		returns := ""
+		if match[8] >= 0 {
+			returns = strings.TrimSpace(src[match[8]:match[9]])
+		}
+
+		signature := "def " + name + "(" + params + ")"
+		if match[2] >= 0 {
+			signature = "async " + signature
+		}
+		if returns != "" {
+			signature += " -> " + returns
+		}
+
+		description := ""
+		if doc := pyDocstringPattern.FindStringSubmatch(src[match[1]:]); doc != nil {
+			description = cleanDocstring(doc[1] + doc[2])
+		}
+
+		functions = append(functions, template.FunctionData{
+			Name:        module + "." + name,
+			Signature:   signature,
+			Description: description,
+			Parameters:  pythonParameters(params),
+			Returns:     returns,
+		})
+	}
+
+	return functions
+}
+
+// pythonParameters parses "a: int, b: str = 'x', *args" into parameters
+func pythonParameters(params string) []template.ParameterData {
+	var result []template.ParameterData
+
+	for _, param := range splitTopLevel(params) {
+		if param == "self" || param == "cls" || param == "*" || param == "/" {
+			continue
+		}
+
+		def := strings.Contains(param, "=")
+		if def {
+			param = strings.TrimSpace(param[:strings.Index(param, "=")])
+		}
+
+		name, paramType := param, ""
+		if colon := strings.Index(param, ":"); colon >= 0 {
+			name, paramType = strings.TrimSpace(param[:colon]), strings.TrimSpace(param[colon+1:])
+		}
+
+		result = append(result, template.ParameterData{
+			Name:     name,
+			Type:     paramType,
+			Required: !def && !strings.HasPrefix(name, "*"),
+		})
+	}
+
+	return result
+}
+
+// tsFunctions extracts exported functions (declarations and arrow function
+// constants) with their JSDoc comments
+func tsFunctions(path string) []template.FunctionData {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	src := string(content)
+
+	var functions []template.FunctionData
+	add := func(start int, name, params, returns string, async bool) {
+		params = collapseSpace(params)
+		returns = strings.TrimSpace(returns)
+
+		signature := "function " + name + "(" + params + ")"
+		if async {
+			signature = "async " + signature
+		}
+		if returns != "" {
+			signature += ": " + returns
+		}
+
+		functions = append(functions, template.FunctionData{
+			Name:        name,
+			Signature:   signature,
+			Description: jsDocBefore(src[:start]),
+			Parameters:  tsParameters(params),
+			Returns:     returns,
+		})
+	}
+
+	for _, m := range tsFuncPattern.FindAllStringSubmatchIndex(src, -1) {
+		add(m[0], src[m[4]:m[5]], src[m[6]:m[7]], submatch(src, m, 4), m[2] >= 0)
+	}
+	for _, m := range tsArrowPattern.FindAllStringSubmatchIndex(src, -1) {
+		add(m[0], src[m[2]:m[3]], src[m[6]:m[7]], submatch(src, m, 4), m[4] >= 0)
+	}
+
+	return functions
+}
+
+// tsParameters parses "a: number, b?: string, ...rest: T[]" into parameters
+func tsParameters(params string) []template.ParameterData {
+	var result []template.ParameterData
+
+	for _, param := range splitTopLevel(params) {
+		def := strings.Contains(param, "=")
+		if def {
+			param = strings.TrimSpace(param[:strings.Index(param, "=")])
+		}
+
+		name, paramType := param, ""
+		if colon := strings.Index(param, ":"); colon >= 0 {
+			name, paramType = strings.TrimSpace(param[:colon]), strings.TrimSpace(param[colon+1:])
+		}
+
+		optional := def || strings.HasSuffix(name, "?") || strings.HasPrefix(name, "...")
+		result = append(result, template.ParameterData{
+			Name:     strings.TrimSuffix(name, "?"),
+			Type:     paramType,
+			Required: !optional,
+		})
+	}
+
+	return result
+}
+
+// jsDocBefore returns the text of a /** ... */ comment directly preceding a
+// declaration, without @tags
+func jsDocBefore(src string) string {
+	src = strings.TrimRight(src, " \t\r\n")
+	if !strings.HasSuffix(src, "*/") {
+		return ""
+	}
+
+	start := strings.LastIndex(src, "/**")
+	if start < 0 {
+		return ""
+	}
+
+	var lines []string
+	for _, line := range strings.Split(src[start+3:len(src)-2], "\n") {
+		line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "*"))
+		if strings.HasPrefix(line, "@") {
+			break
+		}
+		lines = append(lines, line)
+	}
+
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// cleanDocstring removes a docstring's common indentation
+func cleanDocstring(doc string) string {
+	var lines []string
+	for _, line := range strings.Split(doc, "\n") {
+		lines = append(lines, strings.TrimSpace(line))
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// splitTopLevel splits a parameter list on commas outside brackets
+func splitTopLevel(params string) []string {
+	var parts []string
+	depth, start := 0, 0
+
+	for i, r := range params {
+		switch r {
+		case '(', '[', '{', '<':
+			depth++
+		case ')', ']', '}', '>':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, params[start:i])
+				start = i + 1
+			}
+		}
+	}
+	parts = append(parts, params[start:])
+
+	var result []string
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			result = append(result, part)
+		}
+	}
+	return result
+}
+
+// collapseSpace joins a possibly multi-line declaration onto one line
+func collapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// submatch returns the nth capture group of an index match, or ""
+func submatch(src string, match []int, n int) string {
+	if match[2*n] < 0 {
+		return ""
+	}
+	return src[match[2*n]:match[2*n+1]]
+}
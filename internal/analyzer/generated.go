@@ -0,0 +1,63 @@
+package analyzer
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"regexp"
+)
+
+const (
+	// generatedHeaderLines is how many leading lines are searched for a
+	// generated-code marker
+	generatedHeaderLines = 10
+	// minifiedSampleSize is how much of a file is read to spot minified code
+	minifiedSampleSize = 32 * 1024
+	// minifiedLineLength is the line length beyond which code is considered
+	// minified or bundled
+	minifiedLineLength = 1000
+)
+
+// generatedMarker matches the usual generated-code headers: Go's
+// "Code generated ... DO NOT EDIT.", protoc's "DO NOT EDIT!", @generated
+// and .NET's <auto-generated>
+var generatedMarker = regexp.MustCompile(`DO NOT EDIT|@generated\b|<auto-generated`)
+
+// IsGenerated reports whether a file is generated or minified, by name
+// (relPath matching one of patterns) or, when inspect is set, by content
+// (a generated-code header or very long lines)
+func IsGenerated(path, relPath string, patterns []string, inspect bool) bool {
+	if matchAny(relPath, patterns) {
+		return true
+	}
+	return inspect && hasGeneratedContent(path)
+}
+
+// hasGeneratedContent looks for a generated-code header near the top of
+// the file, or a line long enough to be minified code
+func hasGeneratedContent(path string) bool {
+	file, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	sample, err := io.ReadAll(io.LimitReader(file, minifiedSampleSize))
+	if err != nil {
+		return false
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(sample))
+	scanner.Buffer(make([]byte, 0, 64*1024), minifiedSampleSize)
+	for line := 0; scanner.Scan(); line++ {
+		text := scanner.Bytes()
+		if len(text) > minifiedLineLength {
+			return true
+		}
+		if line < generatedHeaderLines && generatedMarker.Match(text) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package analyzer
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// matchGlob reports whether relPath matches a glob pattern. Besides the
+// usual wildcards, a "**" path segment matches zero or more directories,
+// so "**/*_test.go" matches test files at any depth.
+func matchGlob(pattern, relPath string) bool {
+	pattern = strings.TrimPrefix(filepath.ToSlash(pattern), "./")
+	relPath = filepath.ToSlash(relPath)
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(relPath, "/"))
+}
+
+// IsSensitive reports whether relPath, or any directory containing it,
+// matches one of the sensitive file patterns
+func IsSensitive(relPath string, patterns []string) bool {
+	return matchAny(relPath, patterns)
+}
+
+// matchAny reports whether relPath, or any directory containing it,
+// matches one of patterns
+func matchAny(relPath string, patterns []string) bool {
+	segments := strings.Split(filepath.ToSlash(filepath.Clean(relPath)), "/")
+
+	for _, pattern := range patterns {
+		patternSegments := strings.Split(strings.TrimPrefix(filepath.ToSlash(pattern), "./"), "/")
+		for i := len(segments); i > 0; i-- {
+			if matchSegments(patternSegments, segments[:i]) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// matchSegments matches path segments against pattern segments, where a
+// "**" segment matches zero or more path segments
+func matchSegments(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchSegments(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(segments) == 0 {
+		return false
+	}
+
+	if matched, _ := path.Match(pattern[0], segments[0]); !matched {
+		return false
+	}
+	return matchSegments(pattern[1:], segments[1:])
+}
@@ -0,0 +1,237 @@
+package analyzer
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"path/filepath"
+	"strings"
+
+	"github.com/docbrown/cli/internal/template"
+)
+
+// goServerCalls are selector calls that start a network server
+var goServerCalls = map[string]bool{
+	"http.ListenAndServe":    true,
+	"http.ListenAndServeTLS": true,
+	"http.Serve":             true,
+	"grpc.NewServer":         true,
+	"gin.Default":            true,
+	"gin.New":                true,
+	"echo.New":               true,
+	"fiber.New":              true,
+	"chi.NewRouter":          true,
+	"mux.NewRouter":          true,
+}
+
+// goAnalysis is what parsing a component's Go packages tells us about it
+type goAnalysis struct {
+	hasMain   bool
+	hasServer bool
+	functions []template.FunctionData
+}
+
+// componentType classifies the component: binaries that start a server are
+// services, other binaries are CLIs, and everything else is a library
+func (g goAnalysis) componentType() string {
+	switch {
+	case g.hasServer:
+		return "service"
+	case g.hasMain:
+		return "cli"
+	default:
+		return "library"
+	}
+}
+
+// analyzeGoFiles parses a component's Go files (skipping tests, vendor,
+// testdata and hidden directories) to find binaries, server setup and the
+// exported API. dir is the component directory; functions in internal/
+// packages beneath it aren't part of the public API.
+func analyzeGoFiles(dir string, files []string) goAnalysis {
+	var result goAnalysis
+	fset := token.NewFileSet()
+
+	for _, path := range files {
+		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil || skipGoDir(filepath.Dir(rel)) {
+			continue
+		}
+
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			continue
+		}
+
+		if file.Name.Name == "main" {
+			result.hasMain = true
+		}
+		if !result.hasServer && startsServer(file) {
+			result.hasServer = true
+		}
+
+		if file.Name.Name != "main" && !isInternalPath(rel) {
+			result.functions = append(result.functions, exportedFunctions(fset, file)...)
+		}
+	}
+
+	result.functions = sortFunctions(result.functions)
+
+	return result
+}
+
+// skipGoDir reports whether a directory relative to the component holds
+// code that isn't part of it: vendored, test data or hidden
+func skipGoDir(rel string) bool {
+	for _, part := range strings.Split(filepath.ToSlash(rel), "/") {
+		if part == "vendor" || part == "testdata" || part == "node_modules" ||
+			(part != "." && part != ".." && strings.HasPrefix(part, ".")) || strings.HasPrefix(part, "_") {
+			return true
+		}
+	}
+	return false
+}
+
+// startsServer reports whether a file calls a known server constructor or
+// builds an http.Server
+func startsServer(file *ast.File) bool {
+	found := false
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		if found {
+			return false
+		}
+
+		switch node := n.(type) {
+		case *ast.CallExpr:
+			if name := selectorName(node.Fun); goServerCalls[name] {
+				found = true
+			}
+		case *ast.CompositeLit:
+			if selectorName(node.Type) == "http.Server" {
+				found = true
+			}
+		}
+		return true
+	})
+
+	return found
+}
+
+// selectorName returns "pkg.Name" for a selector expression, or ""
+func selectorName(expr ast.Expr) string {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+	ident, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return ""
+	}
+	return ident.Name + "." + sel.Sel.Name
+}
+
+// isInternalPath reports whether a relative file path lies in an internal package
+func isInternalPath(rel string) bool {
+	for _, part := range strings.Split(filepath.ToSlash(rel), "/") {
+		if part == "internal" {
+			return true
+		}
+	}
+	return false
+}
+
+// exportedFunctions extracts the exported top-level functions of a file
+func exportedFunctions(fset *token.FileSet, file *ast.File) []template.FunctionData {
+	var functions []template.FunctionData
+
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv != nil || !fn.Name.IsExported() {
+			continue
+		}
+
+		functions = append(functions, template.FunctionData{
+			Name:        file.Name.Name + "." + fn.Name.Name,
+			Signature:   funcSignature(fset, fn),
+			Description: strings.TrimSpace(fn.Doc.Text()),
+			Parameters:  funcParameters(fset, fn.Type.Params),
+			Returns:     fieldListString(fset, fn.Type.Results),
+		})
+	}
+
+	return functions
+}
+
+// funcSignature prints a function declaration without its body
+func funcSignature(fset *token.FileSet, fn *ast.FuncDecl) string {
+	decl := *fn
+	decl.Body = nil
+	decl.Doc = nil
+
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, fset, &decl); err != nil {
+		return "func " + fn.Name.Name
+	}
+	return buf.String()
+}
+
+// funcParameters converts a parameter list to template parameters
+func funcParameters(fset *token.FileSet, params *ast.FieldList) []template.ParameterData {
+	if params == nil {
+		return nil
+	}
+
+	var result []template.ParameterData
+	for _, field := range params.List {
+		paramType := exprString(fset, field.Type)
+		_, variadic := field.Type.(*ast.Ellipsis)
+		if len(field.Names) == 0 {
+			result = append(result, template.ParameterData{Type: paramType, Required: !variadic})
+			continue
+		}
+		for _, name := range field.Names {
+			result = append(result, template.ParameterData{Name: name.Name, Type: paramType, Required: !variadic})
+		}
+	}
+	return result
+}
+
+// fieldListString prints a result list, e.g. "(string, error)"
+func fieldListString(fset *token.FileSet, fields *ast.FieldList) string {
+	if fields == nil || len(fields.List) == 0 {
+		return ""
+	}
+
+	var parts []string
+	for _, field := range fields.List {
+		fieldType := exprString(fset, field.Type)
+		if len(field.Names) == 0 {
+			parts = append(parts, fieldType)
+			continue
+		}
+		for _, name := range field.Names {
+			parts = append(parts, name.Name+" "+fieldType)
+		}
+	}
+
+	if len(parts) == 1 && len(fields.List[0].Names) == 0 {
+		return parts[0]
+	}
+	return "(" + strings.Join(parts, ", ") + ")"
+}
+
+// exprString prints an expression as Go source
+func exprString(fset *token.FileSet, expr ast.Expr) string {
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, fset, expr); err != nil {
+		return ""
+	}
+	return buf.String()
+}
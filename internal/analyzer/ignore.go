@@ -0,0 +1,92 @@
+package analyzer
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ignoreRule is a single pattern from a gitignore-style file
+type ignoreRule struct {
+	segments []string // pattern split on "/"
+	negate   bool     // "!pattern" re-includes a previously ignored path
+	dirOnly  bool     // "pattern/" only matches directories
+}
+
+// ignoreMatcher applies gitignore-style rules; the last matching rule wins
+type ignoreMatcher struct {
+	rules []ignoreRule
+}
+
+// loadIgnoreFile reads rules from a gitignore-style file. A missing file
+// yields no rules.
+func (m *ignoreMatcher) loadIgnoreFile(file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if rule, ok := parseIgnoreRule(scanner.Text()); ok {
+			m.rules = append(m.rules, rule)
+		}
+	}
+	return scanner.Err()
+}
+
+// parseIgnoreRule parses one line of a gitignore-style file
+func parseIgnoreRule(line string) (ignoreRule, bool) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return ignoreRule{}, false
+	}
+
+	var rule ignoreRule
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\#`) || strings.HasPrefix(line, `\!`) {
+		line = line[1:]
+	}
+
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if line == "" {
+		return ignoreRule{}, false
+	}
+
+	// A slash anywhere but the end anchors the pattern to the root;
+	// otherwise it matches at any depth
+	if strings.Contains(line, "/") {
+		line = strings.TrimPrefix(line, "/")
+	} else {
+		line = "**/" + line
+	}
+
+	rule.segments = strings.Split(line, "/")
+	return rule, true
+}
+
+// Match reports whether relPath (relative to the root) is ignored
+func (m *ignoreMatcher) Match(relPath string, isDir bool) bool {
+	segments := strings.Split(filepath.ToSlash(relPath), "/")
+
+	ignored := false
+	for _, rule := range m.rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		if matchSegments(rule.segments, segments) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
@@ -0,0 +1,226 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	jsImportPattern  = regexp.MustCompile(`(?m)(?:^|[^\w.])(?:import|export)\s+(?:[^'"]*?\s+from\s+)?['"]([^'"]+)['"]`)
+	jsRequirePattern = regexp.MustCompile(`(?:require|import)\(\s*['"]([^'"]+)['"]\s*\)`)
+	pyFromPattern    = regexp.MustCompile(`(?m)^\s*from\s+([\w.]+)\s+import\s`)
+	pyImportPattern  = regexp.MustCompile(`(?m)^\s*import\s+([\w.]+(?:\s*,\s*[\w.]+)*)`)
+	goModulePattern  = regexp.MustCompile(`(?m)^module\s+(\S+)`)
+)
+
+// sourceImport is an import statement found in a component's source
+type sourceImport struct {
+	file string // path relative to the repository root, slash-separated
+	spec string // imported module path or specifier
+	lang string // go, js or python
+}
+
+// componentModule describes how other components can import a component
+type componentModule struct {
+	dir      string // path relative to the repository root, slash-separated
+	goModule string // Go import path
+	npmName  string // package.json name
+	pyName   string // top-level Python package name
+}
+
+// ExtractInternalDependencies parses import statements in each component and
+// records imports of other detected components as internal dependencies
+func (m *MetadataExtractor) ExtractInternalDependencies(components []Component) {
+	rootModule := readGoModulePath(filepath.Join(m.rootPath, "go.mod"))
+
+	modules := make([]componentModule, len(components))
+	for i := range components {
+		modules[i] = m.componentModule(&components[i], rootModule)
+	}
+
+	for i := range components {
+		seen := make(map[int]bool)
+
+		for _, imp := range m.collectImports(&components[i]) {
+			target := matchImport(imp, modules)
+			if target < 0 || target == i || seen[target] {
+				continue
+			}
+			seen[target] = true
+
+			components[i].Dependencies = append(components[i].Dependencies, Dependency{
+				Name: components[target].Name,
+				Type: "internal",
+			})
+		}
+	}
+}
+
+// componentModule works out the module paths a component is importable by
+func (m *MetadataExtractor) componentModule(comp *Component, rootModule string) componentModule {
+	dir := "."
+	if rel, err := filepath.Rel(m.rootPath, comp.Path); err == nil {
+		dir = filepath.ToSlash(rel)
+	}
+
+	module := componentModule{dir: dir}
+
+	// A nested go.mod takes precedence over the root module path
+	if goModule := readGoModulePath(filepath.Join(comp.Path, "go.mod")); goModule != "" {
+		module.goModule = goModule
+	} else if rootModule != "" && dir != "." {
+		module.goModule = rootModule + "/" + dir
+	}
+
+	if content, err := os.ReadFile(filepath.Join(comp.Path, "package.json")); err == nil {
+		var pkg struct {
+			Name string `json:"name"`
+		}
+		if json.Unmarshal(content, &pkg) == nil {
+			module.npmName = pkg.Name
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(comp.Path, "__init__.py")); err == nil {
+		module.pyName = filepath.Base(comp.Path)
+	}
+
+	return module
+}
+
+// collectImports finds import statements in a component's source files
+func (m *MetadataExtractor) collectImports(comp *Component) []sourceImport {
+	var imports []sourceImport
+
+	for _, file := range comp.Files {
+		var lang string
+		switch strings.ToLower(filepath.Ext(file)) {
+		case ".go":
+			lang = "go"
+		case ".js", ".jsx", ".mjs", ".cjs", ".ts", ".tsx":
+			lang = "js"
+		case ".py":
+			lang = "python"
+		default:
+			continue
+		}
+
+		content, err := os.ReadFile(filepath.Join(m.rootPath, file))
+		if err != nil {
+			continue
+		}
+
+		relFile := filepath.ToSlash(file)
+		for _, spec := range parseImports(lang, content) {
+			imports = append(imports, sourceImport{file: relFile, spec: spec, lang: lang})
+		}
+	}
+
+	return imports
+}
+
+// parseImports extracts imported module specifiers from source code
+func parseImports(lang string, content []byte) []string {
+	var specs []string
+
+	switch lang {
+	case "go":
+		f, err := parser.ParseFile(token.NewFileSet(), "", content, parser.ImportsOnly)
+		if err != nil {
+			return nil
+		}
+		for _, imp := range f.Imports {
+			if spec, err := strconv.Unquote(imp.Path.Value); err == nil {
+				specs = append(specs, spec)
+			}
+		}
+
+	case "js":
+		for _, re := range []*regexp.Regexp{jsImportPattern, jsRequirePattern} {
+			for _, match := range re.FindAllSubmatch(content, -1) {
+				specs = append(specs, string(match[1]))
+			}
+		}
+
+	case "python":
+		for _, match := range pyFromPattern.FindAllSubmatch(content, -1) {
+			specs = append(specs, string(match[1]))
+		}
+		for _, match := range pyImportPattern.FindAllSubmatch(content, -1) {
+			for _, name := range strings.Split(string(match[1]), ",") {
+				specs = append(specs, strings.TrimSpace(name))
+			}
+		}
+	}
+
+	return specs
+}
+
+// matchImport returns the index of the component an import refers to, or -1.
+// When several components match, the most specific (deepest) one wins.
+func matchImport(imp sourceImport, modules []componentModule) int {
+	best, bestLen := -1, 0
+
+	consider := func(i int, match string) {
+		if len(match) > bestLen {
+			best, bestLen = i, len(match)
+		}
+	}
+
+	for i, mod := range modules {
+		switch imp.lang {
+		case "go":
+			if mod.goModule != "" && hasPathPrefix(imp.spec, mod.goModule) {
+				consider(i, mod.goModule)
+			}
+
+		case "js":
+			if strings.HasPrefix(imp.spec, ".") {
+				// Relative import: resolve against the importing file
+				resolved := path.Clean(path.Join(path.Dir(imp.file), imp.spec))
+				if mod.dir != "." && hasPathPrefix(resolved, mod.dir) {
+					consider(i, mod.dir)
+				}
+			} else if mod.npmName != "" && hasPathPrefix(imp.spec, mod.npmName) {
+				consider(i, mod.npmName)
+			}
+
+		case "python":
+			if strings.HasPrefix(imp.spec, ".") {
+				continue
+			}
+			if mod.dir != "." && hasPathPrefix(strings.ReplaceAll(imp.spec, ".", "/"), mod.dir) {
+				consider(i, mod.dir)
+			} else if mod.pyName != "" && (imp.spec == mod.pyName || strings.HasPrefix(imp.spec, mod.pyName+".")) {
+				consider(i, mod.pyName)
+			}
+		}
+	}
+
+	return best
+}
+
+// hasPathPrefix reports whether p equals prefix or is nested beneath it
+func hasPathPrefix(p, prefix string) bool {
+	return p == prefix || strings.HasPrefix(p, prefix+"/")
+}
+
+// readGoModulePath returns the module path declared in a go.mod file
+func readGoModulePath(goModPath string) string {
+	content, err := os.ReadFile(goModPath)
+	if err != nil {
+		return ""
+	}
+
+	if match := goModulePattern.FindSubmatch(content); match != nil {
+		return string(match[1])
+	}
+	return ""
+}
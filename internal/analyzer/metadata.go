@@ -6,12 +6,14 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 )
 
 // MetadataExtractor extracts metadata from project files
 type MetadataExtractor struct {
-	rootPath string
+	rootPath   string
+	includeDev bool
 }
 
 // NewMetadataExtractor creates a new metadata extractor
@@ -21,6 +23,12 @@ func NewMetadataExtractor(rootPath string) *MetadataExtractor {
 	}
 }
 
+// SetIncludeDevDependencies includes development-only dependencies: npm
+// devDependencies, Python dev requirements and Go indirect requirements
+func (m *MetadataExtractor) SetIncludeDevDependencies(include bool) {
+	m.includeDev = include
+}
+
 // ExtractDependencies extracts dependencies for a component
 func (m *MetadataExtractor) ExtractDependencies(comp *Component) []Dependency {
 	var deps []Dependency
@@ -34,12 +42,18 @@ func (m *MetadataExtractor) ExtractDependencies(comp *Component) []Dependency {
 		deps = m.extractNodeDependencies(comp.Path)
 	case "rust":
 		deps = m.extractRustDependencies(comp.Path)
-	case "java":
+	case "java", "kotlin":
 		deps = m.extractJavaDependencies(comp.Path)
 	case "ruby":
 		deps = m.extractRubyDependencies(comp.Path)
 	case "csharp":
 		deps = m.extractCSharpDependencies(comp.Path)
+	case "php":
+		deps = m.extractPHPDependencies(comp.Path)
+	case "swift":
+		deps = m.extractSwiftDependencies(comp.Path)
+	case "c", "cpp":
+		deps = m.extractCppDependencies(comp.Path)
 	}
 
 	return deps
@@ -76,17 +90,21 @@ func (m *MetadataExtractor) extractGoDependencies(path string) []Dependency {
 		}
 
 		if strings.HasPrefix(line, "require ") || inRequire {
-			// Parse require line: "github.com/foo/bar v1.2.3"
-			parts := strings.Fields(strings.TrimPrefix(line, "require "))
+			// Parse require line: "github.com/foo/bar v1.2.3 // comment"
+			spec, comment, _ := strings.Cut(strings.TrimPrefix(line, "require "), "//")
+			parts := strings.Fields(spec)
 			if len(parts) >= 2 {
-				name := parts[0]
-				version := parts[1]
+				scope, purpose := parseGoRequireComment(comment)
+				if scope == "indirect" && !m.includeDev {
+					continue
+				}
 
-				// Include all dependencies (both direct and indirect)
 				deps = append(deps, Dependency{
-					Name:    name,
-					Version: version,
+					Name:    parts[0],
+					Version: parts[1],
 					Type:    "external",
+					Scope:   scope,
+					Purpose: purpose,
 				})
 			}
 		}
@@ -95,77 +113,74 @@ func (m *MetadataExtractor) extractGoDependencies(path string) []Dependency {
 	return deps
 }
 
+// parseGoRequireComment splits a require line comment into the dependency
+// scope and purpose. The go tool writes "// indirect" for transitive
+// requirements and keeps any annotation after it as "// indirect; text".
+func parseGoRequireComment(comment string) (scope, purpose string) {
+	comment = strings.TrimSpace(comment)
+
+	if comment == "indirect" {
+		return "indirect", ""
+	}
+	if rest, ok := strings.CutPrefix(comment, "indirect;"); ok {
+		return "indirect", strings.TrimSpace(rest)
+	}
+
+	return "direct", comment
+}
+
+// pythonDevRequirements are the conventional file names for development requirements
+var pythonDevRequirements = []string{"requirements-dev.txt", "dev-requirements.txt"}
+
+// requirementPattern matches a requirements.txt line: "package==1.2.3" or "package>=1.2.3"
+var requirementPattern = regexp.MustCompile(`^([a-zA-Z0-9_-]+)([>=<~!]+)?(.*)$`)
+
 // extractPythonDependencies extracts dependencies from requirements.txt or pyproject.toml
 func (m *MetadataExtractor) extractPythonDependencies(path string) []Dependency {
 	var deps []Dependency
 
 	// Try requirements.txt first
-	reqPath := filepath.Join(path, "requirements.txt")
-	if path == "." {
-		reqPath = "requirements.txt"
-	}
-
-	content, err := os.ReadFile(reqPath)
+	content, err := os.ReadFile(filepath.Join(path, "requirements.txt"))
 	if err == nil {
-		lines := strings.Split(string(content), "\n")
-		for _, line := range lines {
-			line = strings.TrimSpace(line)
+		deps = parseRequirements(string(content), "external")
 
-			// Skip comments and empty lines
-			if line == "" || strings.HasPrefix(line, "#") {
-				continue
-			}
-
-			// Parse line: "package==1.2.3" or "package>=1.2.3"
-			re := regexp.MustCompile(`^([a-zA-Z0-9_-]+)([>=<~!]+)?(.*)$`)
-			matches := re.FindStringSubmatch(line)
-
-			if len(matches) >= 2 {
-				name := matches[1]
-				version := ""
-				if len(matches) >= 4 {
-					version = matches[3]
+		if m.includeDev {
+			for _, name := range pythonDevRequirements {
+				if content, err := os.ReadFile(filepath.Join(path, name)); err == nil {
+					deps = append(deps, parseRequirements(string(content), "dev")...)
 				}
-
-				deps = append(deps, Dependency{
-					Name:    name,
-					Version: version,
-					Type:    "external",
-				})
 			}
 		}
 		return deps
 	}
 
 	// Try pyproject.toml
-	pyprojectPath := filepath.Join(path, "pyproject.toml")
-	if path == "." {
-		pyprojectPath = "pyproject.toml"
-	}
-
-	content, err = os.ReadFile(pyprojectPath)
+	content, err = os.ReadFile(filepath.Join(path, "pyproject.toml"))
 	if err != nil {
 		return deps
 	}
 
-	// Simple parsing for dependencies section
+	// Simple parsing for dependencies sections
 	lines := strings.Split(string(content), "\n")
-	inDeps := false
+	depType := ""
 
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
 
-		if strings.Contains(line, "[tool.poetry.dependencies]") || strings.Contains(line, "[project.dependencies]") {
-			inDeps = true
-			continue
-		}
-
-		if inDeps && strings.HasPrefix(line, "[") {
-			inDeps = false
+		if strings.HasPrefix(line, "[") {
+			switch {
+			case strings.Contains(line, "[tool.poetry.dependencies]") || strings.Contains(line, "[project.dependencies]"):
+				depType = "external"
+			case m.includeDev && (strings.Contains(line, "[tool.poetry.dev-dependencies]") ||
+				strings.Contains(line, "[tool.poetry.group.dev.dependencies]")):
+				depType = "dev"
+			default:
+				depType = ""
+			}
 			continue
 		}
 
-		if inDeps && strings.Contains(line, "=") {
+		if depType != "" && strings.Contains(line, "=") {
 			parts := strings.Split(line, "=")
 			if len(parts) >= 2 {
 				name := strings.TrimSpace(parts[0])
@@ -174,7 +189,7 @@ func (m *MetadataExtractor) extractPythonDependencies(path string) []Dependency
 				deps = append(deps, Dependency{
 					Name:    name,
 					Version: version,
-					Type:    "external",
+					Type:    depType,
 				})
 			}
 		}
@@ -183,6 +198,36 @@ func (m *MetadataExtractor) extractPythonDependencies(path string) []Dependency
 	return deps
 }
 
+// parseRequirements parses a requirements.txt file, tagging each entry with depType
+func parseRequirements(content, depType string) []Dependency {
+	var deps []Dependency
+
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+
+		// Skip comments, empty lines and pip options like "-r requirements.txt"
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "-") {
+			continue
+		}
+
+		matches := requirementPattern.FindStringSubmatch(line)
+		if len(matches) >= 2 {
+			version := ""
+			if len(matches) >= 4 {
+				version = matches[3]
+			}
+
+			deps = append(deps, Dependency{
+				Name:    matches[1],
+				Version: version,
+				Type:    depType,
+			})
+		}
+	}
+
+	return deps
+}
+
 // extractNodeDependencies extracts dependencies from package.json
 func (m *MetadataExtractor) extractNodeDependencies(path string) []Dependency {
 	var deps []Dependency
@@ -206,25 +251,233 @@ func (m *MetadataExtractor) extractNodeDependencies(path string) []Dependency {
 		return deps
 	}
 
-	// Add regular dependencies
-	for name, version := range pkg.Dependencies {
+	// package.json has no comments, but it does separate runtime and
+	// development dependencies
+	deps = append(deps, nodeDependencies(pkg.Dependencies, "external")...)
+	if m.includeDev {
+		deps = append(deps, nodeDependencies(pkg.DevDependencies, "dev")...)
+	}
+
+	return deps
+}
+
+// nodeDependencies converts a package.json dependency map, sorted by name
+func nodeDependencies(versions map[string]string, depType string) []Dependency {
+	names := make([]string, 0, len(versions))
+	for name := range versions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	deps := make([]Dependency, 0, len(names))
+	for _, name := range names {
 		deps = append(deps, Dependency{
 			Name:    name,
-			Version: version,
-			Type:    "external",
+			Version: versions[name],
+			Type:    depType,
 		})
 	}
-
-	// Don't include dev dependencies by default
-	// Could make this configurable
-
 	return deps
 }
 
 // ExtractMetadata extracts additional metadata like ports, endpoints, etc.
 func (m *MetadataExtractor) ExtractMetadata(comp *Component) {
-	// Try to find port configurations
-	comp.Endpoints = m.extractEndpoints(comp)
+	// Prefer OpenAPI/Swagger specs over regex-guessed routes to avoid duplicates
+	comp.APIs = m.ExtractOpenAPI(comp)
+	if len(comp.APIs) == 0 {
+		comp.Endpoints = m.extractEndpoints(comp)
+	}
+
+	// Schema-defined APIs (gRPC services, GraphQL operations)
+	rpcs := m.extractProtoEndpoints(comp)
+	operations := m.extractGraphQLEndpoints(comp)
+
+	switch {
+	case len(rpcs) > 0:
+		comp.Protocol = "grpc"
+	case len(operations) > 0:
+		comp.Protocol = "graphql"
+	case len(comp.APIs) > 0 || len(comp.Endpoints) > 0:
+		comp.Protocol = "rest"
+	}
+
+	comp.Endpoints = append(comp.Endpoints, rpcs...)
+	comp.Endpoints = append(comp.Endpoints, operations...)
+
+	comp.APISpecs = m.findAPISpecs(comp, len(rpcs) > 0, len(operations) > 0)
+
+	m.ExtractContainer(comp)
+	m.ExtractRuntime(comp)
+	comp.Configuration = m.ExtractConfiguration(comp)
+
+	// Libraries are documented by their public functions
+	if comp.Type == "library" {
+		comp.Functions = m.ExtractFunctions(comp)
+	}
+
+	// Frontends are documented by their routes
+	if comp.Type == "frontend" {
+		comp.Framework = frontendFramework(comp.Path)
+		comp.Routes = m.ExtractRoutes(comp)
+	}
+}
+
+// findAPISpecs lists the API definition files behind a component's APIs.
+// Schema files only count when they defined services or operations.
+func (m *MetadataExtractor) findAPISpecs(comp *Component, hasRPCs, hasOperations bool) []APISpec {
+	var specs []APISpec
+
+	if len(comp.APIs) > 0 {
+		for _, file := range findFiles(comp.Path, isOpenAPIFile) {
+			specs = append(specs, APISpec{Type: "openapi", Path: file})
+		}
+	}
+
+	if hasRPCs {
+		for _, file := range findFilesByExt(comp.Path, ".proto") {
+			specs = append(specs, APISpec{Type: "grpc", Path: file})
+		}
+	}
+
+	if hasOperations {
+		for _, file := range findFilesByExt(comp.Path, ".graphql", ".gql") {
+			specs = append(specs, APISpec{Type: "graphql", Path: file})
+		}
+	}
+
+	return specs
+}
+
+// extractProtoEndpoints parses .proto files for service/rpc definitions
+func (m *MetadataExtractor) extractProtoEndpoints(comp *Component) []Endpoint {
+	var endpoints []Endpoint
+
+	serviceRe := regexp.MustCompile(`^service\s+(\w+)`)
+	rpcRe := regexp.MustCompile(`rpc\s+(\w+)\s*\(\s*(stream\s+)?([\w.]+)\s*\)\s*returns\s*\(\s*(stream\s+)?([\w.]+)\s*\)`)
+
+	for _, file := range findFilesByExt(comp.Path, ".proto") {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+
+		service := ""
+		depth := 0
+
+		for _, line := range strings.Split(string(content), "\n") {
+			// Drop line comments
+			if idx := strings.Index(line, "//"); idx >= 0 {
+				line = line[:idx]
+			}
+			line = strings.TrimSpace(line)
+
+			if depth == 0 {
+				if match := serviceRe.FindStringSubmatch(line); match != nil {
+					service = match[1]
+				}
+			}
+
+			if service != "" {
+				if match := rpcRe.FindStringSubmatch(line); match != nil {
+					request := strings.TrimSpace(match[2] + match[3])
+					response := strings.TrimSpace(match[4] + match[5])
+					endpoints = append(endpoints, Endpoint{
+						Method:      "RPC",
+						Path:        service + "/" + match[1],
+						Description: fmt.Sprintf("%s(%s) returns (%s)", match[1], request, response),
+					})
+				}
+			}
+
+			depth += strings.Count(line, "{") - strings.Count(line, "}")
+			if depth <= 0 {
+				depth = 0
+				if strings.Contains(line, "}") {
+					service = ""
+				}
+			}
+		}
+	}
+
+	return endpoints
+}
+
+// extractGraphQLEndpoints parses .graphql/.gql schemas for Query, Mutation
+// and Subscription fields
+func (m *MetadataExtractor) extractGraphQLEndpoints(comp *Component) []Endpoint {
+	var endpoints []Endpoint
+
+	blockRe := regexp.MustCompile(`(?:extend\s+)?type\s+(Query|Mutation|Subscription)\b[^{]*\{([^}]*)\}`)
+	descriptionRe := regexp.MustCompile(`(?s)""".*?"""|"[^"\n]*"`)
+	commentRe := regexp.MustCompile(`#[^\n]*`)
+	argsRe := regexp.MustCompile(`(?s)\([^)]*\)`)
+	fieldRe := regexp.MustCompile(`(?m)^\s*(\w+)\s*:\s*([^\s@]+)`)
+
+	for _, file := range findFilesByExt(comp.Path, ".graphql", ".gql") {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+
+		text := descriptionRe.ReplaceAllString(string(content), "")
+		text = commentRe.ReplaceAllString(text, "")
+
+		for _, block := range blockRe.FindAllStringSubmatch(text, -1) {
+			operation := block[1]
+			fields := argsRe.ReplaceAllString(block[2], "")
+
+			for _, field := range fieldRe.FindAllStringSubmatch(fields, -1) {
+				endpoints = append(endpoints, Endpoint{
+					Method:      strings.ToUpper(operation),
+					Path:        field[1],
+					Description: fmt.Sprintf("%s field returning %s", operation, field[2]),
+				})
+			}
+		}
+	}
+
+	return endpoints
+}
+
+// findFilesByExt walks a directory and returns files with any of the given extensions
+func findFilesByExt(root string, exts ...string) []string {
+	return findFiles(root, func(path string) bool {
+		ext := strings.ToLower(filepath.Ext(path))
+		for _, e := range exts {
+			if ext == e {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// findFiles walks a directory and returns files accepted by match, skipping
+// dependency and VCS directories
+func findFiles(root string, match func(path string) bool) []string {
+	var files []string
+
+	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+
+		if info.IsDir() {
+			base := filepath.Base(path)
+			if base == "node_modules" || base == "vendor" || base == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if match(path) {
+			files = append(files, path)
+		}
+
+		return nil
+	})
+
+	return files
 }
 
 // extractEndpoints attempts to find API endpoints in the code
@@ -335,7 +588,8 @@ func (m *MetadataExtractor) extractRustDependencies(path string) []Dependency {
 	return deps
 }
 
-// extractJavaDependencies extracts dependencies from pom.xml or build.gradle
+// extractJavaDependencies extracts dependencies from pom.xml, build.gradle
+// or build.gradle.kts
 func (m *MetadataExtractor) extractJavaDependencies(path string) []Dependency {
 	var deps []Dependency
 
@@ -372,15 +626,13 @@ func (m *MetadataExtractor) extractJavaDependencies(path string) []Dependency {
 		return deps
 	}
 
-	// Try Gradle build.gradle
-	gradlePath := filepath.Join(path, "build.gradle")
-	if path == "." {
-		gradlePath = "build.gradle"
-	}
-
-	content, err = os.ReadFile(gradlePath)
+	// Try Gradle build.gradle, then the Kotlin DSL
+	content, err = os.ReadFile(filepath.Join(path, "build.gradle"))
 	if err != nil {
-		return deps
+		content, err = os.ReadFile(filepath.Join(path, "build.gradle.kts"))
+		if err != nil {
+			return deps
+		}
 	}
 
 	lines := strings.Split(string(content), "\n")
@@ -482,3 +734,183 @@ func (m *MetadataExtractor) extractCSharpDependencies(path string) []Dependency
 
 	return deps
 }
+
+// extractPHPDependencies extracts dependencies from composer.json
+func (m *MetadataExtractor) extractPHPDependencies(path string) []Dependency {
+	var deps []Dependency
+
+	content, err := os.ReadFile(filepath.Join(path, "composer.json"))
+	if err != nil {
+		return deps
+	}
+
+	var composer struct {
+		Require    map[string]string `json:"require"`
+		RequireDev map[string]string `json:"require-dev"`
+	}
+	if err := json.Unmarshal(content, &composer); err != nil {
+		return deps
+	}
+
+	deps = append(deps, composerDependencies(composer.Require, "external")...)
+	if m.includeDev {
+		deps = append(deps, composerDependencies(composer.RequireDev, "dev")...)
+	}
+
+	return deps
+}
+
+// composerDependencies converts a composer.json require map, leaving out
+// platform requirements such as php and ext-json
+func composerDependencies(versions map[string]string, depType string) []Dependency {
+	packages := make(map[string]string, len(versions))
+	for name, version := range versions {
+		if strings.Contains(name, "/") {
+			packages[name] = version
+		}
+	}
+	return nodeDependencies(packages, depType)
+}
+
+var (
+	swiftPackageRe = regexp.MustCompile(`\.package\s*\(`)
+	swiftURLRe     = regexp.MustCompile(`url\s*:\s*"([^"]+)"`)
+	swiftVersionRe = regexp.MustCompile(`(?:from|exact|branch|revision)\s*:\s*"([^"]+)"|"([^"]+)"\s*(\.\.[.<])\s*"([^"]+)"`)
+)
+
+// extractSwiftDependencies extracts dependencies from Package.swift
+func (m *MetadataExtractor) extractSwiftDependencies(path string) []Dependency {
+	var deps []Dependency
+
+	content, err := os.ReadFile(filepath.Join(path, "Package.swift"))
+	if err != nil {
+		return deps
+	}
+
+	// Each .package(...) call may nest others, e.g. .upToNextMajor(from: "1.0.0").
+	// Local .package(path:) entries are part of the repository and skipped.
+	text := string(content)
+	for _, loc := range swiftPackageRe.FindAllStringIndex(text, -1) {
+		args := balancedArgs(text[loc[1]:])
+
+		source := swiftURLRe.FindStringSubmatch(args)
+		if source == nil {
+			continue
+		}
+		name := strings.TrimSuffix(filepath.Base(strings.TrimRight(source[1], "/")), ".git")
+
+		version := ""
+		if match := swiftVersionRe.FindStringSubmatch(args); match != nil {
+			version = match[1]
+			if match[2] != "" {
+				version = match[2] + match[3] + match[4]
+			}
+		}
+
+		deps = append(deps, Dependency{
+			Name:    name,
+			Version: version,
+			Type:    "external",
+		})
+	}
+
+	return deps
+}
+
+// balancedArgs returns text up to the parenthesis closing an already opened one
+func balancedArgs(text string) string {
+	depth := 1
+	for i, r := range text {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return text[:i]
+			}
+		}
+	}
+	return text
+}
+
+// extractCppDependencies extracts dependencies from vcpkg.json or conanfile.txt
+func (m *MetadataExtractor) extractCppDependencies(path string) []Dependency {
+	var deps []Dependency
+
+	// Try vcpkg manifest first; entries are names or objects
+	content, err := os.ReadFile(filepath.Join(path, "vcpkg.json"))
+	if err == nil {
+		var manifest struct {
+			Dependencies []json.RawMessage `json:"dependencies"`
+		}
+		if err := json.Unmarshal(content, &manifest); err != nil {
+			return deps
+		}
+
+		for _, raw := range manifest.Dependencies {
+			var entry struct {
+				Name    string `json:"name"`
+				Version string `json:"version>="`
+			}
+			if err := json.Unmarshal(raw, &entry.Name); err != nil {
+				if err := json.Unmarshal(raw, &entry); err != nil {
+					continue
+				}
+			}
+			if entry.Name == "" {
+				continue
+			}
+
+			version := ""
+			if entry.Version != "" {
+				version = ">=" + entry.Version
+			}
+			deps = append(deps, Dependency{
+				Name:    entry.Name,
+				Version: version,
+				Type:    "external",
+			})
+		}
+		return deps
+	}
+
+	// Try Conan: name/version references under [requires]
+	content, err = os.ReadFile(filepath.Join(path, "conanfile.txt"))
+	if err != nil {
+		return deps
+	}
+
+	depType := ""
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+
+		if strings.HasPrefix(line, "[") {
+			switch {
+			case line == "[requires]":
+				depType = "external"
+			case m.includeDev && (line == "[tool_requires]" || line == "[build_requires]" || line == "[test_requires]"):
+				depType = "dev"
+			default:
+				depType = ""
+			}
+			continue
+		}
+
+		if depType == "" || line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		// zlib/1.2.13@user/channel#revision
+		ref, _, _ := strings.Cut(line, "@")
+		ref, _, _ = strings.Cut(ref, "#")
+		name, version, _ := strings.Cut(strings.TrimSpace(ref), "/")
+		deps = append(deps, Dependency{
+			Name:    name,
+			Version: version,
+			Type:    depType,
+		})
+	}
+
+	return deps
+}
@@ -1,46 +1,88 @@
 package analyzer
 
+import "github.com/docbrown/cli/internal/template"
+
 // RepoStructure represents the analyzed repository structure
 type RepoStructure struct {
-	RootPath   string
-	Components []Component
-	FileTree   string
-	Languages  map[string]int // language -> file count
-	TotalFiles int
+	RootPath   string         `json:"root_path"`
+	Components []Component    `json:"components"`
+	FileTree   string         `json:"file_tree"`
+	Languages  map[string]int `json:"languages"` // language -> file count
+	TotalFiles int            `json:"total_files"`
+
+	LanguageStats   []LanguageStat `json:"language_stats"`   // most prevalent first
+	PrimaryLanguage string         `json:"primary_language"` // empty if no source files were found
+
+	SensitiveFiles int `json:"sensitive_files"` // files skipped by the sensitive patterns
+	GeneratedFiles int `json:"generated_files"` // generated or minified files skipped
+
+	// AllComponents are every detected component, including any that
+	// SetTargets left out of Components
+	AllComponents []Component `json:"-"`
+}
+
+// LanguageStat is a language's share of the source files. Percentages are
+// of the files (and bytes) with a detected language.
+type LanguageStat struct {
+	Language    string  `json:"language"`
+	Files       int     `json:"files"`
+	Bytes       int64   `json:"bytes"`
+	Percent     float64 `json:"percent"`      // by file count
+	BytePercent float64 `json:"byte_percent"` // by size
 }
 
 // Component represents a detected component in the repository
 type Component struct {
-	Name         string
-	Type         string   // service, library, frontend, cli
-	Language     string
-	Path         string
-	Files        []string
-	Description  string
-	HasTests     bool
-	Dependencies []Dependency
-	Endpoints    []Endpoint
-	EntryPoint   string
+	Name           string                  `json:"name"`
+	Type           string                  `json:"type"` // service, library, frontend, cli
+	Language       string                  `json:"language"`
+	Runtime        string                  `json:"runtime,omitempty"`         // Go, Node.js, Python, ...
+	RuntimeVersion string                  `json:"runtime_version,omitempty"` // minimum version, e.g. 1.22
+	Path           string                  `json:"path"`
+	Files          []string                `json:"files"`
+	Description    string                  `json:"description"`
+	HasTests       bool                    `json:"has_tests"`
+	Dependencies   []Dependency            `json:"dependencies"`
+	Endpoints      []Endpoint              `json:"endpoints"`
+	APIs           []template.APIData      `json:"apis,omitempty"`     // parsed from OpenAPI/Swagger specs
+	Protocol       string                  `json:"protocol,omitempty"` // rest, grpc, graphql (empty if no API detected)
+	APISpecs       []APISpec               `json:"api_specs,omitempty"`
+	Functions      []template.FunctionData `json:"functions,omitempty"` // exported API of libraries
+	Framework      string                  `json:"framework,omitempty"` // React, Vue or Angular (frontends)
+	Routes         []template.RouteData    `json:"routes,omitempty"`    // client-side routes (frontends)
+	EntryPoint     string                  `json:"entry_point,omitempty"`
+	Ports          []int                   `json:"ports,omitempty"`         // from Dockerfile EXPOSE and Compose ports
+	BaseImage      string                  `json:"base_image,omitempty"`    // final Dockerfile FROM
+	Image          string                  `json:"image,omitempty"`         // Compose image
+	Configuration  map[string]string       `json:"configuration,omitempty"` // environment variable -> description
+}
+
+// APISpec is an API definition file found in a component
+type APISpec struct {
+	Type string `json:"type"` // openapi, grpc, graphql
+	Path string `json:"path"`
 }
 
 // Dependency represents a dependency
 type Dependency struct {
-	Name    string
-	Version string
-	Type    string // internal, external
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	Type    string `json:"type"`              // internal, external, dev
+	Scope   string `json:"scope,omitempty"`   // direct, indirect
+	Purpose string `json:"purpose,omitempty"` // from go.mod comments
 }
 
 // Endpoint represents an API endpoint
 type Endpoint struct {
-	Method      string
-	Path        string
-	Description string
+	Method      string `json:"method"`
+	Path        string `json:"path"`
+	Description string `json:"description,omitempty"`
 }
 
 // FileInfo contains information about a file
 type FileInfo struct {
-	Path     string
-	Size     int64
-	Language string
-	IsTest   bool
+	Path     string `json:"path"`
+	Size     int64  `json:"size"`
+	Language string `json:"language"`
+	IsTest   bool   `json:"is_test"`
 }
@@ -0,0 +1,282 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/docbrown/cli/internal/template"
+)
+
+// openAPIFileNames are the spec file names recognised in a component directory
+var openAPIFileNames = map[string]bool{
+	"openapi.yaml": true,
+	"openapi.yml":  true,
+	"openapi.json": true,
+	"swagger.yaml": true,
+	"swagger.yml":  true,
+	"swagger.json": true,
+}
+
+// openAPIMethods lists HTTP methods in the order they are documented
+var openAPIMethods = []string{"get", "post", "put", "patch", "delete", "head", "options"}
+
+// openAPISpec is the subset of an OpenAPI 3 / Swagger 2 document we use
+type openAPISpec struct {
+	OpenAPI  string                          `yaml:"openapi"`
+	Swagger  string                          `yaml:"swagger"`
+	Paths    map[string]map[string]yaml.Node `yaml:"paths"`
+	Security []map[string][]string           `yaml:"security"`
+}
+
+type openAPIOperation struct {
+	Summary     string                     `yaml:"summary"`
+	Description string                     `yaml:"description"`
+	Parameters  []openAPIParameter         `yaml:"parameters"`
+	RequestBody openAPIRequestBody         `yaml:"requestBody"`
+	Responses   map[string]openAPIResponse `yaml:"responses"`
+	Security    []map[string][]string      `yaml:"security"`
+}
+
+type openAPIParameter struct {
+	Name        string        `yaml:"name"`
+	In          string        `yaml:"in"`
+	Description string        `yaml:"description"`
+	Required    bool          `yaml:"required"`
+	Type        string        `yaml:"type"` // Swagger 2
+	Schema      openAPISchema `yaml:"schema"`
+	Example     interface{}   `yaml:"example"`
+}
+
+type openAPISchema struct {
+	Type    string      `yaml:"type"`
+	Ref     string      `yaml:"$ref"`
+	Example interface{} `yaml:"example"`
+}
+
+type openAPIRequestBody struct {
+	Content map[string]openAPIMedia `yaml:"content"`
+}
+
+type openAPIMedia struct {
+	Example interface{}   `yaml:"example"`
+	Schema  openAPISchema `yaml:"schema"`
+}
+
+type openAPIResponse struct {
+	Description string                  `yaml:"description"`
+	Content     map[string]openAPIMedia `yaml:"content"`
+	Examples    map[string]interface{}  `yaml:"examples"` // Swagger 2
+}
+
+// ExtractOpenAPI finds OpenAPI/Swagger specs in a component's directory and
+// parses them into API documentation
+func (m *MetadataExtractor) ExtractOpenAPI(comp *Component) []template.APIData {
+	var apis []template.APIData
+
+	specFiles := findFiles(comp.Path, isOpenAPIFile)
+
+	for _, file := range specFiles {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+
+		// YAML is a superset of JSON, so one decoder handles both formats
+		var spec openAPISpec
+		if err := yaml.Unmarshal(content, &spec); err != nil {
+			continue
+		}
+
+		if spec.OpenAPI == "" && spec.Swagger == "" {
+			continue
+		}
+
+		apis = append(apis, parseOpenAPIPaths(spec)...)
+	}
+
+	return apis
+}
+
+// isOpenAPIFile reports whether a file has a recognised spec file name
+func isOpenAPIFile(path string) bool {
+	return openAPIFileNames[strings.ToLower(filepath.Base(path))]
+}
+
+// parseOpenAPIPaths converts the spec's paths into APIData in a stable order
+func parseOpenAPIPaths(spec openAPISpec) []template.APIData {
+	var apis []template.APIData
+
+	paths := make([]string, 0, len(spec.Paths))
+	for path := range spec.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		item := spec.Paths[path]
+
+		// Parameters declared on the path apply to every operation
+		var shared []openAPIParameter
+		if node, ok := item["parameters"]; ok {
+			node.Decode(&shared)
+		}
+
+		for _, method := range openAPIMethods {
+			node, ok := item[method]
+			if !ok {
+				continue
+			}
+
+			var op openAPIOperation
+			if err := node.Decode(&op); err != nil {
+				continue
+			}
+
+			api := template.APIData{
+				Method:      strings.ToUpper(method),
+				Path:        path,
+				Description: op.Summary,
+			}
+			if api.Description == "" {
+				api.Description = op.Description
+			}
+
+			for _, param := range append(shared, op.Parameters...) {
+				if param.In == "body" {
+					// Swagger 2 request body
+					api.RequestExample = formatExample(param.Schema.Example)
+					continue
+				}
+				api.Parameters = append(api.Parameters, convertParameter(param))
+			}
+
+			if api.RequestExample == "" {
+				api.RequestExample = mediaExample(op.RequestBody.Content)
+			}
+
+			api.ResponseExample, api.ErrorCodes = convertResponses(op.Responses)
+
+			security := op.Security
+			if security == nil {
+				security = spec.Security
+			}
+			api.Authentication = securitySchemes(security)
+
+			apis = append(apis, api)
+		}
+	}
+
+	return apis
+}
+
+// convertParameter converts an OpenAPI parameter to template data
+func convertParameter(param openAPIParameter) template.ParameterData {
+	paramType := param.Schema.Type
+	if paramType == "" {
+		paramType = param.Type
+	}
+
+	description := param.Description
+	if param.In != "" {
+		description = strings.TrimSpace("(" + param.In + ") " + description)
+	}
+
+	example := param.Example
+	if example == nil {
+		example = param.Schema.Example
+	}
+
+	return template.ParameterData{
+		Name:        param.Name,
+		Type:        paramType,
+		Required:    param.Required || param.In == "path",
+		Description: description,
+		Example:     formatExample(example),
+	}
+}
+
+// convertResponses extracts the success response example and error codes
+func convertResponses(responses map[string]openAPIResponse) (string, []template.ErrorCodeData) {
+	codes := make([]string, 0, len(responses))
+	for code := range responses {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	example := ""
+	var errorCodes []template.ErrorCodeData
+
+	for _, code := range codes {
+		resp := responses[code]
+		status, err := strconv.Atoi(code)
+		if err != nil {
+			continue // "default" or ranges like "4XX"
+		}
+
+		if status >= 200 && status < 300 && example == "" {
+			example = mediaExample(resp.Content)
+			if example == "" {
+				if ex, ok := resp.Examples["application/json"]; ok {
+					example = formatExample(ex)
+				}
+			}
+		}
+
+		if status >= 400 {
+			errorCodes = append(errorCodes, template.ErrorCodeData{
+				Code:    status,
+				Message: resp.Description,
+			})
+		}
+	}
+
+	return example, errorCodes
+}
+
+// mediaExample returns the JSON example from a content map, if any
+func mediaExample(content map[string]openAPIMedia) string {
+	media, ok := content["application/json"]
+	if !ok {
+		return ""
+	}
+
+	if media.Example != nil {
+		return formatExample(media.Example)
+	}
+	return formatExample(media.Schema.Example)
+}
+
+// securitySchemes lists the security scheme names required by an operation
+func securitySchemes(requirements []map[string][]string) string {
+	var names []string
+	for _, req := range requirements {
+		for name := range req {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// formatExample renders an example value as indented JSON
+func formatExample(example interface{}) string {
+	if example == nil {
+		return ""
+	}
+
+	if s, ok := example.(string); ok {
+		return s
+	}
+
+	data, err := json.MarshalIndent(example, "", "  ")
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
@@ -0,0 +1,358 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/docbrown/cli/internal/template"
+)
+
+// maxRoutes caps the routes documented for one frontend
+const maxRoutes = 100
+
+// frontendFrameworks maps the package.json dependency that identifies a UI
+// framework to its display name, in detection order
+var frontendFrameworks = []struct {
+	dependency string
+	name       string
+}{
+	{"@angular/core", "Angular"},
+	{"vue", "Vue"},
+	{"react", "React"},
+}
+
+var (
+	// routerSource matches files that set up client-side routing
+	routerSource = regexp.MustCompile(`<Route[\s/>]|create(?:Browser|Hash|Memory)?Router\b|useRoutes\(|RouterModule\.for(?:Root|Child)|provideRouter\(|:\s*Routes\s*=|\broutes\s*[:=]\s*\[`)
+
+	// Route object values: a string, a JSX element, a lazy import or an identifier
+	routeString     = regexp.MustCompile("^\\s*['\"`]([^'\"`\\n]*)['\"`]")
+	routeElement    = regexp.MustCompile(`^\s*<([A-Z][\w.]*)`)
+	routeImport     = regexp.MustCompile(`^\s*(?:async\s*)?\(\s*\)\s*=>\s*import\(\s*['"]([^'"]+)['"]\s*\)(?:\s*\.then\(\s*\(?\s*\w+\s*\)?\s*=>\s*\w+\.(\w+))?`)
+	routeIdentifier = regexp.MustCompile(`^\s*([A-Za-z_$][\w$.]*)`)
+
+	// <Route> attributes
+	jsxPath      = regexp.MustCompile("\\bpath\\s*=\\s*(?:\"([^\"]*)\"|'([^']*)'|\\{\\s*['\"`]([^'\"`]*)['\"`]\\s*\\})")
+	jsxIndex     = regexp.MustCompile(`(?:^|\s)index(?:\s|=\{true\}|/|$)`)
+	jsxElement   = regexp.MustCompile(`\belement\s*=\s*\{\s*<([A-Z][\w.]*)`)
+	jsxComponent = regexp.MustCompile(`\b[Cc]omponent\s*=\s*\{\s*([A-Za-z_$][\w$.]*)\s*\}`)
+)
+
+// routeNode is a route found in source. Paths of nested routes are relative
+// to their parent's until resolved.
+type routeNode struct {
+	parent    *routeNode
+	path      string
+	hasPath   bool
+	component string
+	redirect  string
+}
+
+// ExtractRoutes finds the client-side routes of a frontend component:
+// React Router <Route> trees and route objects (createBrowserRouter,
+// useRoutes), Vue Router route arrays and Angular Routes. Routes in
+// comments are skipped.
+func (m *MetadataExtractor) ExtractRoutes(comp *Component) []template.RouteData {
+	var routes []template.RouteData
+	seen := make(map[template.RouteData]bool)
+
+	for _, file := range comp.Files {
+		switch strings.ToLower(filepath.Ext(file)) {
+		case ".js", ".jsx", ".ts", ".tsx", ".mjs":
+		default:
+			continue
+		}
+
+		content, err := os.ReadFile(filepath.Join(m.rootPath, file))
+		if err != nil || !routerSource.Match(content) {
+			continue
+		}
+
+		src := string(content)
+		for _, nodes := range [][]*routeNode{jsxRoutes(src), objectRoutes(src)} {
+			for _, node := range nodes {
+				route := node.route()
+				if !seen[route] {
+					seen[route] = true
+					routes = append(routes, route)
+				}
+			}
+		}
+	}
+
+	if len(routes) > maxRoutes {
+		routes = routes[:maxRoutes]
+	}
+	return routes
+}
+
+// frontendFramework returns the UI framework the package.json in dir
+// depends on, or "" if there is none
+func frontendFramework(dir string) string {
+	content, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return ""
+	}
+
+	var pkg struct {
+		Dependencies    map[string]string `json:"dependencies"`
+		DevDependencies map[string]string `json:"devDependencies"`
+	}
+	if err := json.Unmarshal(content, &pkg); err != nil {
+		return ""
+	}
+
+	for _, framework := range frontendFrameworks {
+		if _, ok := pkg.Dependencies[framework.dependency]; ok {
+			return framework.name
+		}
+		if _, ok := pkg.DevDependencies[framework.dependency]; ok {
+			return framework.name
+		}
+	}
+	return ""
+}
+
+// jsxRoutes finds React Router <Route> elements. Routes nested inside an
+// open <Route> are children of it.
+func jsxRoutes(src string) []*routeNode {
+	var routes []*routeNode
+	var open []*routeNode
+
+	for i := 0; i < len(src); i++ {
+		if src[i] == '/' && i+1 < len(src) && (src[i+1] == '/' || src[i+1] == '*') {
+			i = skipComment(src, i)
+			continue
+		}
+		if src[i] != '<' {
+			continue
+		}
+
+		if strings.HasPrefix(src[i:], "</Route>") {
+			if len(open) > 0 {
+				open = open[:len(open)-1]
+			}
+			continue
+		}
+		if !strings.HasPrefix(src[i:], "<Route") || i+6 >= len(src) || !strings.ContainsRune(" \t\r\n/>", rune(src[i+6])) {
+			continue
+		}
+
+		end := jsxTagEnd(src, i+6)
+		attrs := src[i+6 : end]
+		node := &routeNode{}
+		if len(open) > 0 {
+			node.parent = open[len(open)-1]
+		}
+
+		if match := jsxPath.FindStringSubmatch(attrs); match != nil {
+			node.path = match[1] + match[2] + match[3]
+			node.hasPath = true
+		} else if jsxIndex.MatchString(attrs) {
+			node.hasPath = true
+		}
+		if match := jsxElement.FindStringSubmatch(attrs); match != nil {
+			node.component = match[1]
+		} else if match := jsxComponent.FindStringSubmatch(attrs); match != nil {
+			node.component = match[1]
+		}
+
+		if node.hasPath {
+			routes = append(routes, node)
+		}
+		if end > 0 && src[end-1] != '/' {
+			open = append(open, node)
+		}
+		i = end
+	}
+
+	return routes
+}
+
+// jsxTagEnd returns the index of the '>' closing the tag whose attributes
+// start at i, skipping '>' inside {expressions} and quoted values
+func jsxTagEnd(src string, i int) int {
+	depth := 0
+	for ; i < len(src); i++ {
+		switch src[i] {
+		case '"', '\'', '`':
+			i = skipString(src, i)
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case '>':
+			if depth <= 0 {
+				return i
+			}
+		}
+	}
+	return len(src)
+}
+
+// objectRoutes finds route objects ({path: ..., component: ...}) in
+// route arrays. Objects nested in another route object (through children)
+// are its children.
+func objectRoutes(src string) []*routeNode {
+	var routes []*routeNode
+	var stack []*routeNode // enclosing object literals, nil for ( and [
+
+	parent := func() *routeNode {
+		for i := len(stack) - 1; i >= 0; i-- {
+			if stack[i] != nil {
+				return stack[i]
+			}
+		}
+		return nil
+	}
+
+	for i := 0; i < len(src); i++ {
+		switch c := src[i]; {
+		case c == '"' || c == '\'' || c == '`':
+			i = skipString(src, i)
+		case c == '/' && i+1 < len(src) && (src[i+1] == '/' || src[i+1] == '*'):
+			i = skipComment(src, i)
+		case c == '{':
+			node := &routeNode{parent: parent()}
+			stack = append(stack, node)
+			routes = append(routes, node)
+		case c == '[' || c == '(':
+			stack = append(stack, nil)
+		case c == '}' || c == ']' || c == ')':
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		case isIdentByte(c) && (i == 0 || !isIdentByte(src[i-1])):
+			end := i
+			for end < len(src) && isIdentByte(src[end]) {
+				end++
+			}
+			if len(stack) > 0 && stack[len(stack)-1] != nil {
+				value := strings.TrimLeft(src[end:], " \t")
+				if strings.HasPrefix(value, ":") {
+					setRouteField(stack[len(stack)-1], src[i:end], value[1:])
+				}
+			}
+			i = end - 1
+		}
+	}
+
+	var found []*routeNode
+	for _, node := range routes {
+		if node.hasPath {
+			found = append(found, node)
+		}
+	}
+	return found
+}
+
+// setRouteField records a route object property from the source following
+// its colon
+func setRouteField(node *routeNode, key, value string) {
+	switch key {
+	case "path":
+		if match := routeString.FindStringSubmatch(value); match != nil {
+			node.path = match[1]
+			node.hasPath = true
+		}
+	case "index":
+		if strings.HasPrefix(strings.TrimSpace(value), "true") {
+			node.hasPath = true
+		}
+	case "redirect", "redirectTo":
+		if match := routeString.FindStringSubmatch(value); match != nil {
+			node.redirect = match[1]
+		}
+	case "component", "Component", "element", "lazy", "loadComponent", "loadChildren":
+		node.component = routeComponent(value)
+	}
+}
+
+// routeComponent names the component a route renders: a JSX element, the
+// export or file a lazy import loads, or a plain identifier
+func routeComponent(value string) string {
+	if match := routeElement.FindStringSubmatch(value); match != nil {
+		return match[1]
+	}
+	if match := routeImport.FindStringSubmatch(value); match != nil {
+		if match[2] != "" {
+			return match[2]
+		}
+		name := strings.TrimSuffix(filepath.Base(match[1]), filepath.Ext(match[1]))
+		if name == "index" {
+			name = filepath.Base(filepath.Dir(match[1]))
+		}
+		return name
+	}
+	if match := routeIdentifier.FindStringSubmatch(value); match != nil && !strings.HasPrefix(match[1], "import") {
+		return match[1]
+	}
+	return ""
+}
+
+// route resolves a node's full path against its parents
+func (n *routeNode) route() template.RouteData {
+	path := n.path
+	for parent := n.parent; parent != nil && !strings.HasPrefix(path, "/"); parent = parent.parent {
+		if !parent.hasPath {
+			continue
+		}
+		switch {
+		case path == "":
+			path = parent.path
+		case parent.path != "":
+			path = strings.TrimSuffix(parent.path, "/") + "/" + path
+		}
+	}
+
+	route := template.RouteData{
+		Path:      "/" + strings.TrimPrefix(path, "/"),
+		Component: n.component,
+	}
+	if n.redirect != "" {
+		route.Description = "Redirects to " + n.redirect
+	}
+	return route
+}
+
+// skipString returns the index of the quote closing the string starting at
+// i. Unterminated ' and " strings end at the line break.
+func skipString(src string, i int) int {
+	quote := src[i]
+	for j := i + 1; j < len(src); j++ {
+		switch src[j] {
+		case '\\':
+			j++
+		case quote:
+			return j
+		case '\n':
+			if quote != '`' {
+				return j
+			}
+		}
+	}
+	return len(src)
+}
+
+// skipComment returns the index of the last character of the // or /* */
+// comment starting at i
+func skipComment(src string, i int) int {
+	if src[i+1] == '/' {
+		if end := strings.IndexByte(src[i:], '\n'); end >= 0 {
+			return i + end
+		}
+		return len(src)
+	}
+	if end := strings.Index(src[i+2:], "*/"); end >= 0 {
+		return i + 2 + end + 1
+	}
+	return len(src)
+}
+
+// isIdentByte reports whether c can be part of a JavaScript identifier
+func isIdentByte(c byte) bool {
+	return c == '_' || c == '$' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
@@ -0,0 +1,186 @@
+package analyzer
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// runtimes maps a component language to the runtime it needs and the
+// names that runtime goes by in .tool-versions
+var runtimes = map[string]struct {
+	name  string
+	tools []string
+}{
+	"go":         {"Go", []string{"golang", "go"}},
+	"javascript": {"Node.js", []string{"nodejs", "node"}},
+	"typescript": {"Node.js", []string{"nodejs", "node"}},
+	"python":     {"Python", []string{"python"}},
+	"ruby":       {"Ruby", []string{"ruby"}},
+	"rust":       {"Rust", []string{"rust"}},
+	"java":       {"Java", []string{"java"}},
+	"kotlin":     {"Java", []string{"java"}},
+	"php":        {"PHP", []string{"php"}},
+}
+
+var (
+	// versionNumber matches the first dotted version number in a constraint
+	versionNumber = regexp.MustCompile(`\d+(?:\.\d+)*`)
+	// minimumVersion matches the lower bound of a constraint like ">=3.9,<4"
+	minimumVersion = regexp.MustCompile(`>=?\s*v?(\d+(?:\.\d+)*)`)
+	// requiresPython matches requires-python in pyproject.toml
+	requiresPython = regexp.MustCompile(`(?m)^\s*requires-python\s*=\s*["']([^"']+)["']`)
+	// poetryPython matches the python entry of [tool.poetry.dependencies]
+	poetryPython = regexp.MustCompile(`(?m)^\s*python\s*=\s*["']([^"']+)["']`)
+)
+
+// ExtractRuntime finds the language runtime version a component targets,
+// looking in the component directory first and then the repository root
+func (m *MetadataExtractor) ExtractRuntime(comp *Component) {
+	runtime, ok := runtimes[comp.Language]
+	if !ok {
+		return
+	}
+
+	dirs := []string{comp.Path}
+	if filepath.Clean(comp.Path) != filepath.Clean(m.rootPath) {
+		dirs = append(dirs, m.rootPath)
+	}
+
+	for _, dir := range dirs {
+		version := languageVersion(dir, comp.Language)
+		if version == "" {
+			version = toolVersion(dir, runtime.tools)
+		}
+		if version != "" {
+			comp.Runtime = runtime.name
+			comp.RuntimeVersion = version
+			return
+		}
+	}
+}
+
+// languageVersion reads the version from a language's own files in dir
+func languageVersion(dir, language string) string {
+	switch language {
+	case "go":
+		return goVersion(filepath.Join(dir, "go.mod"))
+	case "javascript", "typescript":
+		for _, name := range []string{".nvmrc", ".node-version"} {
+			if version := versionFile(filepath.Join(dir, name)); version != "" {
+				return version
+			}
+		}
+		return nodeEngine(filepath.Join(dir, "package.json"))
+	case "python":
+		if version := versionFile(filepath.Join(dir, ".python-version")); version != "" {
+			return version
+		}
+		return pyprojectPython(filepath.Join(dir, "pyproject.toml"))
+	case "ruby":
+		return versionFile(filepath.Join(dir, ".ruby-version"))
+	}
+	return ""
+}
+
+// goVersion reads the go directive from go.mod
+func goVersion(path string) string {
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "go" {
+			return normalizeVersion(fields[1])
+		}
+	}
+	return ""
+}
+
+// versionFile reads a single-version file such as .nvmrc or .python-version.
+// Aliases like lts/iron carry no version number and are ignored.
+func versionFile(path string) string {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			return normalizeVersion(line)
+		}
+	}
+	return ""
+}
+
+// nodeEngine reads engines.node from package.json
+func nodeEngine(path string) string {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+
+	var pkg struct {
+		Engines map[string]string `json:"engines"`
+	}
+	if err := json.Unmarshal(content, &pkg); err != nil {
+		return ""
+	}
+	return normalizeVersion(pkg.Engines["node"])
+}
+
+// pyprojectPython reads requires-python, or the poetry python dependency
+func pyprojectPython(path string) string {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+
+	for _, re := range []*regexp.Regexp{requiresPython, poetryPython} {
+		if match := re.FindSubmatch(content); match != nil {
+			return normalizeVersion(string(match[1]))
+		}
+	}
+	return ""
+}
+
+// toolVersion reads the first listed version of any of tools from an asdf
+// .tool-versions file
+func toolVersion(dir string, tools []string) string {
+	content, err := os.ReadFile(filepath.Join(dir, ".tool-versions"))
+	if err != nil {
+		return ""
+	}
+
+	for _, line := range strings.Split(string(content), "\n") {
+		line, _, _ = strings.Cut(line, "#")
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		for _, tool := range tools {
+			if fields[0] == tool {
+				return normalizeVersion(fields[1])
+			}
+		}
+	}
+	return ""
+}
+
+// normalizeVersion reduces a version or constraint to its minimum version
+// number: "v20.11.0" -> "20.11.0", ">=3.9,<4" -> "3.9", "^18" -> "18".
+// It returns "" when there is no number, e.g. for "lts/*" or "system".
+func normalizeVersion(constraint string) string {
+	if match := minimumVersion.FindStringSubmatch(constraint); match != nil {
+		return match[1]
+	}
+	return versionNumber.FindString(constraint)
+}
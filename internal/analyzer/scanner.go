@@ -4,31 +4,172 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 )
 
 // Scanner scans the repository file structure
 type Scanner struct {
-	rootPath        string
-	excludePatterns []string
+	rootPath         string
+	includePatterns  []string
+	excludePatterns  []string
+	sensitive        []string
+	generated        []string
+	inspectGenerated bool
+	respectGitignore bool
+	ignore           *ignoreMatcher
 }
 
 // NewScanner creates a new scanner
-func NewScanner(rootPath string, excludePatterns []string) *Scanner {
+func NewScanner(rootPath string, includePatterns, excludePatterns []string) *Scanner {
 	return &Scanner{
 		rootPath:        rootPath,
+		includePatterns: includePatterns,
 		excludePatterns: excludePatterns,
 	}
 }
 
+// SetSensitivePatterns sets patterns for files that must never be read,
+// such as credentials and keys
+func (s *Scanner) SetSensitivePatterns(patterns []string) {
+	s.sensitive = patterns
+}
+
+// SetGeneratedPatterns sets patterns for generated and minified files to
+// skip. With inspect, files are also checked for generated-code headers
+// and minified lines.
+func (s *Scanner) SetGeneratedPatterns(patterns []string, inspect bool) {
+	s.generated = patterns
+	s.inspectGenerated = inspect
+}
+
+// SetRespectGitignore makes the scanner honor the repository's .gitignore
+// in addition to .docbrownignore
+func (s *Scanner) SetRespectGitignore(respect bool) {
+	s.respectGitignore = respect
+}
+
+// loadIgnoreFiles reads .docbrownignore (and optionally .gitignore) from the
+// repository root
+func (s *Scanner) loadIgnoreFiles() error {
+	s.ignore = &ignoreMatcher{}
+
+	files := []string{".docbrownignore"}
+	if s.respectGitignore {
+		// .docbrownignore is read last so it can override .gitignore
+		files = []string{".gitignore", ".docbrownignore"}
+	}
+
+	for _, name := range files {
+		if err := s.ignore.loadIgnoreFile(filepath.Join(s.rootPath, name)); err != nil {
+			return fmt.Errorf("failed to read %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
 // Scan scans the repository and returns the structure
 func (s *Scanner) Scan() (*RepoStructure, error) {
+	if err := s.loadIgnoreFiles(); err != nil {
+		return nil, err
+	}
+
 	structure := &RepoStructure{
 		RootPath:  s.rootPath,
 		Languages: make(map[string]int),
 	}
 
+	files, skipped, err := s.walk()
+	if err != nil {
+		return nil, err
+	}
+	structure.SensitiveFiles = skipped.sensitive
+	structure.GeneratedFiles = skipped.generated
+
+	for _, file := range files {
+		// Count languages
+		if file.Language != "" {
+			structure.Languages[file.Language]++
+		}
+		structure.TotalFiles++
+	}
+
+	structure.LanguageStats = languageStats(files)
+	if len(structure.LanguageStats) > 0 {
+		structure.PrimaryLanguage = structure.LanguageStats[0].Language
+	}
+
+	// Generate file tree
+	structure.FileTree = s.generateFileTree(files)
+
+	return structure, nil
+}
+
+// languageStats computes each language's share of the files, ordered by
+// file count, then size, then name
+func languageStats(files []FileInfo) []LanguageStat {
+	byLanguage := make(map[string]*LanguageStat)
+	var totalFiles int
+	var totalBytes int64
+
+	for _, file := range files {
+		if file.Language == "" {
+			continue
+		}
+		stat, ok := byLanguage[file.Language]
+		if !ok {
+			stat = &LanguageStat{Language: file.Language}
+			byLanguage[file.Language] = stat
+		}
+		stat.Files++
+		stat.Bytes += file.Size
+		totalFiles++
+		totalBytes += file.Size
+	}
+
+	stats := make([]LanguageStat, 0, len(byLanguage))
+	for _, stat := range byLanguage {
+		stat.Percent = float64(stat.Files) * 100 / float64(totalFiles)
+		if totalBytes > 0 {
+			stat.BytePercent = float64(stat.Bytes) * 100 / float64(totalBytes)
+		}
+		stats = append(stats, *stat)
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		a, b := stats[i], stats[j]
+		if a.Files != b.Files {
+			return a.Files > b.Files
+		}
+		if a.Bytes != b.Bytes {
+			return a.Bytes > b.Bytes
+		}
+		return a.Language < b.Language
+	})
+
+	return stats
+}
+
+// ListFiles returns the files a scan includes, relative to the root path
+func (s *Scanner) ListFiles() ([]FileInfo, error) {
+	if err := s.loadIgnoreFiles(); err != nil {
+		return nil, err
+	}
+
+	files, _, err := s.walk()
+	return files, err
+}
+
+// skippedFiles counts the files a walk left out
+type skippedFiles struct {
+	sensitive int
+	generated int
+}
+
+// walk collects the included files and counts the ones skipped
+func (s *Scanner) walk() ([]FileInfo, skippedFiles, error) {
 	var files []FileInfo
+	var skipped skippedFiles
 
 	err := filepath.Walk(s.rootPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -36,7 +177,7 @@ func (s *Scanner) Scan() (*RepoStructure, error) {
 		}
 
 		// Skip excluded paths
-		if s.shouldExclude(path) {
+		if s.shouldExclude(path, info.IsDir()) {
 			if info.IsDir() {
 				return filepath.SkipDir
 			}
@@ -48,9 +189,21 @@ func (s *Scanner) Scan() (*RepoStructure, error) {
 			return nil
 		}
 
+		if !s.shouldInclude(path) {
+			return nil
+		}
+
 		// Process file
 		relPath, _ := filepath.Rel(s.rootPath, path)
-		language := detectLanguage(path)
+		if IsSensitive(relPath, s.sensitive) {
+			skipped.sensitive++
+			return nil
+		}
+		if IsGenerated(path, relPath, s.generated, s.inspectGenerated) {
+			skipped.generated++
+			return nil
+		}
+		language := DetectLanguage(path)
 		isTest := isTestFile(path)
 
 		fileInfo := FileInfo{
@@ -62,31 +215,24 @@ func (s *Scanner) Scan() (*RepoStructure, error) {
 
 		files = append(files, fileInfo)
 
-		// Count languages
-		if language != "" {
-			structure.Languages[language]++
-		}
-
-		structure.TotalFiles++
-
 		return nil
 	})
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to scan repository: %w", err)
+		return nil, skipped, fmt.Errorf("failed to scan repository: %w", err)
 	}
 
-	// Generate file tree
-	structure.FileTree = s.generateFileTree(files)
-
-	return structure, nil
+	return files, skipped, nil
 }
 
 // shouldExclude checks if a path should be excluded
-func (s *Scanner) shouldExclude(path string) bool {
+func (s *Scanner) shouldExclude(path string, isDir bool) bool {
 	relPath, _ := filepath.Rel(s.rootPath, path)
+	if relPath == "." {
+		return false
+	}
 
-	// Always exclude
+	// Always exclude these directories (and files of the same name) at any depth
 	alwaysExclude := []string{
 		".git",
 		".docbrown",
@@ -102,16 +248,22 @@ func (s *Scanner) shouldExclude(path string) bool {
 		".cache",
 	}
 
-	for _, pattern := range alwaysExclude {
-		if strings.Contains(relPath, pattern) {
-			return true
+	for _, segment := range strings.Split(filepath.ToSlash(relPath), "/") {
+		for _, name := range alwaysExclude {
+			if segment == name {
+				return true
+			}
 		}
 	}
 
+	// Check .docbrownignore / .gitignore rules
+	if s.ignore != nil && s.ignore.Match(relPath, isDir) {
+		return true
+	}
+
 	// Check configured patterns
 	for _, pattern := range s.excludePatterns {
-		matched, _ := filepath.Match(pattern, relPath)
-		if matched {
+		if matchGlob(pattern, relPath) {
 			return true
 		}
 	}
@@ -119,51 +271,92 @@ func (s *Scanner) shouldExclude(path string) bool {
 	return false
 }
 
-// generateFileTree generates a textual representation of the file tree
-func (s *Scanner) generateFileTree(files []FileInfo) string {
-	var sb strings.Builder
+// shouldInclude checks if a file matches the include patterns. With no
+// include patterns configured every file is included.
+func (s *Scanner) shouldInclude(path string) bool {
+	if len(s.includePatterns) == 0 {
+		return true
+	}
 
-	// Group files by directory
-	dirs := make(map[string][]string)
+	relPath, _ := filepath.Rel(s.rootPath, path)
+	for _, pattern := range s.includePatterns {
+		if matchGlob(pattern, relPath) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fileTreeNode is a directory in the rendered file tree
+type fileTreeNode struct {
+	dirs  map[string]*fileTreeNode
+	files []string
+}
+
+// generateFileTree generates a textual representation of the file tree.
+// Entries are sorted (files before subdirectories) so the output is stable
+// from run to run.
+func (s *Scanner) generateFileTree(files []FileInfo) string {
+	root := &fileTreeNode{dirs: make(map[string]*fileTreeNode)}
 
 	for _, file := range files {
-		dir := filepath.Dir(file.Path)
-		if dir == "." {
-			dir = ""
+		parts := strings.Split(filepath.ToSlash(file.Path), "/")
+
+		node := root
+		for _, dir := range parts[:len(parts)-1] {
+			child, ok := node.dirs[dir]
+			if !ok {
+				child = &fileTreeNode{dirs: make(map[string]*fileTreeNode)}
+				node.dirs[dir] = child
+			}
+			node = child
 		}
-		dirs[dir] = append(dirs[dir], filepath.Base(file.Path))
+		node.files = append(node.files, parts[len(parts)-1])
 	}
 
-	// Build tree structure
+	var sb strings.Builder
 	sb.WriteString(".\n")
+	root.render(&sb, "")
 
-	var printDir func(dir string, indent string)
-	printDir = func(dir string, indent string) {
-		if files, ok := dirs[dir]; ok {
-			for _, file := range files {
-				sb.WriteString(fmt.Sprintf("%s├── %s\n", indent, file))
-			}
-		}
+	return sb.String()
+}
 
-		// Print subdirectories
-		for d := range dirs {
-			if strings.HasPrefix(d, dir) && d != dir {
-				parts := strings.Split(strings.TrimPrefix(d, dir), string(filepath.Separator))
-				if len(parts) > 0 && parts[0] != "" {
-					sb.WriteString(fmt.Sprintf("%s├── %s/\n", indent, parts[0]))
-					printDir(d, indent+"│   ")
-				}
-			}
+// render writes the node's files and subdirectories, each prefixed by indent
+func (n *fileTreeNode) render(sb *strings.Builder, indent string) {
+	sort.Strings(n.files)
+
+	dirs := make([]string, 0, len(n.dirs))
+	for dir := range n.dirs {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+
+	total := len(n.files) + len(dirs)
+	entry := 0
+
+	connector := func() (string, string) {
+		entry++
+		if entry == total {
+			return "└── ", "    "
 		}
+		return "├── ", "│   "
 	}
 
-	printDir("", "")
+	for _, file := range n.files {
+		branch, _ := connector()
+		sb.WriteString(indent + branch + file + "\n")
+	}
 
-	return sb.String()
+	for _, dir := range dirs {
+		branch, childIndent := connector()
+		sb.WriteString(indent + branch + dir + "/\n")
+		n.dirs[dir].render(sb, indent+childIndent)
+	}
 }
 
-// detectLanguage detects the programming language from file extension
-func detectLanguage(path string) string {
+// DetectLanguage detects the programming language from file extension
+func DetectLanguage(path string) string {
 	ext := strings.ToLower(filepath.Ext(path))
 
 	languageMap := map[string]string{
@@ -0,0 +1,163 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/docbrown/cli/internal/config"
+)
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"**/vendor/**", "vendor/github.com/x/y.go", true},
+		{"**/vendor/**", "services/api/vendor/lib/z.go", true},
+		{"**/vendor/**", "vendors/x.go", false},
+		{"**/vendor/**", "services/vendor.go", false},
+		{"**/*_test.go", "main_test.go", true},
+		{"**/*_test.go", "internal/analyzer/scanner_test.go", true},
+		{"**/*_test.go", "internal/analyzer/scanner.go", false},
+		{"**/*_test.go", "testdata/main_test.go.golden", false},
+		{"**/node_modules/**", "node_modules/react/index.js", true},
+		{"**/node_modules/**", "web/app/node_modules/react/cjs/react.js", true},
+		{"**/node_modules/**", "web/node_modules_backup/index.js", false},
+		{"./legacy/**", "legacy/old.go", true},
+		{"legacy/**", "src/legacy/old.go", false},
+		{"**/docker-compose.y*ml", "deploy/docker-compose.yml", true},
+		{"**/docker-compose.y*ml", "docker-compose.yaml", true},
+	}
+
+	for _, tt := range tests {
+		if got := matchGlob(tt.pattern, tt.path); got != tt.want {
+			t.Errorf("matchGlob(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestScanDefaultPatterns(t *testing.T) {
+	root := t.TempDir()
+	files := []string{
+		"go.mod",
+		"main.go",
+		"main_test.go",
+		"README.md",
+		"Dockerfile",
+		"docs/logo.png",
+		"vendor/github.com/x/y.go",
+		"services/api/openapi.yaml",
+		"services/api/server.go",
+		"services/api/rebuild.go",
+		"services/api/vendor/lib/z.go",
+		"proto/orders.proto",
+		"web/package.json",
+		"web/src/App.tsx",
+		"web/node_modules/react/index.js",
+		"android/build.gradle",
+		"android/app/Main.kt",
+		"ios/App.swift",
+		"php/index.php",
+		"native/engine.cpp",
+		"native/build/engine.o",
+	}
+	for _, file := range files {
+		path := filepath.Join(root, filepath.FromSlash(file))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	docs := config.DefaultConfig().Documentation
+	structure, err := NewScanner(root, docs.IncludePatterns, docs.ExcludePatterns).Scan()
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+
+	scanned, err := NewScanner(root, docs.IncludePatterns, docs.ExcludePatterns).ListFiles()
+	if err != nil {
+		t.Fatalf("ListFiles: %v", err)
+	}
+	var got []string
+	for _, file := range scanned {
+		got = append(got, filepath.ToSlash(file.Path))
+	}
+	sort.Strings(got)
+
+	want := []string{
+		"Dockerfile",
+		"README.md",
+		"android/app/Main.kt",
+		"android/build.gradle",
+		"go.mod",
+		"ios/App.swift",
+		"main.go",
+		"native/engine.cpp",
+		"php/index.php",
+		"proto/orders.proto",
+		"services/api/openapi.yaml",
+		"services/api/rebuild.go",
+		"services/api/server.go",
+		"web/package.json",
+		"web/src/App.tsx",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("scanned:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	if structure.TotalFiles != len(want) {
+		t.Errorf("TotalFiles = %d, want %d", structure.TotalFiles, len(want))
+	}
+	for _, language := range []string{"kotlin", "swift", "php", "cpp"} {
+		if structure.Languages[language] != 1 {
+			t.Errorf("Languages[%s] = %d, want 1", language, structure.Languages[language])
+		}
+	}
+}
+
+func TestGenerateFileTree(t *testing.T) {
+	// Deliberately unsorted, as a walk of several roots might produce
+	files := []FileInfo{
+		{Path: "services/orders/main.go"},
+		{Path: "go.mod"},
+		{Path: "services/api/handlers/users.go"},
+		{Path: "README.md"},
+		{Path: "services/api/main.go"},
+		{Path: "services/api/handlers/orders.go"},
+		{Path: "web/src/App.tsx"},
+		{Path: "Dockerfile"},
+	}
+
+	want := `.
+├── Dockerfile
+├── README.md
+├── go.mod
+├── services/
+│   ├── api/
+│   │   ├── main.go
+│   │   └── handlers/
+│   │       ├── orders.go
+│   │       └── users.go
+│   └── orders/
+│       └── main.go
+└── web/
+    └── src/
+        └── App.tsx
+`
+
+	got := (&Scanner{}).generateFileTree(files)
+	if got != want {
+		t.Errorf("file tree:\n%s\nwant:\n%s", got, want)
+	}
+
+	if empty := (&Scanner{}).generateFileTree(nil); empty != ".\n" {
+		t.Errorf("empty file tree = %q, want %q", empty, ".\n")
+	}
+}
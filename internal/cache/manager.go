@@ -7,9 +7,13 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
+	"sync"
 	"time"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/docbrown/cli/internal/fileutil"
 )
 
 // Cache represents the cached analysis and generation state
@@ -25,6 +29,16 @@ type ComponentCache struct {
 	FilesHash     string    `yaml:"files_hash"`
 	LastGenerated time.Time `yaml:"last_generated"`
 	Files         []string  `yaml:"files"`
+
+	// FileHashes maps each file to its content hash
+	FileHashes map[string]string `yaml:"file_hashes,omitempty"`
+
+	// KeyFiles are the files that were sent to the LLM
+	KeyFiles []string `yaml:"key_files,omitempty"`
+
+	// Overview is the generated overview, reused by the repository pages
+	// when the component isn't regenerated
+	Overview string `yaml:"overview,omitempty"`
 }
 
 // Manager manages the cache
@@ -33,6 +47,18 @@ type Manager struct {
 	cache     *Cache
 	enabled   bool
 	ttl       time.Duration
+
+	// hashes memoizes file hashes for the duration of a run
+	hashMu sync.Mutex
+	hashes map[string]fileHash
+}
+
+// fileHash is a memoized content hash, valid while the file's modification
+// time and size are unchanged
+type fileHash struct {
+	modTime time.Time
+	size    int64
+	hash    string
 }
 
 // NewManager creates a new cache manager
@@ -41,6 +67,7 @@ func NewManager(cachePath string, enabled bool, ttl time.Duration) *Manager {
 		cachePath: cachePath,
 		enabled:   enabled,
 		ttl:       ttl,
+		hashes:    make(map[string]fileHash),
 		cache: &Cache{
 			Version:    "1.0",
 			Components: make(map[string]ComponentCache),
@@ -90,7 +117,8 @@ func (m *Manager) Save() error {
 		return fmt.Errorf("failed to marshal cache: %w", err)
 	}
 
-	if err := os.WriteFile(m.cachePath, data, 0644); err != nil {
+	// Written atomically so an interrupted save never corrupts progress
+	if err := fileutil.WriteFileAtomic(m.cachePath, data, 0644); err != nil {
 		return fmt.Errorf("failed to write cache: %w", err)
 	}
 
@@ -122,18 +150,101 @@ func (m *Manager) IsStale(componentName string, files []string) bool {
 	return false // Cache valid
 }
 
-// Update updates the cache for a component
-func (m *Manager) Update(componentName string, files []string) {
+// KeyFilesChanged checks whether the files sent to the LLM for a component
+// differ from the last generation, either in which files were selected or in
+// their content. Changes to any other files are ignored.
+func (m *Manager) KeyFilesChanged(componentName string, keyFiles []string) bool {
+	if !m.enabled {
+		return true
+	}
+
+	cached, exists := m.cache.Components[componentName]
+	if !exists || cached.FileHashes == nil || cached.KeyFiles == nil {
+		return true // Not cached, or cached before key files were tracked
+	}
+
+	if time.Since(cached.LastGenerated) > m.ttl {
+		return true // Expired
+	}
+
+	if len(keyFiles) != len(cached.KeyFiles) {
+		return true
+	}
+
+	previous := make(map[string]bool, len(cached.KeyFiles))
+	for _, file := range cached.KeyFiles {
+		previous[file] = true
+	}
+
+	for _, file := range keyFiles {
+		if !previous[file] || m.hashFile(file) != cached.FileHashes[file] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ChangedFiles returns the files that were added, modified or removed since
+// the component was last generated, in sorted order
+func (m *Manager) ChangedFiles(componentName string, files []string) []string {
+	cached, exists := m.cache.Components[componentName]
+	if !m.enabled || !exists || cached.FileHashes == nil {
+		changed := append([]string(nil), files...)
+		sort.Strings(changed)
+		return changed
+	}
+
+	var changed []string
+	current := make(map[string]bool, len(files))
+
+	for _, file := range files {
+		current[file] = true
+		if hash, ok := cached.FileHashes[file]; !ok || hash != m.hashFile(file) {
+			changed = append(changed, file)
+		}
+	}
+
+	for file := range cached.FileHashes {
+		if !current[file] {
+			changed = append(changed, file) // Removed
+		}
+	}
+
+	sort.Strings(changed)
+	return changed
+}
+
+// Update updates the cache for a component, recording which of its files
+// were sent to the LLM and the overview generated from them
+func (m *Manager) Update(componentName string, files, keyFiles []string, overview string) {
 	if !m.enabled {
 		return
 	}
 
+	fileHashes := make(map[string]string, len(files))
+	for _, file := range files {
+		fileHashes[file] = m.hashFile(file)
+	}
+
 	m.cache.Components[componentName] = ComponentCache{
 		Hash:          m.hashComponent(componentName, files),
 		FilesHash:     m.hashFiles(files),
 		LastGenerated: time.Now(),
 		Files:         files,
+		FileHashes:    fileHashes,
+		KeyFiles:      keyFiles,
+		Overview:      overview,
+	}
+}
+
+// GetOverview returns the overview last generated for a component
+func (m *Manager) GetOverview(componentName string) (string, bool) {
+	cached, exists := m.cache.Components[componentName]
+	if !m.enabled || !exists || cached.Overview == "" {
+		return "", false
 	}
+	return cached.Overview, true
 }
 
 // GetChangedComponents returns components that have changed since last run
@@ -213,15 +324,48 @@ func (m *Manager) hashFiles(files []string) string {
 		h.Write([]byte(file))
 
 		// Hash file content
-		if f, err := os.Open(file); err == nil {
-			io.Copy(h, f)
-			f.Close()
-		}
+		h.Write([]byte(m.hashFile(file)))
 	}
 
 	return hex.EncodeToString(h.Sum(nil))
 }
 
+// hashFile creates a hash of a single file's content ("" if unreadable).
+// Hashes are memoized by path, modification time and size, so each file is
+// read at most once per run unless it changes.
+func (m *Manager) hashFile(file string) string {
+	info, err := os.Stat(file)
+	if err != nil {
+		return ""
+	}
+
+	m.hashMu.Lock()
+	cached, ok := m.hashes[file]
+	m.hashMu.Unlock()
+
+	if ok && cached.size == info.Size() && cached.modTime.Equal(info.ModTime()) {
+		return cached.hash
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return ""
+	}
+	hash := hex.EncodeToString(h.Sum(nil))
+
+	m.hashMu.Lock()
+	m.hashes[file] = fileHash{modTime: info.ModTime(), size: info.Size(), hash: hash}
+	m.hashMu.Unlock()
+
+	return hash
+}
+
 // hashComponent creates a hash of a component
 func (m *Manager) hashComponent(name string, files []string) string {
 	h := sha256.New()
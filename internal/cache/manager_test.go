@@ -0,0 +1,84 @@
+package cache
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeFiles creates n files of size bytes in a temp directory
+func writeFiles(tb testing.TB, n, size int) []string {
+	tb.Helper()
+
+	dir := tb.TempDir()
+	content := []byte(strings.Repeat("x", size))
+	files := make([]string, n)
+	for i := range files {
+		files[i] = filepath.Join(dir, fmt.Sprintf("file%d.go", i))
+		if err := os.WriteFile(files[i], content, 0644); err != nil {
+			tb.Fatal(err)
+		}
+	}
+	return files
+}
+
+func TestHashFileChanges(t *testing.T) {
+	files := writeFiles(t, 1, 16)
+	m := NewManager(filepath.Join(t.TempDir(), "cache.yaml"), true, time.Hour)
+	m.Update("api", files, files, "")
+
+	if m.IsStale("api", files) {
+		t.Fatal("unchanged component reported stale")
+	}
+
+	// Same size, later modification time
+	if err := os.WriteFile(files[0], []byte(strings.Repeat("y", 16)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(files[0], later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	if !m.IsStale("api", files) {
+		t.Error("modified component not reported stale")
+	}
+	if changed := m.ChangedFiles("api", files); len(changed) != 1 || changed[0] != files[0] {
+		t.Errorf("ChangedFiles = %v, want %v", changed, files)
+	}
+}
+
+// BenchmarkIsStale checks a cached component repeatedly, as one run does
+// for the analysis, stale check and cache update. Hashes after the first
+// check come from the memo.
+func BenchmarkIsStale(b *testing.B) {
+	files := writeFiles(b, 200, 32*1024)
+	m := NewManager(filepath.Join(b.TempDir(), "cache.yaml"), true, time.Hour)
+	m.Update("api", files, nil, "")
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if m.IsStale("api", files) {
+			b.Fatal("component reported stale")
+		}
+	}
+}
+
+// BenchmarkIsStaleCold checks with a fresh memo each time, so every file
+// is read and hashed
+func BenchmarkIsStaleCold(b *testing.B) {
+	files := writeFiles(b, 200, 32*1024)
+	m := NewManager(filepath.Join(b.TempDir(), "cache.yaml"), true, time.Hour)
+	m.Update("api", files, nil, "")
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.hashes = make(map[string]fileHash)
+		if m.IsStale("api", files) {
+			b.Fatal("component reported stale")
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ManifestFile is the name of the generated-files manifest in the cache directory
+const ManifestFile = "generated.yaml"
+
+// ValidationFile is the name of the last validation results in the cache directory
+const ValidationFile = "validation.json"
+
+// Manifest records the files written by the last generation run, so later
+// commands (e.g. pr) know exactly what was generated
+type Manifest struct {
+	Generated  time.Time `yaml:"generated"`
+	Files      []string  `yaml:"files"`
+	Components []string  `yaml:"components,omitempty"`
+}
+
+// SaveManifest writes the list of generated files and the components they
+// document. It is written even when the cache is disabled, since it
+// describes output rather than cached state.
+func SaveManifest(path string, files, components []string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	data, err := yaml.Marshal(Manifest{
+		Generated:  time.Now(),
+		Files:      files,
+		Components: components,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	return nil
+}
+
+// LoadManifest reads the list of generated files. A missing manifest
+// returns an error satisfying os.IsNotExist.
+func LoadManifest(path string) ([]string, error) {
+	manifest, err := ReadManifest(path)
+	if err != nil {
+		return nil, err
+	}
+	return manifest.Files, nil
+}
+
+// ReadManifest reads the whole manifest. A missing manifest returns an
+// error satisfying os.IsNotExist.
+func ReadManifest(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest Manifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+
+	return &manifest, nil
+}
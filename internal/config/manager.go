@@ -1,24 +1,51 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+
+	"github.com/docbrown/cli/internal/fileutil"
+	"github.com/docbrown/cli/internal/httpclient"
+	"github.com/docbrown/cli/internal/logging"
+	"github.com/docbrown/cli/internal/template"
 )
 
+// RepoConfigFile is the repository config file used when none is given
+const RepoConfigFile = ".docbrown.yaml"
+
+// secretKeys hold credentials. They come from the environment or the global
+// config and are never written to a config file, which is usually committed.
+var secretKeys = []string{
+	"git.pat",
+	"llm.anthropic.api_key",
+	"llm.openai.api_key",
+	"notifications.webhook_url",
+}
+
 // Manager handles configuration loading and merging
 type Manager struct {
-	config *Config
-	v      *viper.Viper
+	config     *Config
+	v          *viper.Viper
+	configFile string                 // explicit config file (--config); replaces RepoConfigFile
+	changes    map[string]interface{} // values Set since loading, written by Save
 }
 
-// NewManager creates a new configuration manager
-func NewManager() *Manager {
+// NewManager creates a new configuration manager. A non-empty configFile is
+// loaded instead of the repository's .docbrown.yaml and must exist.
+func NewManager(configFile string) *Manager {
 	return &Manager{
-		config: DefaultConfig(),
-		v:      viper.New(),
+		config:     DefaultConfig(),
+		v:          viper.New(),
+		configFile: configFile,
+		changes:    make(map[string]interface{}),
 	}
 }
 
@@ -34,19 +61,36 @@ func (m *Manager) Load() (*Config, error) {
 		}
 	}
 
-	// Try to load repository config
-	m.v.SetConfigName(".docbrown")
+	// Load the repository config, or the explicit file in its place. The
+	// file is always set by path: after loading the global config, viper
+	// would otherwise keep re-reading that file instead of searching.
+	repoFile := m.ConfigFile()
+	m.v.SetConfigFile(repoFile)
 	m.v.SetConfigType("yaml")
-	m.v.AddConfigPath(".")
 
 	if err := m.v.MergeInConfig(); err == nil {
 		if err := m.v.Unmarshal(config); err != nil {
 			return nil, fmt.Errorf("failed to unmarshal repo config: %w", err)
 		}
+		logging.Debugf("Using config file %s", repoFile)
+	} else if m.configFile != "" {
+		return nil, fmt.Errorf("failed to read config file %s: %w", m.configFile, err)
 	}
 
 	// Apply environment variable overrides
-	m.applyEnvOverrides(config)
+	if err := m.applyEnvOverrides(config); err != nil {
+		return nil, err
+	}
+
+	// Decrypt the stored PAT if no plaintext token was given
+	if err := decryptPAT(config); err != nil {
+		return nil, err
+	}
+
+	// Route every outbound HTTP call through the configured proxy and CAs
+	if err := httpclient.Configure(config.Network.Proxy, config.Network.CABundle); err != nil {
+		return nil, fmt.Errorf("invalid network config: %w", err)
+	}
 
 	m.config = config
 	return config, nil
@@ -65,17 +109,50 @@ func (m *Manager) loadGlobalConfig() error {
 	return m.v.ReadInConfig()
 }
 
-// applyEnvOverrides applies environment variable overrides
-func (m *Manager) applyEnvOverrides(config *Config) {
+// applyEnvOverrides applies environment variable overrides. They take
+// precedence over both config files; command-line flags are applied later
+// by each command and win over everything.
+func (m *Manager) applyEnvOverrides(config *Config) error {
 	// LLM provider
 	if provider := os.Getenv("DOCBROWN_PROVIDER"); provider != "" {
 		config.LLM.Provider = provider
 	}
+	if model := os.Getenv("DOCBROWN_MODEL"); model != "" {
+		config.LLM.Model = model
+	}
+
+	// Ollama, using the same variable names as the ollama CLI
+	if host := os.Getenv("OLLAMA_HOST"); host != "" {
+		config.LLM.Ollama.Endpoint = ollamaEndpoint(host)
+	}
+	if model := os.Getenv("OLLAMA_MODEL"); model != "" {
+		config.LLM.Ollama.Model = model
+	}
 
-	// Anthropic API key
+	// Anthropic
 	if apiKey := os.Getenv("ANTHROPIC_API_KEY"); apiKey != "" {
 		config.LLM.Anthropic.APIKey = apiKey
 	}
+	if model := os.Getenv("ANTHROPIC_MODEL"); model != "" {
+		config.LLM.Anthropic.Model = model
+	}
+	if err := envInt("ANTHROPIC_MAX_TOKENS", &config.LLM.Anthropic.MaxTokens); err != nil {
+		return err
+	}
+
+	// OpenAI
+	if apiKey := os.Getenv("OPENAI_API_KEY"); apiKey != "" {
+		config.LLM.OpenAI.APIKey = apiKey
+	}
+	if model := os.Getenv("OPENAI_MODEL"); model != "" {
+		config.LLM.OpenAI.Model = model
+	}
+	if baseURL := os.Getenv("OPENAI_BASE_URL"); baseURL != "" {
+		config.LLM.OpenAI.BaseURL = baseURL
+	}
+	if err := envInt("OPENAI_MAX_TOKENS", &config.LLM.OpenAI.MaxTokens); err != nil {
+		return err
+	}
 
 	// GitHub/GitLab/Bitbucket tokens
 	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
@@ -85,6 +162,52 @@ func (m *Manager) applyEnvOverrides(config *Config) {
 	} else if token := os.Getenv("BITBUCKET_TOKEN"); token != "" {
 		config.Git.PAT = token
 	}
+
+	// Webhook URLs embed credentials, so CI usually passes them as secrets
+	if url := os.Getenv("DOCBROWN_WEBHOOK_URL"); url != "" {
+		config.Notifications.WebhookURL = url
+	}
+
+	// Corporate networks often provide the CA bundle path in the environment
+	if bundle := os.Getenv("DOCBROWN_CA_BUNDLE"); bundle != "" {
+		config.Network.CABundle = bundle
+	}
+
+	return nil
+}
+
+// envInt sets *dst from a positive integer environment variable, if set
+func envInt(name string, dst *int) error {
+	value := os.Getenv(name)
+	if value == "" {
+		return nil
+	}
+
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || n <= 0 {
+		return fmt.Errorf("invalid %s %q: must be a positive integer", name, value)
+	}
+	*dst = n
+	return nil
+}
+
+// ollamaEndpoint turns an OLLAMA_HOST value such as "0.0.0.0:11434" into a
+// URL. Values that already have a scheme are used as-is.
+func ollamaEndpoint(host string) string {
+	host = strings.TrimRight(strings.TrimSpace(host), "/")
+	if strings.Contains(host, "://") {
+		return host
+	}
+	return "http://" + host
+}
+
+// ConfigFile returns the repository config file in use: the explicit
+// config file if one was given, otherwise .docbrown.yaml
+func (m *Manager) ConfigFile() string {
+	if m.configFile != "" {
+		return m.configFile
+	}
+	return RepoConfigFile
 }
 
 // Get retrieves a configuration value by key
@@ -92,20 +215,69 @@ func (m *Manager) Get(key string) interface{} {
 	return m.v.Get(key)
 }
 
-// Set sets a configuration value
+// Set sets a configuration value. Plaintext tokens are refused so they
+// don't end up in a committed config file.
 func (m *Manager) Set(key string, value interface{}) error {
+	if strings.EqualFold(key, "git.pat") {
+		return fmt.Errorf("refusing to store a plaintext PAT; use 'docbrown secret set-pat' instead")
+	}
+
 	m.v.Set(key, value)
+	m.changes[key] = value
 	return nil
 }
 
-// Save saves the current configuration to the repository config file
+// Save saves the current configuration to the repository config file, or
+// the explicit config file if one was given
 func (m *Manager) Save() error {
-	return m.v.WriteConfig()
+	return m.SaveAs(m.v.ConfigFileUsed())
 }
 
-// SaveAs saves the current configuration to a specific file
+// SaveAs writes the values Set since loading to a specific file, keeping the
+// settings already in it. Values from the global config and the environment
+// aren't copied in, and secrets are never written; encrypted_pat is kept.
 func (m *Manager) SaveAs(path string) error {
-	return m.v.WriteConfigAs(path)
+	file := viper.New()
+	file.SetConfigFile(path)
+	file.SetConfigType("yaml")
+	if err := file.ReadInConfig(); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+	for key, value := range m.changes {
+		file.Set(key, value)
+	}
+
+	settings := file.AllSettings()
+	for _, key := range secretKeys {
+		if value, _ := file.Get(key).(string); value != "" {
+			if key == "git.pat" {
+				logging.Warnf("⚠ Not writing plaintext git.pat to %s; use 'docbrown secret set-pat' to store it encrypted", path)
+			} else {
+				logging.Warnf("⚠ Not writing %s to %s; set it in the environment or the global config instead", key, path)
+			}
+		}
+		deleteSetting(settings, key)
+	}
+
+	data, err := yaml.Marshal(settings)
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	return fileutil.WriteFileAtomic(path, data, 0644)
+}
+
+// deleteSetting removes a dotted key from nested settings
+func deleteSetting(settings map[string]interface{}, key string) {
+	parts := strings.Split(key, ".")
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := settings[part].(map[string]interface{})
+		if !ok {
+			return
+		}
+		settings = next
+	}
+	delete(settings, parts[len(parts)-1])
 }
 
 // GetConfig returns the loaded configuration
@@ -113,27 +285,147 @@ func (m *Manager) GetConfig() *Config {
 	return m.config
 }
 
-// Validate validates the configuration
+// Validate validates the configuration. Every problem found is reported,
+// joined into a single error. Durations that don't parse at all are already
+// rejected by Load.
 func (m *Manager) Validate() error {
 	config := m.config
+	var errs []error
 
 	// Validate provider
-	validProviders := []string{"auto", "anthropic", "ollama"}
+	validProviders := []string{"auto", "anthropic", "ollama", "openai", "mock"}
 	if !contains(validProviders, config.LLM.Provider) {
-		return fmt.Errorf("invalid provider: %s (must be one of: auto, anthropic, ollama)", config.LLM.Provider)
+		errs = append(errs, fmt.Errorf("invalid provider: %s (must be one of: auto, anthropic, ollama, openai, mock)", config.LLM.Provider))
+	}
+	for _, provider := range config.LLM.Fallback {
+		if provider == "auto" || !contains(validProviders, provider) {
+			errs = append(errs, fmt.Errorf("invalid fallback provider: %s (must be one of: anthropic, ollama, openai, mock)", provider))
+		}
+	}
+
+	// Validate sampling parameters
+	if config.LLM.Temperature < 0 || config.LLM.Temperature > 2 {
+		errs = append(errs, fmt.Errorf("llm.temperature must be between 0 and 2, got %g", config.LLM.Temperature))
+	}
+	if config.LLM.TopP < 0 || config.LLM.TopP > 1 {
+		errs = append(errs, fmt.Errorf("llm.top_p must be between 0 and 1, got %g", config.LLM.TopP))
 	}
 
 	// Validate output directory
 	if config.Documentation.OutputDir == "" {
-		return fmt.Errorf("output_dir cannot be empty")
+		errs = append(errs, fmt.Errorf("output_dir cannot be empty"))
+	}
+
+	// Validate template
+	if err := validateTemplate(config.Documentation); err != nil {
+		errs = append(errs, err)
 	}
 
 	// Validate push strategy
 	validStrategies := []string{"auto", "direct", "pr"}
 	if !contains(validStrategies, config.Git.PushStrategy) {
-		return fmt.Errorf("invalid push_strategy: %s (must be one of: auto, direct, pr)", config.Git.PushStrategy)
+		errs = append(errs, fmt.Errorf("invalid push_strategy: %s (must be one of: auto, direct, pr)", config.Git.PushStrategy))
+	}
+
+	// Validate notifications
+	validFormats := []string{"generic", "slack"}
+	if !contains(validFormats, config.Notifications.Format) {
+		errs = append(errs, fmt.Errorf("invalid notifications.format: %s (must be one of: generic, slack)", config.Notifications.Format))
+	}
+
+	// Validate external analyzer mode
+	validModes := []string{"merge", "replace"}
+	if !contains(validModes, config.Analyzer.ExternalMode) {
+		errs = append(errs, fmt.Errorf("invalid analyzer.external_mode: %s (must be one of: merge, replace)", config.Analyzer.ExternalMode))
+	}
+
+	// Validate limits
+	limits := []struct {
+		key   string
+		value int
+	}{
+		{"llm.anthropic.max_tokens", config.LLM.Anthropic.MaxTokens},
+		{"llm.openai.max_tokens", config.LLM.OpenAI.MaxTokens},
+		{"llm.ollama.context_size", config.LLM.Ollama.ContextSize},
+		{"performance.max_concurrent", config.Performance.MaxConcurrent},
+		{"performance.max_files_per_component", config.Performance.MaxFilesPerComponent},
+		{"performance.max_context_tokens", config.Performance.MaxContextTokens},
+		{"performance.max_file_tokens", config.Performance.MaxFileTokens},
+		{"performance.max_retries", config.Performance.MaxRetries},
+	}
+	for _, limit := range limits {
+		if limit.value < 0 {
+			errs = append(errs, fmt.Errorf("%s cannot be negative, got %d", limit.key, limit.value))
+		}
 	}
 
+	// Validate durations
+	durations := []struct {
+		key   string
+		value time.Duration
+	}{
+		{"llm.anthropic.timeout", config.LLM.Anthropic.Timeout},
+		{"llm.ollama.timeout", config.LLM.Ollama.Timeout},
+		{"llm.openai.timeout", config.LLM.OpenAI.Timeout},
+		{"cache.ttl", config.Cache.TTL},
+		{"performance.retry_backoff", config.Performance.RetryBackoff},
+		{"performance.timeout", config.Performance.Timeout},
+		{"notifications.timeout", config.Notifications.Timeout},
+		{"analyzer.external_timeout", config.Analyzer.ExternalTimeout},
+	}
+	for _, d := range durations {
+		if d.value < 0 {
+			errs = append(errs, fmt.Errorf("%s cannot be negative, got %s", d.key, d.value))
+		}
+	}
+
+	if err := validateKeepAlive(config.LLM.Ollama.KeepAlive); err != nil {
+		errs = append(errs, err)
+	}
+
+	if config.Performance.CostLimit < 0 {
+		errs = append(errs, fmt.Errorf("performance.cost_limit cannot be negative, got %g", config.Performance.CostLimit))
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateKeepAlive checks llm.ollama.keep_alive is a duration or a number
+// of seconds, the two forms Ollama accepts
+func validateKeepAlive(keepAlive string) error {
+	if keepAlive == "" {
+		return nil
+	}
+	if _, err := strconv.Atoi(keepAlive); err == nil {
+		return nil
+	}
+	if _, err := time.ParseDuration(keepAlive); err == nil {
+		return nil
+	}
+	return fmt.Errorf("invalid llm.ollama.keep_alive: %s (use a duration such as 10m, or seconds; -1 keeps the model loaded)", keepAlive)
+}
+
+// validateTemplate checks that the configured template directories exist
+// and that the configured template is found in one of them, among the
+// installed templates or built in
+func validateTemplate(doc DocumentationConfig) error {
+	if doc.Template == "" {
+		return fmt.Errorf("template cannot be empty")
+	}
+
+	configured := doc.TemplatePaths
+	if doc.TemplatePath != "" {
+		configured = append([]string{doc.TemplatePath}, configured...)
+	}
+	for _, dir := range configured {
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			return fmt.Errorf("template directory %s is not a directory", dir)
+		}
+	}
+
+	if _, err := template.NewEngine(doc.TemplateSearchPaths()...).LoadTemplate(doc.Template); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -0,0 +1,200 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// configDirs creates a repository working directory and a home directory
+// holding the given config files; an empty content means no file
+func configDirs(t *testing.T, global, repo string) (repoDir, home string) {
+	t.Helper()
+
+	repoDir, home = t.TempDir(), t.TempDir()
+	t.Chdir(repoDir)
+	t.Setenv("HOME", home)
+	for _, name := range []string{"DOCBROWN_PROVIDER", "DOCBROWN_MODEL", "OLLAMA_MODEL"} {
+		t.Setenv(name, "")
+	}
+
+	if global != "" {
+		writeConfig(t, filepath.Join(home, ".docbrown", "config.yaml"), global)
+	}
+	if repo != "" {
+		writeConfig(t, filepath.Join(repoDir, RepoConfigFile), repo)
+	}
+	return repoDir, home
+}
+
+func writeConfig(t *testing.T, path, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadConfigPaths(t *testing.T) {
+	const global = "llm:\n  provider: openai\n  ollama:\n    model: global-model\n"
+	const repo = "llm:\n  provider: anthropic\ndocumentation:\n  output_dir: repo-docs\n"
+	const explicit = "documentation:\n  output_dir: explicit-docs\n"
+
+	tests := []struct {
+		name        string
+		global      string
+		repo        string
+		configFile  string // --config, relative to the repository
+		wantFile    string
+		provider    string
+		outputDir   string
+		ollamaModel string
+	}{
+		{
+			name:        "defaults",
+			wantFile:    RepoConfigFile,
+			provider:    DefaultConfig().LLM.Provider,
+			outputDir:   "docs",
+			ollamaModel: DefaultConfig().LLM.Ollama.Model,
+		},
+		{
+			name:        "repository file",
+			repo:        repo,
+			wantFile:    RepoConfigFile,
+			provider:    "anthropic",
+			outputDir:   "repo-docs",
+			ollamaModel: DefaultConfig().LLM.Ollama.Model,
+		},
+		{
+			name:        "global file",
+			global:      global,
+			wantFile:    RepoConfigFile,
+			provider:    "openai",
+			outputDir:   "docs",
+			ollamaModel: "global-model",
+		},
+		{
+			name:        "repository file overrides global",
+			global:      global,
+			repo:        repo,
+			wantFile:    RepoConfigFile,
+			provider:    "anthropic",
+			outputDir:   "repo-docs",
+			ollamaModel: "global-model",
+		},
+		{
+			name:        "explicit file replaces repository file",
+			global:      global,
+			repo:        repo,
+			configFile:  "ci/docbrown.yaml",
+			wantFile:    "ci/docbrown.yaml",
+			provider:    "openai",
+			outputDir:   "explicit-docs",
+			ollamaModel: "global-model",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoDir, _ := configDirs(t, tt.global, tt.repo)
+			if tt.configFile != "" {
+				writeConfig(t, filepath.Join(repoDir, tt.configFile), explicit)
+			}
+
+			m := NewManager(tt.configFile)
+			cfg, err := m.Load()
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+
+			if got := m.ConfigFile(); got != tt.wantFile {
+				t.Errorf("ConfigFile = %q, want %q", got, tt.wantFile)
+			}
+			if cfg.LLM.Provider != tt.provider {
+				t.Errorf("provider = %q, want %q", cfg.LLM.Provider, tt.provider)
+			}
+			if cfg.Documentation.OutputDir != tt.outputDir {
+				t.Errorf("output_dir = %q, want %q", cfg.Documentation.OutputDir, tt.outputDir)
+			}
+			if cfg.LLM.Ollama.Model != tt.ollamaModel {
+				t.Errorf("ollama model = %q, want %q", cfg.LLM.Ollama.Model, tt.ollamaModel)
+			}
+		})
+	}
+}
+
+func TestLoadMissingExplicitConfig(t *testing.T) {
+	configDirs(t, "", "llm:\n  provider: anthropic\n")
+
+	if _, err := NewManager("missing.yaml").Load(); err == nil {
+		t.Error("expected an error for a missing --config file")
+	}
+}
+
+func TestSaveWritesRepositoryFile(t *testing.T) {
+	const global = "llm:\n  provider: openai\n  ollama:\n    model: global-model\n  openai:\n    api_key: sk-global\n" +
+		"notifications:\n  webhook_url: https://hooks.example.com/secret\n"
+	repoDir, home := configDirs(t, global, "llm:\n  provider: anthropic\n  anthropic:\n    api_key: sk-repo\n")
+
+	m := NewManager("")
+	if _, err := m.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if err := m.Set("documentation.output_dir", "site"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	repoConfig, err := os.ReadFile(filepath.Join(repoDir, RepoConfigFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(repoConfig), "output_dir: site") {
+		t.Errorf("repository config not updated:\n%s", repoConfig)
+	}
+	if !strings.Contains(string(repoConfig), "provider: anthropic") {
+		t.Errorf("repository settings lost:\n%s", repoConfig)
+	}
+	for _, leaked := range []string{"global-model", "sk-global", "sk-repo", "hooks.example.com"} {
+		if strings.Contains(string(repoConfig), leaked) {
+			t.Errorf("repository config contains %q:\n%s", leaked, repoConfig)
+		}
+	}
+
+	globalConfig, err := os.ReadFile(filepath.Join(home, ".docbrown", "config.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(globalConfig), "site") {
+		t.Errorf("global config was modified:\n%s", globalConfig)
+	}
+}
+
+func TestSaveCreatesRepositoryFile(t *testing.T) {
+	repoDir, _ := configDirs(t, "llm:\n  provider: openai\n", "")
+
+	m := NewManager("")
+	if _, err := m.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if err := m.Set("documentation.output_dir", "site"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	repoConfig, err := os.ReadFile(filepath.Join(repoDir, RepoConfigFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(repoConfig), "documentation:\n    output_dir: site\n"; got != want {
+		t.Errorf("repository config = %q, want %q", got, want)
+	}
+}
@@ -11,52 +11,107 @@ type Config struct {
 	Quality       QualityConfig       `yaml:"quality" mapstructure:"quality"`
 	Cache         CacheConfig         `yaml:"cache" mapstructure:"cache"`
 	Performance   PerformanceConfig   `yaml:"performance" mapstructure:"performance"`
+	Notifications NotificationsConfig `yaml:"notifications" mapstructure:"notifications"`
+	Network       NetworkConfig       `yaml:"network" mapstructure:"network"`
+	Analyzer      AnalyzerConfig      `yaml:"analyzer" mapstructure:"analyzer"`
 }
 
 // LLMConfig contains LLM provider settings
 type LLMConfig struct {
-	Provider  string          `yaml:"provider" mapstructure:"provider"`
-	Anthropic AnthropicConfig `yaml:"anthropic" mapstructure:"anthropic"`
-	Ollama    OllamaConfig    `yaml:"ollama" mapstructure:"ollama"`
+	Provider    string          `yaml:"provider" mapstructure:"provider"`
+	Fallback    []string        `yaml:"fallback" mapstructure:"fallback"` // providers tried in order when the provider is unavailable
+	Model       string          `yaml:"model" mapstructure:"model"`       // overrides the selected provider's model
+	Stream      bool            `yaml:"stream" mapstructure:"stream"`
+	Temperature float64         `yaml:"temperature" mapstructure:"temperature"`
+	TopP        float64         `yaml:"top_p" mapstructure:"top_p"` // 0 = provider default
+	Seed        int             `yaml:"seed" mapstructure:"seed"`   // 0 = unset; Ollama and OpenAI only
+	Anthropic   AnthropicConfig `yaml:"anthropic" mapstructure:"anthropic"`
+	Ollama      OllamaConfig    `yaml:"ollama" mapstructure:"ollama"`
+	OpenAI      OpenAIConfig    `yaml:"openai" mapstructure:"openai"`
 }
 
 // AnthropicConfig contains Anthropic-specific settings
 type AnthropicConfig struct {
-	APIKey    string `yaml:"api_key" mapstructure:"api_key"`
-	Model     string `yaml:"model" mapstructure:"model"`
-	MaxTokens int    `yaml:"max_tokens" mapstructure:"max_tokens"`
+	APIKey    string        `yaml:"api_key" mapstructure:"api_key"`
+	Model     string        `yaml:"model" mapstructure:"model"`
+	MaxTokens int           `yaml:"max_tokens" mapstructure:"max_tokens"`
+	Timeout   time.Duration `yaml:"timeout" mapstructure:"timeout"`
 }
 
 // OllamaConfig contains Ollama-specific settings
 type OllamaConfig struct {
 	Endpoint    string        `yaml:"endpoint" mapstructure:"endpoint"`
 	Model       string        `yaml:"model" mapstructure:"model"`
+	Models      []string      `yaml:"models" mapstructure:"models"` // fallbacks, tried in order after Model
 	Timeout     time.Duration `yaml:"timeout" mapstructure:"timeout"`
 	ContextSize int           `yaml:"context_size" mapstructure:"context_size"`
+	KeepAlive   string        `yaml:"keep_alive" mapstructure:"keep_alive"` // duration or seconds; -1 keeps the model loaded
+}
+
+// TemplateSearchPaths returns the directories templates are looked up in:
+// template_path, then template_paths, or ./templates if neither is set.
+// Installed and built-in templates are searched after these.
+func (d DocumentationConfig) TemplateSearchPaths() []string {
+	var paths []string
+	if d.TemplatePath != "" {
+		paths = append(paths, d.TemplatePath)
+	}
+	paths = append(paths, d.TemplatePaths...)
+	if len(paths) == 0 {
+		paths = []string{"templates"}
+	}
+	return paths
+}
+
+// OpenAIConfig contains OpenAI-specific settings
+type OpenAIConfig struct {
+	APIKey    string        `yaml:"api_key" mapstructure:"api_key"`
+	Model     string        `yaml:"model" mapstructure:"model"`
+	MaxTokens int           `yaml:"max_tokens" mapstructure:"max_tokens"`
+	BaseURL   string        `yaml:"base_url" mapstructure:"base_url"`
+	Timeout   time.Duration `yaml:"timeout" mapstructure:"timeout"`
 }
 
 // DocumentationConfig contains documentation generation settings
 type DocumentationConfig struct {
-	Template          string   `yaml:"template" mapstructure:"template"`
-	TemplatePath      string   `yaml:"template_path" mapstructure:"template_path"`
-	GeneratedBy       string   `yaml:"generated_by" mapstructure:"generated_by"`
-	OutputDir         string   `yaml:"output_dir" mapstructure:"output_dir"`
-	IncludePatterns   []string `yaml:"include_patterns" mapstructure:"include_patterns"`
-	ExcludePatterns   []string `yaml:"exclude_patterns" mapstructure:"exclude_patterns"`
-	ExcludeSensitive  []string `yaml:"exclude_sensitive" mapstructure:"exclude_sensitive"`
+	Template               string   `yaml:"template" mapstructure:"template"`
+	TemplatePath           string   `yaml:"template_path" mapstructure:"template_path"`
+	TemplatePaths          []string `yaml:"template_paths" mapstructure:"template_paths"` // searched after TemplatePath
+	GeneratedBy            string   `yaml:"generated_by" mapstructure:"generated_by"`
+	OutputDir              string   `yaml:"output_dir" mapstructure:"output_dir"`
+	IncludePatterns        []string `yaml:"include_patterns" mapstructure:"include_patterns"`
+	ExcludePatterns        []string `yaml:"exclude_patterns" mapstructure:"exclude_patterns"`
+	ExcludeSensitive       []string `yaml:"exclude_sensitive" mapstructure:"exclude_sensitive"`
+	ExcludeGenerated       []string `yaml:"exclude_generated" mapstructure:"exclude_generated"`
+	DetectGenerated        bool     `yaml:"detect_generated" mapstructure:"detect_generated"` // also check file headers and line lengths
+	RespectGitignore       bool     `yaml:"respect_gitignore" mapstructure:"respect_gitignore"`
+	RedactSecrets          bool     `yaml:"redact_secrets" mapstructure:"redact_secrets"`
+	RedactPatterns         []string `yaml:"redact_patterns" mapstructure:"redact_patterns"`
+	IncludeDevDependencies bool     `yaml:"include_dev_dependencies" mapstructure:"include_dev_dependencies"`
+	Deterministic          bool     `yaml:"deterministic" mapstructure:"deterministic"`
 }
 
 // GitConfig contains Git-related settings
 type GitConfig struct {
-	Remote       string   `yaml:"remote" mapstructure:"remote"`
-	BaseBranch   string   `yaml:"base_branch" mapstructure:"base_branch"`
-	BranchPrefix string   `yaml:"branch_prefix" mapstructure:"branch_prefix"`
-	PushStrategy string   `yaml:"push_strategy" mapstructure:"push_strategy"`
-	PAT          string   `yaml:"pat" mapstructure:"pat"`
-	EncryptedPAT string   `yaml:"encrypted_pat" mapstructure:"encrypted_pat"`
-	PRTemplate   string   `yaml:"pr_template" mapstructure:"pr_template"`
-	AutoMerge    bool     `yaml:"auto_merge" mapstructure:"auto_merge"`
-	PRLabels     []string `yaml:"pr_labels" mapstructure:"pr_labels"`
+	Remote            string   `yaml:"remote" mapstructure:"remote"`
+	BaseBranch        string   `yaml:"base_branch" mapstructure:"base_branch"`
+	BranchPrefix      string   `yaml:"branch_prefix" mapstructure:"branch_prefix"`
+	PushStrategy      string   `yaml:"push_strategy" mapstructure:"push_strategy"`
+	PAT               string   `yaml:"pat" mapstructure:"pat"`
+	EncryptedPAT      string   `yaml:"encrypted_pat" mapstructure:"encrypted_pat"`
+	PRTemplate        string   `yaml:"pr_template" mapstructure:"pr_template"`
+	AutoMerge         bool     `yaml:"auto_merge" mapstructure:"auto_merge"`
+	PRLabels          []string `yaml:"pr_labels" mapstructure:"pr_labels"`
+	DraftPR           bool     `yaml:"draft_pr" mapstructure:"draft_pr"`
+	Reviewers         []string `yaml:"reviewers" mapstructure:"reviewers"`
+	Assignees         []string `yaml:"assignees" mapstructure:"assignees"`
+	CommitAuthorName  string   `yaml:"commit_author_name" mapstructure:"commit_author_name"`
+	CommitAuthorEmail string   `yaml:"commit_author_email" mapstructure:"commit_author_email"`
+	CommitMessage     string   `yaml:"commit_message" mapstructure:"commit_message"`
+	SigningKey        string   `yaml:"signing_key" mapstructure:"signing_key"`
+	GitHubHost        string   `yaml:"github_host" mapstructure:"github_host"`
+	GitLabHost        string   `yaml:"gitlab_host" mapstructure:"gitlab_host"`
+	APIBase           string   `yaml:"api_base" mapstructure:"api_base"`
 }
 
 // BackstageConfig contains Backstage-specific settings
@@ -70,11 +125,13 @@ type BackstageConfig struct {
 
 // QualityConfig contains quality validation settings
 type QualityConfig struct {
-	MinScore              float64 `yaml:"min_score" mapstructure:"min_score"`
-	RequireAPIDocs        bool    `yaml:"require_api_docs" mapstructure:"require_api_docs"`
-	RequireArchitecture   bool    `yaml:"require_architecture" mapstructure:"require_architecture"`
-	RequireGettingStarted bool    `yaml:"require_getting_started" mapstructure:"require_getting_started"`
-	StrictMode            bool    `yaml:"strict_mode" mapstructure:"strict_mode"`
+	MinScore              float64  `yaml:"min_score" mapstructure:"min_score"`
+	RequireAPIDocs        bool     `yaml:"require_api_docs" mapstructure:"require_api_docs"`
+	RequireArchitecture   bool     `yaml:"require_architecture" mapstructure:"require_architecture"`
+	RequireGettingStarted bool     `yaml:"require_getting_started" mapstructure:"require_getting_started"`
+	StrictMode            bool     `yaml:"strict_mode" mapstructure:"strict_mode"`
+	SpellCheck            bool     `yaml:"spell_check" mapstructure:"spell_check"`
+	SpellAllowlist        []string `yaml:"spell_allowlist" mapstructure:"spell_allowlist"`
 }
 
 // CacheConfig contains cache settings
@@ -86,37 +143,111 @@ type CacheConfig struct {
 
 // PerformanceConfig contains performance tuning settings
 type PerformanceConfig struct {
-	MaxConcurrent          int `yaml:"max_concurrent" mapstructure:"max_concurrent"`
-	MaxFilesPerComponent   int `yaml:"max_files_per_component" mapstructure:"max_files_per_component"`
-	MaxContextTokens       int `yaml:"max_context_tokens" mapstructure:"max_context_tokens"`
+	MaxConcurrent        int           `yaml:"max_concurrent" mapstructure:"max_concurrent"`
+	MaxFilesPerComponent int           `yaml:"max_files_per_component" mapstructure:"max_files_per_component"`
+	MaxContextTokens     int           `yaml:"max_context_tokens" mapstructure:"max_context_tokens"`
+	MaxFileTokens        int           `yaml:"max_file_tokens" mapstructure:"max_file_tokens"` // 0: half of max_context_tokens
+	MaxRetries           int           `yaml:"max_retries" mapstructure:"max_retries"`
+	RetryBackoff         time.Duration `yaml:"retry_backoff" mapstructure:"retry_backoff"`
+	CostLimit            float64       `yaml:"cost_limit" mapstructure:"cost_limit"`
+	Timeout              time.Duration `yaml:"timeout" mapstructure:"timeout"` // overall deadline for a run, 0 for none
+}
+
+// NotificationsConfig contains settings for completion notifications
+type NotificationsConfig struct {
+	WebhookURL string        `yaml:"webhook_url" mapstructure:"webhook_url"` // empty disables notifications
+	Format     string        `yaml:"format" mapstructure:"format"`           // generic or slack
+	Timeout    time.Duration `yaml:"timeout" mapstructure:"timeout"`
+}
+
+// NetworkConfig contains settings for outbound HTTP connections
+type NetworkConfig struct {
+	Proxy    string `yaml:"proxy" mapstructure:"proxy"`         // empty: HTTP_PROXY/HTTPS_PROXY/NO_PROXY
+	CABundle string `yaml:"ca_bundle" mapstructure:"ca_bundle"` // PEM file of extra trusted CAs
+}
+
+// AnalyzerConfig contains settings for repository analysis
+type AnalyzerConfig struct {
+	ExternalCommand string        `yaml:"external_command" mapstructure:"external_command"` // run with the repo path, prints JSON components
+	ExternalMode    string        `yaml:"external_mode" mapstructure:"external_mode"`       // merge or replace
+	ExternalTimeout time.Duration `yaml:"external_timeout" mapstructure:"external_timeout"`
 }
 
 // DefaultConfig returns a config with sensible defaults
 func DefaultConfig() *Config {
 	return &Config{
 		LLM: LLMConfig{
-			Provider: "auto",
+			Provider:    "auto",
+			Temperature: 0.2,
 			Anthropic: AnthropicConfig{
 				Model:     "claude-sonnet-4-20250514",
 				MaxTokens: 4096,
+				Timeout:   120 * time.Second,
 			},
 			Ollama: OllamaConfig{
 				Endpoint:    "http://localhost:11434",
 				Model:       "qwen2.5-coder:latest",
 				Timeout:     300 * time.Second,
 				ContextSize: 8192,
+				KeepAlive:   "10m",
+			},
+			OpenAI: OpenAIConfig{
+				Model:     "gpt-4o",
+				MaxTokens: 4096,
+				BaseURL:   "https://api.openai.com/v1",
+				Timeout:   120 * time.Second,
 			},
 		},
 		Documentation: DocumentationConfig{
 			Template:  "backstage",
 			OutputDir: "docs",
+			// Source in every language the analyzer recognises, plus the
+			// manifests, Dockerfiles, docs and API specs it reads
 			IncludePatterns: []string{
 				"**/*.go",
 				"**/*.py",
-				"**/*.ts",
 				"**/*.js",
+				"**/*.jsx",
+				"**/*.ts",
+				"**/*.tsx",
 				"**/*.java",
+				"**/*.kt",
 				"**/*.rs",
+				"**/*.rb",
+				"**/*.php",
+				"**/*.c",
+				"**/*.cpp",
+				"**/*.h",
+				"**/*.hpp",
+				"**/*.cs",
+				"**/*.swift",
+				"**/*.sh",
+				"**/go.mod",
+				"**/package.json",
+				"**/tsconfig.json",
+				"**/pyproject.toml",
+				"**/requirements*.txt",
+				"**/Cargo.toml",
+				"**/pom.xml",
+				"**/build.gradle",
+				"**/build.gradle.kts",
+				"**/Gemfile",
+				"**/composer.json",
+				"**/*.csproj",
+				"**/CMakeLists.txt",
+				"**/conanfile.txt",
+				"**/vcpkg.json",
+				"**/Makefile",
+				"**/Dockerfile",
+				"**/Dockerfile.*",
+				"**/docker-compose.y*ml",
+				"**/compose.y*ml",
+				"**/*.md",
+				"**/*.proto",
+				"**/*.graphql",
+				"**/*.gql",
+				"**/openapi.*",
+				"**/swagger.*",
 			},
 			ExcludePatterns: []string{
 				"**/test/**",
@@ -138,10 +269,24 @@ func DefaultConfig() *Config {
 				"**/.env*",
 				"**/credentials*",
 			},
+			ExcludeGenerated: []string{
+				"**/*.min.js",
+				"**/*.min.css",
+				"**/*.bundle.js",
+				"**/*.pb.go",
+				"**/*.pb.gw.go",
+				"**/*_generated.go",
+				"**/zz_generated*.go",
+				"**/*_pb2.py",
+				"**/*_pb2_grpc.py",
+				"**/*.generated.*",
+			},
+			DetectGenerated: true,
+			RedactSecrets:   true,
 		},
 		Git: GitConfig{
 			Remote:       "origin",
-			BaseBranch:   "main",
+			BaseBranch:   "", // Detected from the remote's HEAD
 			BranchPrefix: "docs/auto-gen",
 			PushStrategy: "auto",
 			PRLabels:     []string{"documentation", "automated"},
@@ -168,6 +313,17 @@ func DefaultConfig() *Config {
 			MaxConcurrent:        5,
 			MaxFilesPerComponent: 100,
 			MaxContextTokens:     8000,
+			MaxRetries:           3,
+			RetryBackoff:         1 * time.Second,
+			CostLimit:            1.00,
+		},
+		Notifications: NotificationsConfig{
+			Format:  "generic",
+			Timeout: 10 * time.Second,
+		},
+		Analyzer: AnalyzerConfig{
+			ExternalMode:    "merge",
+			ExternalTimeout: time.Minute,
 		},
 	}
 }
@@ -0,0 +1,154 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/docbrown/cli/internal/logging"
+)
+
+// PassphraseEnv names the environment variable holding the passphrase used
+// to decrypt git.encrypted_pat
+const PassphraseEnv = "DOCBROWN_PASSPHRASE"
+
+const (
+	// encryptedPATPrefix versions the encrypted format
+	encryptedPATPrefix = "v1:"
+	pbkdf2Iterations   = 600000
+	saltSize           = 16
+	keySize            = 32 // AES-256
+)
+
+// patPattern matches real GitHub, GitLab and Bitbucket tokens, as opposed
+// to placeholders like ${GITHUB_TOKEN}
+var patPattern = regexp.MustCompile(`^(?:gh[pousr]_[A-Za-z0-9]{20,}|github_pat_[A-Za-z0-9_]{20,}|glpat-[A-Za-z0-9_-]{20,}|ATBB[A-Za-z0-9]{20,})$`)
+
+// LooksLikePAT reports whether a value looks like a real access token
+func LooksLikePAT(value string) bool {
+	return patPattern.MatchString(strings.TrimSpace(value))
+}
+
+// FilePAT returns the git.pat value written in a config file, without
+// environment or global overrides
+func FilePAT(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+
+	var file struct {
+		Git struct {
+			PAT string `yaml:"pat"`
+		} `yaml:"git"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return ""
+	}
+	return file.Git.PAT
+}
+
+// EncryptPAT encrypts a token with a key derived from passphrase
+// (PBKDF2-SHA256, AES-256-GCM). The result is safe to commit.
+func EncryptPAT(pat, passphrase string) (string, error) {
+	if passphrase == "" {
+		return "", fmt.Errorf("passphrase cannot be empty")
+	}
+
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	gcm, err := patCipher(passphrase, salt)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	// salt | nonce | ciphertext
+	sealed := gcm.Seal(append(salt, nonce...), nonce, []byte(pat), nil)
+	return encryptedPATPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// DecryptPAT reverses EncryptPAT
+func DecryptPAT(encrypted, passphrase string) (string, error) {
+	encoded, ok := strings.CutPrefix(strings.TrimSpace(encrypted), encryptedPATPrefix)
+	if !ok {
+		return "", fmt.Errorf("unsupported encrypted_pat format")
+	}
+
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("invalid encrypted_pat: %w", err)
+	}
+	if len(data) < saltSize {
+		return "", fmt.Errorf("invalid encrypted_pat: too short")
+	}
+
+	gcm, err := patCipher(passphrase, data[:saltSize])
+	if err != nil {
+		return "", err
+	}
+
+	data = data[saltSize:]
+	if len(data) < gcm.NonceSize() {
+		return "", fmt.Errorf("invalid encrypted_pat: too short")
+	}
+
+	pat, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", errors.New("failed to decrypt encrypted_pat (wrong passphrase?)")
+	}
+	return string(pat), nil
+}
+
+// patCipher derives the AES-GCM cipher for a passphrase and salt
+func patCipher(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, pbkdf2Iterations, keySize)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// decryptPAT fills in git.pat from git.encrypted_pat when no plaintext
+// token was configured. Without a passphrase the token is left empty so
+// commands that don't push still work.
+func decryptPAT(config *Config) error {
+	if config.Git.PAT != "" || config.Git.EncryptedPAT == "" {
+		return nil
+	}
+
+	passphrase := os.Getenv(PassphraseEnv)
+	if passphrase == "" {
+		logging.Debugf("git.encrypted_pat is set but %s is not; leaving the PAT empty", PassphraseEnv)
+		return nil
+	}
+
+	pat, err := DecryptPAT(config.Git.EncryptedPAT, passphrase)
+	if err != nil {
+		return err
+	}
+	config.Git.PAT = pat
+	return nil
+}
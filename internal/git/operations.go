@@ -3,24 +3,45 @@ package git
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/ProtonMail/go-crypto/openpgp"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport/client"
 	"github.com/go-git/go-git/v5/plumbing/transport/http"
+
+	"github.com/docbrown/cli/internal/httpclient"
 )
 
+// Pushes and fetches over HTTP(S) use the shared transport, so they honor
+// the configured proxy and CA bundle
+func init() {
+	transport := http.NewClient(httpclient.New(0))
+	client.InstallProtocol("https", transport)
+	client.InstallProtocol("http", transport)
+}
+
 // Operations handles Git operations
 type Operations struct {
 	repo       *git.Repository
 	remoteName string
 	baseBranch string
+	gitlabHost string
+	githubHost string
+
+	authorName  string
+	authorEmail string
+	signingKey  string
 }
 
-// NewOperations creates a new Git operations handler
+// NewOperations creates a new Git operations handler. An empty baseBranch
+// means the repository's default branch is detected.
 func NewOperations(remoteName, baseBranch string) (*Operations, error) {
 	repo, err := git.PlainOpen(".")
 	if err != nil {
@@ -30,9 +51,6 @@ func NewOperations(remoteName, baseBranch string) (*Operations, error) {
 	if remoteName == "" {
 		remoteName = "origin"
 	}
-	if baseBranch == "" {
-		baseBranch = "main"
-	}
 
 	return &Operations{
 		repo:       repo,
@@ -41,6 +59,31 @@ func NewOperations(remoteName, baseBranch string) (*Operations, error) {
 	}, nil
 }
 
+// SetGitLabHost sets the host of a self-hosted GitLab instance, so remotes
+// on that host are detected as GitLab
+func (g *Operations) SetGitLabHost(host string) {
+	g.gitlabHost = host
+}
+
+// SetGitHubHost sets the host of a GitHub Enterprise Server instance, so
+// remotes on that host are detected as GitHub
+func (g *Operations) SetGitHubHost(host string) {
+	g.githubHost = host
+}
+
+// SetCommitAuthor sets the author of commits. Empty values fall back to the
+// git config user, then to DocBrown.
+func (g *Operations) SetCommitAuthor(name, email string) {
+	g.authorName = name
+	g.authorEmail = email
+}
+
+// SetSigningKey sets the path of an armored OpenPGP private key used to
+// sign commits. An empty path leaves commits unsigned.
+func (g *Operations) SetSigningKey(path string) {
+	g.signingKey = path
+}
+
 // GetCurrentBranch returns the current branch name
 func (g *Operations) GetCurrentBranch() (string, error) {
 	head, err := g.repo.Head()
@@ -51,6 +94,113 @@ func (g *Operations) GetCurrentBranch() (string, error) {
 	return head.Name().Short(), nil
 }
 
+// GetDefaultBranch returns the repository's default branch: the branch the
+// remote's HEAD points to, or the current branch if that isn't known
+func (g *Operations) GetDefaultBranch() (string, error) {
+	remoteHead := plumbing.NewRemoteHEADReferenceName(g.remoteName)
+	if ref, err := g.repo.Reference(remoteHead, false); err == nil && ref.Type() == plumbing.SymbolicReference {
+		prefix := "refs/remotes/" + g.remoteName + "/"
+		if branch := strings.TrimPrefix(ref.Target().String(), prefix); branch != ref.Target().String() {
+			return branch, nil
+		}
+	}
+
+	head, err := g.repo.Head()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine default branch: %w", err)
+	}
+	if !head.Name().IsBranch() {
+		return "", fmt.Errorf("failed to determine default branch: HEAD is detached")
+	}
+
+	return head.Name().Short(), nil
+}
+
+// GetBaseBranch returns the configured base branch, or the detected default
+// branch if none is configured
+func (g *Operations) GetBaseBranch() (string, error) {
+	if g.baseBranch != "" {
+		return g.baseBranch, nil
+	}
+	return g.GetDefaultBranch()
+}
+
+// ChangedFilesSince returns the files changed between ref and the working
+// tree: commits since the merge base with ref plus uncommitted changes.
+// Paths are relative to the repository root and sorted.
+func (g *Operations) ChangedFilesSince(ref string) ([]string, error) {
+	hash, err := g.repo.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", ref, err)
+	}
+
+	base, err := g.repo.CommitObject(*hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read commit %s: %w", ref, err)
+	}
+
+	head, err := g.repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get HEAD: %w", err)
+	}
+	headCommit, err := g.repo.CommitObject(head.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read HEAD commit: %w", err)
+	}
+
+	// Diff from the merge base so commits only on ref's side aren't included
+	if bases, err := base.MergeBase(headCommit); err == nil && len(bases) > 0 {
+		base = bases[0]
+	}
+
+	baseTree, err := base.Tree()
+	if err != nil {
+		return nil, err
+	}
+	headTree, err := headCommit.Tree()
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := object.DiffTree(baseTree, headTree)
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff against %s: %w", ref, err)
+	}
+
+	changed := make(map[string]bool)
+	for _, change := range changes {
+		if change.From.Name != "" {
+			changed[change.From.Name] = true
+		}
+		if change.To.Name != "" {
+			changed[change.To.Name] = true
+		}
+	}
+
+	// Include uncommitted and untracked files
+	w, err := g.repo.Worktree()
+	if err != nil {
+		return nil, err
+	}
+	status, err := w.Status()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status: %w", err)
+	}
+	for path, fileStatus := range status {
+		if fileStatus.Worktree != git.Unmodified || fileStatus.Staging != git.Unmodified {
+			changed[path] = true
+		}
+	}
+
+	files := make([]string, 0, len(changed))
+	for path := range changed {
+		files = append(files, path)
+	}
+	sort.Strings(files)
+
+	return files, nil
+}
+
 // CreateBranch creates a new branch
 func (g *Operations) CreateBranch(branchName string) error {
 	// Get HEAD reference
@@ -125,14 +275,19 @@ func (g *Operations) Commit(message string) (string, error) {
 		return "", fmt.Errorf("no changes to commit")
 	}
 
-	hash, err := w.Commit(message, &git.CommitOptions{
-		Author: &object.Signature{
-			Name:  "DocBrown",
-			Email: "docbrown@example.com",
-			When:  time.Now(),
-		},
-	})
+	opts := &git.CommitOptions{
+		Author: g.commitAuthor(),
+	}
+
+	if g.signingKey != "" {
+		key, err := loadSigningKey(g.signingKey)
+		if err != nil {
+			return "", err
+		}
+		opts.SignKey = key
+	}
 
+	hash, err := w.Commit(message, opts)
 	if err != nil {
 		return "", fmt.Errorf("failed to commit: %w", err)
 	}
@@ -140,6 +295,58 @@ func (g *Operations) Commit(message string) (string, error) {
 	return hash.String(), nil
 }
 
+// commitAuthor returns the configured author, filling gaps from the git
+// config user (repository, then global and system config)
+func (g *Operations) commitAuthor() *object.Signature {
+	name, email := g.authorName, g.authorEmail
+
+	if name == "" || email == "" {
+		if cfg, err := g.repo.ConfigScoped(config.SystemScope); err == nil {
+			if name == "" {
+				name = cfg.User.Name
+			}
+			if email == "" {
+				email = cfg.User.Email
+			}
+		}
+	}
+
+	if name == "" {
+		name = "DocBrown"
+	}
+	if email == "" {
+		email = "docbrown@example.com"
+	}
+
+	return &object.Signature{
+		Name:  name,
+		Email: email,
+		When:  time.Now(),
+	}
+}
+
+// loadSigningKey reads an armored OpenPGP private key for commit signing
+func loadSigningKey(path string) (*openpgp.Entity, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open signing key: %w", err)
+	}
+	defer f.Close()
+
+	entities, err := openpgp.ReadArmoredKeyRing(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signing key: %w", err)
+	}
+	if len(entities) == 0 || entities[0].PrivateKey == nil {
+		return nil, fmt.Errorf("signing key %s contains no private key", path)
+	}
+	if entities[0].PrivateKey.Encrypted {
+		return nil, fmt.Errorf("signing key %s is passphrase-protected; export an unprotected key for CI", path)
+	}
+
+	return entities[0], nil
+}
+
 // Push pushes to remote
 func (g *Operations) Push(branchName, token string) error {
 	auth := &http.BasicAuth{
@@ -166,6 +373,35 @@ func (g *Operations) PushDirect(token string) error {
 	return g.Push(currentBranch, token)
 }
 
+// Clone makes a shallow clone of url into dir. ref names a branch or tag;
+// empty means the remote's default branch.
+func Clone(url, ref, dir string) error {
+	opts := &git.CloneOptions{
+		URL:          url,
+		Depth:        1,
+		SingleBranch: true,
+	}
+	if ref == "" {
+		_, err := git.PlainClone(dir, false, opts)
+		return err
+	}
+
+	opts.ReferenceName = plumbing.NewBranchReferenceName(ref)
+	if _, err := git.PlainClone(dir, false, opts); err == nil {
+		return nil
+	}
+
+	// Not a branch; try it as a tag in a fresh directory
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	opts.ReferenceName = plumbing.NewTagReferenceName(ref)
+	if _, err := git.PlainClone(dir, false, opts); err != nil {
+		return fmt.Errorf("no branch or tag %q: %w", ref, err)
+	}
+	return nil
+}
+
 // GetRemoteURL gets the remote URL
 func (g *Operations) GetRemoteURL() (string, error) {
 	remote, err := g.repo.Remote(g.remoteName)
@@ -181,6 +417,44 @@ func (g *Operations) GetRemoteURL() (string, error) {
 	return cfg.URLs[0], nil
 }
 
+// HeadCommitTime returns the committer time of the HEAD commit
+func (g *Operations) HeadCommitTime() (time.Time, error) {
+	head, err := g.repo.Head()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	commit, err := g.repo.CommitObject(head.Hash())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to read HEAD commit: %w", err)
+	}
+
+	return commit.Committer.When, nil
+}
+
+// IsTracked reports whether a file is in the index, i.e. committed or
+// about to be
+func (g *Operations) IsTracked(path string) bool {
+	w, err := g.repo.Worktree()
+	if err != nil {
+		return false
+	}
+
+	if filepath.IsAbs(path) {
+		if path, err = filepath.Rel(w.Filesystem.Root(), path); err != nil {
+			return false
+		}
+	}
+
+	idx, err := g.repo.Storer.Index()
+	if err != nil {
+		return false
+	}
+
+	_, err = idx.Entry(filepath.ToSlash(filepath.Clean(path)))
+	return err == nil
+}
+
 // DocsExist checks if documentation already exists
 func (g *Operations) DocsExist() bool {
 	_, err := os.Stat("docs")
@@ -216,11 +490,11 @@ func (g *Operations) DetectPlatform() (string, error) {
 		return "", err
 	}
 
-	if strings.Contains(url, "github.com") {
+	if strings.Contains(url, "github.com") || sameHost(RemoteHost(url), g.githubHost) {
 		return "github", nil
 	}
 
-	if strings.Contains(url, "gitlab.com") {
+	if strings.Contains(url, "gitlab.com") || sameHost(RemoteHost(url), g.gitlabHost) {
 		return "gitlab", nil
 	}
 
@@ -231,45 +505,146 @@ func (g *Operations) DetectPlatform() (string, error) {
 	return "", fmt.Errorf("unsupported platform for URL: %s", url)
 }
 
-// ParseGitHubURL parses a GitHub URL to extract owner and repo
-func ParseGitHubURL(url string) (owner, repo string, err error) {
-	// Handle git@github.com:owner/repo.git
-	if strings.HasPrefix(url, "git@github.com:") {
-		parts := strings.TrimPrefix(url, "git@github.com:")
-		parts = strings.TrimSuffix(parts, ".git")
-		split := strings.Split(parts, "/")
-		if len(split) == 2 {
-			return split[0], split[1], nil
-		}
+// BrowseURL converts a remote URL into an HTTPS URL for browsing the
+// repository, e.g. git@github.com:owner/repo.git becomes
+// https://github.com/owner/repo. It returns "" for URLs it can't convert.
+func BrowseURL(remoteURL string) string {
+	host, path := splitRemoteURL(remoteURL)
+	if host == "" || path == "" {
+		return ""
+	}
+
+	return "https://" + host + "/" + path
+}
+
+// RemoteHost returns the host of a remote URL (with any HTTP port, but
+// without credentials or SSH ports), or "" if it can't be parsed
+func RemoteHost(remoteURL string) string {
+	host, _ := splitRemoteURL(remoteURL)
+	return host
+}
+
+// APIBaseURL returns the API root for a self-hosted platform on the
+// remote's host, e.g. https://git.company.internal/api/v4 for GitLab
+func APIBaseURL(remoteURL, apiPath string) string {
+	host := RemoteHost(remoteURL)
+	if host == "" {
+		return ""
 	}
+	return "https://" + host + apiPath
+}
 
-	// Handle https://github.com/owner/repo.git
-	if strings.HasPrefix(url, "https://github.com/") {
-		parts := strings.TrimPrefix(url, "https://github.com/")
-		parts = strings.TrimSuffix(parts, ".git")
-		split := strings.Split(parts, "/")
-		if len(split) == 2 {
-			return split[0], split[1], nil
+// splitRemoteURL splits a remote URL into its host and repository path
+// (without a .git suffix). Both are "" for URLs it can't parse.
+func splitRemoteURL(remoteURL string) (host, path string) {
+	url := strings.TrimSpace(remoteURL)
+
+	switch {
+	case strings.HasPrefix(url, "https://") || strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "ssh://"):
+		rest := url[strings.Index(url, "://")+3:]
+		slash := strings.Index(rest, "/")
+		if slash < 0 {
+			return "", ""
+		}
+		host, path = rest[:slash], rest[slash+1:]
+
+		// Drop credentials (user@, user:token@) and ssh ports
+		if at := strings.LastIndex(host, "@"); at >= 0 {
+			host = host[at+1:]
+		}
+		if strings.HasPrefix(url, "ssh://") {
+			if colon := strings.Index(host, ":"); colon >= 0 {
+				host = host[:colon]
+			}
 		}
+	case strings.Contains(url, "@") && strings.Contains(url, ":"):
+		// scp-like syntax: git@github.com:owner/repo.git
+		rest := url[strings.Index(url, "@")+1:]
+		colon := strings.Index(rest, ":")
+		host, path = rest[:colon], rest[colon+1:]
+	default:
+		return "", ""
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	if host == "" || path == "" {
+		return "", ""
+	}
+
+	return host, path
+}
+
+// sameHost reports whether a remote host matches a configured host. The
+// configured value may include a scheme or port, which are ignored.
+func sameHost(remoteHost, configured string) bool {
+	if remoteHost == "" || configured == "" {
+		return false
+	}
+
+	configured = strings.TrimSuffix(configured, "/")
+	if idx := strings.Index(configured, "://"); idx >= 0 {
+		configured = configured[idx+3:]
+	}
+
+	return strings.EqualFold(stripPort(remoteHost), stripPort(configured))
+}
+
+// stripPort removes a trailing :port from a host
+func stripPort(host string) string {
+	if colon := strings.LastIndex(host, ":"); colon >= 0 {
+		return host[:colon]
+	}
+	return host
+}
+
+// ParseGitHubURL parses a GitHub URL (github.com or GitHub Enterprise
+// Server) to extract owner and repo
+func ParseGitHubURL(url string) (owner, repo string, err error) {
+	_, path := splitRemoteURL(url)
+	split := strings.Split(path, "/")
+	if len(split) == 2 && split[0] != "" && split[1] != "" {
+		return split[0], split[1], nil
 	}
 
 	return "", "", fmt.Errorf("invalid GitHub URL: %s", url)
 }
 
-// ParseGitLabURL parses a GitLab URL
+// ParseGitLabURL parses a GitLab URL (gitlab.com or a self-hosted instance)
+// into a URL-encoded project path, including any subgroups
 func ParseGitLabURL(url string) (projectID string, err error) {
-	// Simplified - would need more robust parsing
-	if strings.HasPrefix(url, "git@gitlab.com:") {
-		parts := strings.TrimPrefix(url, "git@gitlab.com:")
-		parts = strings.TrimSuffix(parts, ".git")
-		return strings.ReplaceAll(parts, "/", "%2F"), nil
+	_, path := splitRemoteURL(url)
+	if !strings.Contains(path, "/") {
+		return "", fmt.Errorf("invalid GitLab URL: %s", url)
+	}
+
+	return strings.ReplaceAll(path, "/", "%2F"), nil
+}
+
+// ParseBitbucketURL parses a Bitbucket URL to extract workspace and repo slug
+func ParseBitbucketURL(url string) (workspace, repoSlug string, err error) {
+	var path string
+
+	// Handle git@bitbucket.org:workspace/repo.git
+	if strings.HasPrefix(url, "git@bitbucket.org:") {
+		path = strings.TrimPrefix(url, "git@bitbucket.org:")
+	}
+
+	// Handle https://[user@]bitbucket.org/workspace/repo.git
+	if strings.HasPrefix(url, "https://") {
+		rest := strings.TrimPrefix(url, "https://")
+		if idx := strings.Index(rest, "@"); idx >= 0 && idx < strings.Index(rest, "/") {
+			rest = rest[idx+1:]
+		}
+		if strings.HasPrefix(rest, "bitbucket.org/") {
+			path = strings.TrimPrefix(rest, "bitbucket.org/")
+		}
 	}
 
-	if strings.HasPrefix(url, "https://gitlab.com/") {
-		parts := strings.TrimPrefix(url, "https://gitlab.com/")
-		parts = strings.TrimSuffix(parts, ".git")
-		return strings.ReplaceAll(parts, "/", "%2F"), nil
+	path = strings.TrimSuffix(strings.TrimSuffix(path, "/"), ".git")
+	split := strings.Split(path, "/")
+	if len(split) == 2 && split[0] != "" && split[1] != "" {
+		return split[0], split[1], nil
 	}
 
-	return "", fmt.Errorf("invalid GitLab URL: %s", url)
+	return "", "", fmt.Errorf("invalid Bitbucket URL: %s", url)
 }
@@ -0,0 +1,214 @@
+package platforms
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	neturl "net/url"
+	"strings"
+)
+
+// Bitbucket implements Platform for Bitbucket Cloud
+type Bitbucket struct {
+	workspace string
+	repoSlug  string
+	token     string
+}
+
+// NewBitbucket creates a new Bitbucket platform
+func NewBitbucket(workspace, repoSlug, token string) *Bitbucket {
+	return &Bitbucket{
+		workspace: workspace,
+		repoSlug:  repoSlug,
+		token:     token,
+	}
+}
+
+// Name returns the platform name
+func (bb *Bitbucket) Name() string {
+	return "bitbucket"
+}
+
+// CreatePR creates a pull request on Bitbucket Cloud. Bitbucket pull
+// requests have no assignees, so opts.Assignees is ignored.
+func (bb *Bitbucket) CreatePR(opts PROptions) (string, error) {
+	url := bb.pullRequestsURL()
+
+	reqBody := map[string]interface{}{
+		"title":       opts.Title,
+		"description": opts.Body,
+		"source": map[string]interface{}{
+			"branch": map[string]string{"name": opts.Branch},
+		},
+		"destination": map[string]interface{}{
+			"branch": map[string]string{"name": opts.BaseBranch},
+		},
+		"close_source_branch": true,
+		"draft":               opts.Draft,
+	}
+
+	if len(opts.Reviewers) > 0 {
+		reqBody["reviewers"] = bitbucketReviewers(opts.Reviewers)
+	}
+
+	bodyBytes, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", url, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+bb.token)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf("Bitbucket API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var result struct {
+		ID    int `json:"id"`
+		Links struct {
+			HTML struct {
+				Href string `json:"href"`
+			} `json:"html"`
+		} `json:"links"`
+	}
+
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if opts.AutoMerge {
+		return result.Links.HTML.Href, &AutoMergeError{Reason: "Bitbucket Cloud has no API for auto-merge"}
+	}
+
+	return result.Links.HTML.Href, nil
+}
+
+// FindPR returns the open pull request from opts.Branch into opts.BaseBranch
+func (bb *Bitbucket) FindPR(opts PROptions) (*PullRequest, error) {
+	query := neturl.Values{
+		"state": {"OPEN"},
+		"q": {fmt.Sprintf(`source.branch.name = %q AND destination.branch.name = %q`,
+			opts.Branch, opts.BaseBranch)},
+	}
+
+	var page struct {
+		Values []struct {
+			ID    int `json:"id"`
+			Links struct {
+				HTML struct {
+					Href string `json:"href"`
+				} `json:"html"`
+			} `json:"links"`
+		} `json:"values"`
+	}
+	if err := bb.do("GET", bb.pullRequestsURL()+"?"+query.Encode(), nil, http.StatusOK, &page); err != nil {
+		return nil, err
+	}
+	if len(page.Values) == 0 {
+		return nil, nil
+	}
+
+	return &PullRequest{Number: page.Values[0].ID, URL: page.Values[0].Links.HTML.Href}, nil
+}
+
+// UpdatePR updates the title, description and reviewers of an open pull
+// request. Bitbucket pull requests have no labels, so opts.Labels is ignored.
+func (bb *Bitbucket) UpdatePR(pr *PullRequest, opts PROptions) (string, error) {
+	reqBody := map[string]interface{}{
+		"title":       opts.Title,
+		"description": opts.Body,
+	}
+	if len(opts.Reviewers) > 0 {
+		reqBody["reviewers"] = bitbucketReviewers(opts.Reviewers)
+	}
+
+	url := fmt.Sprintf("%s/%d", bb.pullRequestsURL(), pr.Number)
+	if err := bb.do("PUT", url, reqBody, http.StatusOK, nil); err != nil {
+		return "", err
+	}
+
+	if opts.AutoMerge {
+		return pr.URL, &AutoMergeError{Reason: "Bitbucket Cloud has no API for auto-merge"}
+	}
+
+	return pr.URL, nil
+}
+
+// pullRequestsURL returns the repository's pull requests endpoint
+func (bb *Bitbucket) pullRequestsURL() string {
+	return fmt.Sprintf("https://api.bitbucket.org/2.0/repositories/%s/%s/pullrequests", bb.workspace, bb.repoSlug)
+}
+
+// do sends a request to the Bitbucket API, checks the status and decodes
+// the response into result if it is non-nil
+func (bb *Bitbucket) do(method, url string, reqBody interface{}, wantStatus int, result interface{}) error {
+	var reader io.Reader
+	if reqBody != nil {
+		bodyBytes, err := json.Marshal(reqBody)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(bodyBytes)
+	}
+
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+bb.token)
+	req.Header.Set("Accept", "application/json")
+	if reqBody != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode != wantStatus {
+		return fmt.Errorf("Bitbucket API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	if result != nil {
+		if err := json.Unmarshal(body, result); err != nil {
+			return fmt.Errorf("failed to parse response: %w", err)
+		}
+	}
+	return nil
+}
+
+// bitbucketReviewers converts reviewer identifiers to Bitbucket user objects.
+// Bitbucket Cloud no longer accepts usernames, so reviewers are given either
+// as UUIDs ("{...}") or Atlassian account IDs.
+func bitbucketReviewers(reviewers []string) []map[string]string {
+	result := make([]map[string]string, 0, len(reviewers))
+	for _, reviewer := range reviewers {
+		if strings.HasPrefix(reviewer, "{") {
+			result = append(result, map[string]string{"uuid": reviewer})
+		} else {
+			result = append(result, map[string]string{"account_id": reviewer})
+		}
+	}
+	return result
+}
@@ -0,0 +1,208 @@
+package platforms
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docbrown/cli/internal/httpclient"
+	"github.com/docbrown/cli/internal/logging"
+)
+
+const (
+	// rateLimitRetries is how many times a rate-limited request is retried
+	rateLimitRetries = 3
+	// rateLimitBackoff is the first retry delay when the response doesn't
+	// say how long to wait; it doubles on each attempt
+	rateLimitBackoff = 2 * time.Second
+	// maxRateLimitWait is the longest we wait for a limit to reset before
+	// giving up and returning the error response
+	maxRateLimitWait = 2 * time.Minute
+	// minWriteInterval spaces out mutating requests, as GitHub recommends
+	// to avoid secondary rate limits
+	minWriteInterval = time.Second
+)
+
+// httpClient is shared by all platform API calls. It paces writes, waits
+// when the remaining quota is exhausted, and retries 429 and rate-limited
+// 403 responses, honoring Retry-After and the rate limit reset headers.
+var httpClient = &http.Client{
+	Transport: &rateLimitTransport{base: httpclient.Transport()},
+}
+
+// rateLimitTransport wraps a RoundTripper with rate limit handling
+type rateLimitTransport struct {
+	base http.RoundTripper
+
+	mu        sync.Mutex
+	lastWrite time.Time
+	resetAt   time.Time // when an exhausted quota resets, zero if not exhausted
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		if err := t.pace(req); err != nil {
+			return nil, err
+		}
+
+		attemptReq := req
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			attemptReq = req.Clone(req.Context())
+			attemptReq.Body = body
+		}
+
+		resp, err := t.base.RoundTrip(attemptReq)
+		if err != nil {
+			return nil, err
+		}
+		t.record(resp)
+
+		wait, limited := rateLimitWait(resp, attempt)
+		if !limited || attempt >= rateLimitRetries || wait > maxRateLimitWait ||
+			(req.Body != nil && req.GetBody == nil) {
+			return resp, nil
+		}
+
+		logging.Warnf("⚠ Rate limited by %s (status %d), retrying in %s (attempt %d/%d)",
+			req.URL.Host, resp.StatusCode, wait.Round(time.Second), attempt+1, rateLimitRetries)
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		if err := sleep(req, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// pace waits out an exhausted quota and spaces out mutating requests
+func (t *rateLimitTransport) pace(req *http.Request) error {
+	t.mu.Lock()
+	var wait time.Duration
+	if until := time.Until(t.resetAt); until > 0 && until <= maxRateLimitWait {
+		wait = until
+	}
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		if gap := minWriteInterval - time.Since(t.lastWrite); gap > wait {
+			wait = gap
+		}
+		t.lastWrite = time.Now().Add(wait)
+	}
+	t.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+	logging.Debugf("Waiting %s before calling %s", wait.Round(time.Millisecond), req.URL.Host)
+	return sleep(req, wait)
+}
+
+// record notes when the quota is exhausted so later requests wait for the reset
+func (t *rateLimitTransport) record(resp *http.Response) {
+	remaining, ok := headerInt(resp.Header, "X-RateLimit-Remaining", "RateLimit-Remaining")
+	if !ok {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.resetAt = time.Time{}
+	if remaining == 0 {
+		t.resetAt = rateLimitReset(resp.Header)
+	}
+}
+
+// rateLimitWait reports whether a response is a rate limit error and how
+// long to wait before retrying it
+func rateLimitWait(resp *http.Response, attempt int) (time.Duration, bool) {
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+	case http.StatusForbidden:
+		// GitHub also answers 403 for primary and secondary rate limits
+		remaining, ok := headerInt(resp.Header, "X-RateLimit-Remaining", "RateLimit-Remaining")
+		if !(ok && remaining == 0) && resp.Header.Get("Retry-After") == "" && !secondaryRateLimit(resp) {
+			return 0, false
+		}
+	default:
+		return 0, false
+	}
+
+	if wait := parseRetryAfter(resp.Header.Get("Retry-After")); wait > 0 {
+		return wait, true
+	}
+	if reset := rateLimitReset(resp.Header); !reset.IsZero() {
+		return max(time.Until(reset), time.Second), true
+	}
+	return rateLimitBackoff << attempt, true
+}
+
+// secondaryRateLimit reports whether a 403 body mentions a rate limit. The
+// body is restored so callers can still read it.
+func secondaryRateLimit(resp *http.Response) bool {
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return strings.Contains(strings.ToLower(string(body)), "rate limit")
+}
+
+// rateLimitReset returns when the quota resets, from X-RateLimit-Reset
+// (GitHub) or RateLimit-Reset (GitLab), both Unix timestamps
+func rateLimitReset(header http.Header) time.Time {
+	reset, ok := headerInt(header, "X-RateLimit-Reset", "RateLimit-Reset")
+	if !ok || reset <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(int64(reset), 0)
+}
+
+// headerInt returns the first of names present in header as an integer
+func headerInt(header http.Header, names ...string) (int, bool) {
+	for _, name := range names {
+		if value := header.Get(name); value != "" {
+			n, err := strconv.Atoi(value)
+			return n, err == nil
+		}
+	}
+	return 0, false
+}
+
+// parseRetryAfter parses a Retry-After header (seconds or HTTP date)
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if when, err := http.ParseTime(value); err == nil {
+		if d := time.Until(when); d > 0 {
+			return d
+		}
+	}
+
+	return 0
+}
+
+// sleep waits for d or until the request is cancelled
+func sleep(req *http.Request, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-req.Context().Done():
+		return req.Context().Err()
+	case <-timer.C:
+		return nil
+	}
+}
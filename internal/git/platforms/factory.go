@@ -2,26 +2,42 @@ package platforms
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/docbrown/cli/internal/git"
 )
 
-// NewPlatform creates a Platform based on the detected platform
-func NewPlatform(platformName, remoteURL, token string) (Platform, error) {
+// NewPlatform creates a Platform based on the detected platform. apiBase
+// overrides the API root; when empty it is derived from the remote URL.
+func NewPlatform(platformName, remoteURL, token, apiBase string) (Platform, error) {
 	switch platformName {
 	case "github":
 		owner, repo, err := git.ParseGitHubURL(remoteURL)
 		if err != nil {
 			return nil, err
 		}
-		return NewGitHub(owner, repo, token), nil
+		if apiBase == "" && !strings.Contains(git.RemoteHost(remoteURL), "github.com") {
+			// GitHub Enterprise Server serves its API under /api/v3
+			apiBase = git.APIBaseURL(remoteURL, "/api/v3")
+		}
+		return NewGitHub(owner, repo, token, apiBase), nil
 
 	case "gitlab":
 		projectID, err := git.ParseGitLabURL(remoteURL)
 		if err != nil {
 			return nil, err
 		}
-		return NewGitLab(projectID, token), nil
+		if apiBase == "" {
+			apiBase = git.APIBaseURL(remoteURL, "/api/v4")
+		}
+		return NewGitLab(projectID, token, apiBase), nil
+
+	case "bitbucket":
+		workspace, repoSlug, err := git.ParseBitbucketURL(remoteURL)
+		if err != nil {
+			return nil, err
+		}
+		return NewBitbucket(workspace, repoSlug, token), nil
 
 	default:
 		return nil, fmt.Errorf("unsupported platform: %s", platformName)
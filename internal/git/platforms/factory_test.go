@@ -0,0 +1,60 @@
+package platforms
+
+import "testing"
+
+func TestNewPlatformGitHubAPIBase(t *testing.T) {
+	tests := []struct {
+		remoteURL string
+		apiBase   string // configured override
+		wantAPI   string
+		wantGraph string
+	}{
+		{"git@github.com:acme/shop.git", "", "https://api.github.com", "https://api.github.com/graphql"},
+		{"https://github.com/acme/shop.git", "", "https://api.github.com", "https://api.github.com/graphql"},
+		{"git@github.acme.internal:platform/shop.git", "", "https://github.acme.internal/api/v3", "https://github.acme.internal/api/graphql"},
+		{"ssh://git@github.acme.internal:2222/platform/shop.git", "", "https://github.acme.internal/api/v3", "https://github.acme.internal/api/graphql"},
+		{"https://github.acme.internal/platform/shop.git", "", "https://github.acme.internal/api/v3", "https://github.acme.internal/api/graphql"},
+		{"https://github.acme.internal/platform/shop.git", "https://ghe-api.acme.internal/api/v3/", "https://ghe-api.acme.internal/api/v3", "https://ghe-api.acme.internal/api/graphql"},
+	}
+
+	for _, tt := range tests {
+		platform, err := NewPlatform("github", tt.remoteURL, "token", tt.apiBase)
+		if err != nil {
+			t.Errorf("NewPlatform(%q): %v", tt.remoteURL, err)
+			continue
+		}
+
+		gh := platform.(*GitHub)
+		if gh.owner == "" || gh.repo != "shop" {
+			t.Errorf("%s: owner/repo = %s/%s", tt.remoteURL, gh.owner, gh.repo)
+		}
+		if gh.apiBase != tt.wantAPI {
+			t.Errorf("%s: API base = %q, want %q", tt.remoteURL, gh.apiBase, tt.wantAPI)
+		}
+		if graph := gh.graphQLURL(); graph != tt.wantGraph {
+			t.Errorf("%s: GraphQL URL = %q, want %q", tt.remoteURL, graph, tt.wantGraph)
+		}
+	}
+}
+
+func TestNewPlatformGitLabAPIBase(t *testing.T) {
+	tests := []struct {
+		remoteURL string
+		wantAPI   string
+	}{
+		{"git@gitlab.com:acme/shop.git", "https://gitlab.com/api/v4"},
+		{"git@gitlab.acme.internal:platform/payments/shop.git", "https://gitlab.acme.internal/api/v4"},
+		{"https://gitlab.acme.internal/platform/shop.git", "https://gitlab.acme.internal/api/v4"},
+	}
+
+	for _, tt := range tests {
+		platform, err := NewPlatform("gitlab", tt.remoteURL, "token", "")
+		if err != nil {
+			t.Errorf("NewPlatform(%q): %v", tt.remoteURL, err)
+			continue
+		}
+		if gl := platform.(*GitLab); gl.apiBase != tt.wantAPI {
+			t.Errorf("%s: API base = %q, want %q", tt.remoteURL, gl.apiBase, tt.wantAPI)
+		}
+	}
+}
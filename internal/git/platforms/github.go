@@ -6,21 +6,34 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	neturl "net/url"
+	"strings"
+
+	"github.com/docbrown/cli/internal/logging"
 )
 
-// GitHub implements Platform for GitHub
+const gitHubDefaultAPIBase = "https://api.github.com"
+
+// GitHub implements Platform for GitHub and GitHub Enterprise Server
 type GitHub struct {
-	owner string
-	repo  string
-	token string
+	owner   string
+	repo    string
+	token   string
+	apiBase string
 }
 
-// NewGitHub creates a new GitHub platform
-func NewGitHub(owner, repo, token string) *GitHub {
+// NewGitHub creates a new GitHub platform. apiBase is the REST API root
+// (https://HOST/api/v3 for GitHub Enterprise Server); empty means github.com.
+func NewGitHub(owner, repo, token, apiBase string) *GitHub {
+	if apiBase == "" {
+		apiBase = gitHubDefaultAPIBase
+	}
+
 	return &GitHub{
-		owner: owner,
-		repo:  repo,
-		token: token,
+		owner:   owner,
+		repo:    repo,
+		token:   token,
+		apiBase: strings.TrimSuffix(apiBase, "/"),
 	}
 }
 
@@ -31,86 +44,265 @@ func (gh *GitHub) Name() string {
 
 // CreatePR creates a pull request on GitHub
 func (gh *GitHub) CreatePR(opts PROptions) (string, error) {
-	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls", gh.owner, gh.repo)
+	url := fmt.Sprintf("%s/repos/%s/%s/pulls", gh.apiBase, gh.owner, gh.repo)
 
 	reqBody := map[string]interface{}{
 		"title": opts.Title,
 		"head":  opts.Branch,
 		"base":  opts.BaseBranch,
 		"body":  opts.Body,
+		"draft": opts.Draft,
 	}
 
-	bodyBytes, err := json.Marshal(reqBody)
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %w", err)
+	var result struct {
+		HTMLURL string `json:"html_url"`
+		Number  int    `json:"number"`
+		NodeID  string `json:"node_id"`
+	}
+	if err := gh.do("POST", url, reqBody, http.StatusCreated, &result); err != nil {
+		return "", err
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewReader(bodyBytes))
-	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+	pr := &PullRequest{Number: result.Number, URL: result.HTMLURL, NodeID: result.NodeID, Draft: opts.Draft}
+	return pr.URL, gh.finishPR(pr, opts)
+}
+
+// FindPR returns the open pull request from opts.Branch into opts.BaseBranch
+func (gh *GitHub) FindPR(opts PROptions) (*PullRequest, error) {
+	query := neturl.Values{
+		"state": {"open"},
+		"head":  {gh.owner + ":" + opts.Branch},
+		"base":  {opts.BaseBranch},
 	}
+	url := fmt.Sprintf("%s/repos/%s/%s/pulls?%s", gh.apiBase, gh.owner, gh.repo, query.Encode())
 
-	req.Header.Set("Authorization", "Bearer "+gh.token)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	var pulls []struct {
+		HTMLURL string `json:"html_url"`
+		Number  int    `json:"number"`
+		NodeID  string `json:"node_id"`
+		Draft   bool   `json:"draft"`
+	}
+	if err := gh.do("GET", url, nil, http.StatusOK, &pulls); err != nil {
+		return nil, err
+	}
+	if len(pulls) == 0 {
+		return nil, nil
+	}
+
+	pull := pulls[0]
+	return &PullRequest{Number: pull.Number, URL: pull.HTMLURL, NodeID: pull.NodeID, Draft: pull.Draft}, nil
+}
+
+// UpdatePR updates the title and body of an open pull request and adds the
+// labels, reviewers and assignees from opts. Whether it is a draft is left
+// unchanged.
+func (gh *GitHub) UpdatePR(pr *PullRequest, opts PROptions) (string, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s/pulls/%d", gh.apiBase, gh.owner, gh.repo, pr.Number)
+
+	reqBody := map[string]interface{}{
+		"title": opts.Title,
+		"body":  opts.Body,
+	}
+	if err := gh.do("PATCH", url, reqBody, http.StatusOK, nil); err != nil {
+		return "", err
+	}
+
+	return pr.URL, gh.finishPR(pr, opts)
+}
+
+// finishPR applies the labels, reviewers, assignees and auto-merge setting
+// of a created or updated pull request. The pull request exists by now, so
+// label, reviewer and assignee failures are warnings; auto-merge failures
+// are returned.
+func (gh *GitHub) finishPR(pr *PullRequest, opts PROptions) error {
+	if len(opts.Labels) > 0 {
+		if err := gh.addLabels(pr.Number, opts.Labels); err != nil {
+			logging.Warnf("⚠ Failed to add labels to PR #%d: %v", pr.Number, err)
+		}
+	}
+
+	if len(opts.Reviewers) > 0 {
+		if err := gh.requestReviewers(pr.Number, opts.Reviewers); err != nil {
+			logging.Warnf("⚠ Failed to request reviewers on PR #%d: %v", pr.Number, err)
+		}
+	}
+
+	if len(opts.Assignees) > 0 {
+		if err := gh.addAssignees(pr.Number, opts.Assignees); err != nil {
+			logging.Warnf("⚠ Failed to assign PR #%d: %v", pr.Number, err)
+		}
+	}
+
+	if opts.AutoMerge {
+		return gh.enableAutoMerge(pr.NodeID, pr.Draft)
+	}
+
+	return nil
+}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+// enableAutoMerge turns on auto-merge for a PR, using a merge method the
+// repository allows. GitHub only accepts this when the repository allows
+// auto-merge and the base branch has protection rules still to satisfy.
+func (gh *GitHub) enableAutoMerge(nodeID string, draft bool) error {
+	if draft {
+		return &AutoMergeError{Reason: "draft pull requests can't be auto-merged"}
+	}
+
+	repo, err := gh.repoSettings()
 	if err != nil {
-		return "", fmt.Errorf("request failed: %w", err)
+		return &AutoMergeError{Reason: err.Error()}
+	}
+	// allow_auto_merge is only returned to users who can change it
+	if repo.AllowAutoMerge != nil && !*repo.AllowAutoMerge {
+		return &AutoMergeError{Reason: "auto-merge is disabled in the repository settings"}
 	}
-	defer resp.Body.Close()
 
-	body, _ := io.ReadAll(resp.Body)
+	mergeMethod := "MERGE"
+	switch {
+	case repo.AllowMergeCommit:
+	case repo.AllowSquashMerge:
+		mergeMethod = "SQUASH"
+	case repo.AllowRebaseMerge:
+		mergeMethod = "REBASE"
+	}
 
-	if resp.StatusCode != http.StatusCreated {
-		return "", fmt.Errorf("GitHub API error (status %d): %s", resp.StatusCode, string(body))
+	query := `mutation($id: ID!, $method: PullRequestMergeMethod!) {
+  enablePullRequestAutoMerge(input: {pullRequestId: $id, mergeMethod: $method}) {
+    clientMutationId
+  }
+}`
+	reqBody := map[string]interface{}{
+		"query": query,
+		"variables": map[string]string{
+			"id":     nodeID,
+			"method": mergeMethod,
+		},
 	}
 
+	// GraphQL reports failures (e.g. "Pull request is in clean status" when
+	// the base branch has no protection) with a 200 status
 	var result struct {
-		HTMLURL string `json:"html_url"`
-		Number  int    `json:"number"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
 	}
-
-	if err := json.Unmarshal(body, &result); err != nil {
-		return "", fmt.Errorf("failed to parse response: %w", err)
+	if err := gh.do("POST", gh.graphQLURL(), reqBody, http.StatusOK, &result); err != nil {
+		return &AutoMergeError{Reason: err.Error()}
+	}
+	if len(result.Errors) > 0 {
+		messages := make([]string, len(result.Errors))
+		for i, e := range result.Errors {
+			messages[i] = e.Message
+		}
+		return &AutoMergeError{Reason: strings.Join(messages, "; ")}
 	}
 
-	// Add labels if specified
-	if len(opts.Labels) > 0 {
-		gh.addLabels(result.Number, opts.Labels)
+	return nil
+}
+
+// gitHubRepo is the subset of repository settings used for auto-merge
+type gitHubRepo struct {
+	AllowAutoMerge   *bool `json:"allow_auto_merge"`
+	AllowMergeCommit bool  `json:"allow_merge_commit"`
+	AllowSquashMerge bool  `json:"allow_squash_merge"`
+	AllowRebaseMerge bool  `json:"allow_rebase_merge"`
+}
+
+// repoSettings fetches the repository's merge settings
+func (gh *GitHub) repoSettings() (*gitHubRepo, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s", gh.apiBase, gh.owner, gh.repo)
+
+	var repo gitHubRepo
+	if err := gh.do("GET", url, nil, http.StatusOK, &repo); err != nil {
+		return nil, err
 	}
+	return &repo, nil
+}
 
-	return result.HTMLURL, nil
+// graphQLURL returns the GraphQL endpoint for the API base: /graphql on
+// github.com, /api/graphql on GitHub Enterprise Server
+func (gh *GitHub) graphQLURL() string {
+	if gh.apiBase == gitHubDefaultAPIBase {
+		return gitHubDefaultAPIBase + "/graphql"
+	}
+	return strings.TrimSuffix(gh.apiBase, "/v3") + "/graphql"
 }
 
 // addLabels adds labels to a PR
 func (gh *GitHub) addLabels(prNumber int, labels []string) error {
-	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/issues/%d/labels",
-		gh.owner, gh.repo, prNumber)
+	url := fmt.Sprintf("%s/repos/%s/%s/issues/%d/labels",
+		gh.apiBase, gh.owner, gh.repo, prNumber)
 
 	reqBody := map[string]interface{}{
 		"labels": labels,
 	}
 
-	bodyBytes, _ := json.Marshal(reqBody)
+	return gh.do("POST", url, reqBody, http.StatusOK, nil)
+}
+
+// requestReviewers requests reviews on a PR
+func (gh *GitHub) requestReviewers(prNumber int, reviewers []string) error {
+	url := fmt.Sprintf("%s/repos/%s/%s/pulls/%d/requested_reviewers",
+		gh.apiBase, gh.owner, gh.repo, prNumber)
+
+	reqBody := map[string]interface{}{
+		"reviewers": reviewers,
+	}
+
+	return gh.do("POST", url, reqBody, http.StatusCreated, nil)
+}
+
+// addAssignees assigns users to a PR
+func (gh *GitHub) addAssignees(prNumber int, assignees []string) error {
+	url := fmt.Sprintf("%s/repos/%s/%s/issues/%d/assignees",
+		gh.apiBase, gh.owner, gh.repo, prNumber)
+
+	reqBody := map[string]interface{}{
+		"assignees": assignees,
+	}
+
+	return gh.do("POST", url, reqBody, http.StatusCreated, nil)
+}
+
+// do sends a request to the GitHub API, checks the status and decodes the
+// response into result if it is non-nil
+func (gh *GitHub) do(method, url string, reqBody interface{}, wantStatus int, result interface{}) error {
+	var reader io.Reader
+	if reqBody != nil {
+		bodyBytes, err := json.Marshal(reqBody)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(bodyBytes)
+	}
+
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
 
-	req, _ := http.NewRequest("POST", url, bytes.NewReader(bodyBytes))
 	req.Header.Set("Authorization", "Bearer "+gh.token)
-	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	if reqBody != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := httpClient.Do(req)
 	if err != nil {
-		return err
+		return fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("failed to add labels (status %d): %s", resp.StatusCode, string(body))
+	body, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode != wantStatus {
+		return fmt.Errorf("GitHub API error (status %d): %s", resp.StatusCode, string(body))
 	}
 
+	if result != nil {
+		if err := json.Unmarshal(body, result); err != nil {
+			return fmt.Errorf("failed to parse response: %w", err)
+		}
+	}
 	return nil
 }
@@ -6,19 +6,30 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	neturl "net/url"
+	"strings"
 )
 
+const gitLabDefaultAPIBase = "https://gitlab.com/api/v4"
+
 // GitLab implements Platform for GitLab
 type GitLab struct {
 	projectID string
 	token     string
+	apiBase   string
 }
 
-// NewGitLab creates a new GitLab platform
-func NewGitLab(projectID, token string) *GitLab {
+// NewGitLab creates a new GitLab platform. apiBase is the /api/v4 root of
+// the instance; empty means gitlab.com.
+func NewGitLab(projectID, token, apiBase string) *GitLab {
+	if apiBase == "" {
+		apiBase = gitLabDefaultAPIBase
+	}
+
 	return &GitLab{
 		projectID: projectID,
 		token:     token,
+		apiBase:   strings.TrimSuffix(apiBase, "/"),
 	}
 }
 
@@ -29,14 +40,14 @@ func (gl *GitLab) Name() string {
 
 // CreatePR creates a merge request on GitLab
 func (gl *GitLab) CreatePR(opts PROptions) (string, error) {
-	url := fmt.Sprintf("https://gitlab.com/api/v4/projects/%s/merge_requests", gl.projectID)
+	url := fmt.Sprintf("%s/projects/%s/merge_requests", gl.apiBase, gl.projectID)
 
-	reqBody := map[string]interface{}{
-		"source_branch": opts.Branch,
-		"target_branch": opts.BaseBranch,
-		"title":         opts.Title,
-		"description":   opts.Body,
+	reqBody, err := gl.mergeRequestFields(opts, opts.Draft)
+	if err != nil {
+		return "", err
 	}
+	reqBody["source_branch"] = opts.Branch
+	reqBody["target_branch"] = opts.BaseBranch
 
 	if len(opts.Labels) > 0 {
 		reqBody["labels"] = opts.Labels
@@ -55,8 +66,7 @@ func (gl *GitLab) CreatePR(opts PROptions) (string, error) {
 	req.Header.Set("PRIVATE-TOKEN", gl.token)
 	req.Header.Set("Content-Type", "application/json")
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := httpClient.Do(req)
 	if err != nil {
 		return "", fmt.Errorf("request failed: %w", err)
 	}
@@ -77,5 +87,213 @@ func (gl *GitLab) CreatePR(opts PROptions) (string, error) {
 		return "", fmt.Errorf("failed to parse response: %w", err)
 	}
 
+	if opts.AutoMerge {
+		if err := gl.mergeWhenPipelineSucceeds(result.IID, opts.Draft); err != nil {
+			return result.WebURL, err
+		}
+	}
+
 	return result.WebURL, nil
 }
+
+// FindPR returns the open merge request from opts.Branch into opts.BaseBranch
+func (gl *GitLab) FindPR(opts PROptions) (*PullRequest, error) {
+	query := neturl.Values{
+		"state":         {"opened"},
+		"source_branch": {opts.Branch},
+		"target_branch": {opts.BaseBranch},
+	}
+	url := fmt.Sprintf("%s/projects/%s/merge_requests?%s", gl.apiBase, gl.projectID, query.Encode())
+
+	var mrs []struct {
+		WebURL string `json:"web_url"`
+		IID    int    `json:"iid"`
+		Draft  bool   `json:"draft"`
+	}
+	if err := gl.do("GET", url, nil, http.StatusOK, &mrs); err != nil {
+		return nil, err
+	}
+	if len(mrs) == 0 {
+		return nil, nil
+	}
+
+	return &PullRequest{Number: mrs[0].IID, URL: mrs[0].WebURL, Draft: mrs[0].Draft}, nil
+}
+
+// UpdatePR updates the title, description, reviewers and assignees of an
+// open merge request and adds the labels from opts. A draft stays a draft.
+func (gl *GitLab) UpdatePR(pr *PullRequest, opts PROptions) (string, error) {
+	draft := opts.Draft || pr.Draft
+
+	reqBody, err := gl.mergeRequestFields(opts, draft)
+	if err != nil {
+		return "", err
+	}
+	if len(opts.Labels) > 0 {
+		reqBody["add_labels"] = strings.Join(opts.Labels, ",")
+	}
+
+	url := fmt.Sprintf("%s/projects/%s/merge_requests/%d", gl.apiBase, gl.projectID, pr.Number)
+	if err := gl.do("PUT", url, reqBody, http.StatusOK, nil); err != nil {
+		return "", err
+	}
+
+	if opts.AutoMerge {
+		if err := gl.mergeWhenPipelineSucceeds(pr.Number, draft); err != nil {
+			return pr.URL, err
+		}
+	}
+
+	return pr.URL, nil
+}
+
+// mergeRequestFields returns the title, description, reviewers and
+// assignees of a merge request
+func (gl *GitLab) mergeRequestFields(opts PROptions, draft bool) (map[string]interface{}, error) {
+	// GitLab marks merge requests as drafts via a title prefix
+	title := opts.Title
+	if draft && !strings.HasPrefix(title, "Draft:") {
+		title = "Draft: " + title
+	}
+
+	fields := map[string]interface{}{
+		"title":       title,
+		"description": opts.Body,
+	}
+
+	// GitLab expects user IDs rather than usernames
+	if len(opts.Reviewers) > 0 {
+		ids, err := gl.resolveUserIDs(opts.Reviewers)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve reviewers: %w", err)
+		}
+		fields["reviewer_ids"] = ids
+	}
+
+	if len(opts.Assignees) > 0 {
+		ids, err := gl.resolveUserIDs(opts.Assignees)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve assignees: %w", err)
+		}
+		fields["assignee_ids"] = ids
+	}
+
+	return fields, nil
+}
+
+// mergeWhenPipelineSucceeds sets a merge request to merge once its pipeline
+// passes. Without a pipeline GitLab would merge straight away, so that case
+// is refused.
+func (gl *GitLab) mergeWhenPipelineSucceeds(iid int, draft bool) error {
+	if draft {
+		return &AutoMergeError{Reason: "draft merge requests can't be merged"}
+	}
+
+	url := fmt.Sprintf("%s/projects/%s/merge_requests/%d", gl.apiBase, gl.projectID, iid)
+
+	var mr struct {
+		HeadPipeline *struct {
+			ID int `json:"id"`
+		} `json:"head_pipeline"`
+	}
+	if err := gl.do("GET", url, nil, http.StatusOK, &mr); err != nil {
+		return &AutoMergeError{Reason: err.Error()}
+	}
+	if mr.HeadPipeline == nil {
+		return &AutoMergeError{Reason: "no pipeline is running for the merge request, so GitLab would merge it immediately"}
+	}
+
+	reqBody := map[string]interface{}{
+		"merge_when_pipeline_succeeds": true,
+	}
+	if err := gl.do("PUT", url+"/merge", reqBody, http.StatusOK, nil); err != nil {
+		return &AutoMergeError{Reason: err.Error()}
+	}
+
+	return nil
+}
+
+// do sends a request to the GitLab API, checks the status and decodes the
+// response into result if it is non-nil
+func (gl *GitLab) do(method, url string, reqBody interface{}, wantStatus int, result interface{}) error {
+	var reader io.Reader
+	if reqBody != nil {
+		bodyBytes, err := json.Marshal(reqBody)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(bodyBytes)
+	}
+
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("PRIVATE-TOKEN", gl.token)
+	if reqBody != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode != wantStatus {
+		return fmt.Errorf("GitLab API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	if result != nil {
+		if err := json.Unmarshal(body, result); err != nil {
+			return fmt.Errorf("failed to parse response: %w", err)
+		}
+	}
+	return nil
+}
+
+// resolveUserIDs looks up the GitLab user ID for each username
+func (gl *GitLab) resolveUserIDs(usernames []string) ([]int, error) {
+	ids := make([]int, 0, len(usernames))
+
+	for _, username := range usernames {
+		username = strings.TrimPrefix(username, "@")
+		url := gl.apiBase + "/users?username=" + neturl.QueryEscape(username)
+
+		req, err := http.NewRequest("GET", url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("PRIVATE-TOKEN", gl.token)
+
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("request failed: %w", err)
+		}
+
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("GitLab API error (status %d): %s", resp.StatusCode, string(body))
+		}
+
+		var users []struct {
+			ID int `json:"id"`
+		}
+		if err := json.Unmarshal(body, &users); err != nil {
+			return nil, fmt.Errorf("failed to parse response: %w", err)
+		}
+
+		if len(users) == 0 {
+			return nil, fmt.Errorf("user not found: %s", username)
+		}
+
+		ids = append(ids, users[0].ID)
+	}
+
+	return ids, nil
+}
@@ -1,12 +1,52 @@
 package platforms
 
+import "fmt"
+
 // Platform defines the interface for Git platform integrations
 type Platform interface {
 	// Name returns the platform name
 	Name() string
 
-	// CreatePR creates a pull request
+	// CreatePR creates a pull request and returns its URL. If the pull
+	// request was created but auto-merge could not be enabled, the URL is
+	// returned along with an *AutoMergeError.
 	CreatePR(opts PROptions) (string, error)
+
+	// FindPR returns the open pull request from opts.Branch into
+	// opts.BaseBranch, or nil if there is none
+	FindPR(opts PROptions) (*PullRequest, error)
+
+	// UpdatePR updates an open pull request's title, body, labels,
+	// reviewers and assignees from opts and returns its URL. Auto-merge
+	// failures are reported as in CreatePR.
+	UpdatePR(pr *PullRequest, opts PROptions) (string, error)
+}
+
+// PullRequest identifies an open pull request
+type PullRequest struct {
+	Number int    // GitHub number, GitLab IID or Bitbucket ID
+	URL    string // web URL
+	NodeID string // GitHub GraphQL ID, used for auto-merge
+	Draft  bool
+}
+
+// CreateOrUpdatePR updates the open pull request from opts.Branch into
+// opts.BaseBranch if there is one, and creates a pull request otherwise,
+// so re-running on the same branch never opens a duplicate. updated
+// reports which happened.
+func CreateOrUpdatePR(p Platform, opts PROptions) (url string, updated bool, err error) {
+	existing, err := p.FindPR(opts)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to look up open pull requests: %w", err)
+	}
+
+	if existing != nil {
+		url, err := p.UpdatePR(existing, opts)
+		return url, true, err
+	}
+
+	url, err = p.CreatePR(opts)
+	return url, false, err
 }
 
 // PROptions contains options for creating a pull request
@@ -16,4 +56,18 @@ type PROptions struct {
 	Branch     string
 	BaseBranch string
 	Labels     []string
+	Reviewers  []string
+	Assignees  []string
+	Draft      bool
+	AutoMerge  bool
+}
+
+// AutoMergeError reports that a pull request was created but auto-merge
+// could not be enabled on it
+type AutoMergeError struct {
+	Reason string
+}
+
+func (e *AutoMergeError) Error() string {
+	return "auto-merge not enabled: " + e.Reason
 }
@@ -0,0 +1,79 @@
+package httpclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+)
+
+// current is the transport behind every outbound call: the LLM providers,
+// the git platforms, git pushes, webhooks and link checks
+var (
+	mu      sync.RWMutex
+	current = http.DefaultTransport
+)
+
+// Configure sets up the shared transport. Proxies come from HTTP_PROXY,
+// HTTPS_PROXY and NO_PROXY unless proxyURL is set. caBundle is a PEM file
+// of extra certificate authorities trusted alongside the system ones.
+func Configure(proxyURL, caBundle string) error {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if proxyURL != "" {
+		proxy, err := url.Parse(proxyURL)
+		if err != nil || proxy.Host == "" {
+			return fmt.Errorf("invalid proxy URL %q", proxyURL)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	if caBundle != "" {
+		pem, err := os.ReadFile(caBundle)
+		if err != nil {
+			return fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in CA bundle %s", caBundle)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+
+	mu.Lock()
+	current = transport
+	mu.Unlock()
+	return nil
+}
+
+// Transport returns a RoundTripper that always uses the configured
+// transport, so clients created before Configure pick up its settings
+func Transport() http.RoundTripper {
+	return sharedTransport{}
+}
+
+// New creates a client using the shared transport. A zero timeout means none.
+func New(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Transport: Transport(),
+		Timeout:   timeout,
+	}
+}
+
+// sharedTransport delegates to the currently configured transport
+type sharedTransport struct{}
+
+// RoundTrip implements http.RoundTripper
+func (sharedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	mu.RLock()
+	transport := current
+	mu.RUnlock()
+	return transport.RoundTrip(req)
+}
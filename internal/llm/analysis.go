@@ -0,0 +1,177 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// jsonRepairInstruction is appended to the analysis prompt when the first
+// response couldn't be parsed
+const jsonRepairInstruction = "\n\nYour previous response was not valid JSON. " +
+	"Return only valid JSON matching the structure above, with no prose or code fences."
+
+// AnalysisParseError is returned when an analysis response isn't valid JSON,
+// even after re-prompting. Response holds the last raw response.
+type AnalysisParseError struct {
+	Response string
+	Err      error
+}
+
+func (e *AnalysisParseError) Error() string {
+	return fmt.Sprintf("analysis response is not valid JSON: %v", e.Err)
+}
+
+func (e *AnalysisParseError) Unwrap() error {
+	return e.Err
+}
+
+// analyzeWithRepair runs an analysis prompt and parses the JSON result. If
+// the response can't be parsed it re-prompts once, asking for JSON only.
+func analyzeWithRepair(ctx context.Context, prompt string, call func(ctx context.Context, prompt string) (string, error)) (*AnalysisResult, error) {
+	response, err := call(ctx, prompt)
+	if err != nil {
+		return nil, fmt.Errorf("analysis failed: %w", err)
+	}
+
+	result, err := parseAnalysis(response)
+	if err == nil {
+		return result, nil
+	}
+
+	response, err = call(ctx, prompt+jsonRepairInstruction)
+	if err != nil {
+		return nil, fmt.Errorf("analysis failed: %w", err)
+	}
+
+	result, err = parseAnalysis(response)
+	if err != nil {
+		return nil, &AnalysisParseError{Response: response, Err: err}
+	}
+
+	return result, nil
+}
+
+// parseAnalysis decodes an analysis response. Responses wrapped in prose or
+// code fences are repaired by decoding the first {...} block.
+func parseAnalysis(response string) (*AnalysisResult, error) {
+	var result AnalysisResult
+	err := json.Unmarshal([]byte(response), &result)
+	if err == nil {
+		return &result, nil
+	}
+
+	block := extractJSONObject(response)
+	if block == "" {
+		return nil, err
+	}
+
+	result = AnalysisResult{}
+	if err := json.Unmarshal([]byte(block), &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// analysisSchema returns the JSON schema of AnalysisResult, for providers
+// that can constrain their output to a schema
+func analysisSchema() map[string]interface{} {
+	str := map[string]interface{}{"type": "string"}
+	list := map[string]interface{}{"type": "array", "items": str}
+	object := func(required []string, properties map[string]interface{}) map[string]interface{} {
+		schema := map[string]interface{}{"type": "object", "properties": properties}
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+		return schema
+	}
+	array := func(items map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{"type": "array", "items": items}
+	}
+
+	parameter := object([]string{"name"}, map[string]interface{}{
+		"name":        str,
+		"type":        str,
+		"required":    map[string]interface{}{"type": "boolean"},
+		"description": str,
+	})
+	endpoint := object([]string{"method", "path"}, map[string]interface{}{
+		"method":      str,
+		"path":        str,
+		"description": str,
+		"parameters":  array(parameter),
+	})
+
+	return object([]string{"overview"}, map[string]interface{}{
+		"overview": map[string]interface{}{"type": "string", "description": "High-level description of the component"},
+		"components": array(object([]string{"name"}, map[string]interface{}{
+			"name":         str,
+			"type":         map[string]interface{}{"type": "string", "description": "service, library, frontend or cli"},
+			"language":     str,
+			"path":         str,
+			"description":  str,
+			"dependencies": list,
+		})),
+		"services": array(object([]string{"name"}, map[string]interface{}{
+			"name":        str,
+			"type":        map[string]interface{}{"type": "string", "description": "rest, grpc or graphql"},
+			"description": str,
+			"endpoints":   array(endpoint),
+			"port":        map[string]interface{}{"type": "integer"},
+		})),
+		"architecture": object(nil, map[string]interface{}{
+			"overview":     str,
+			"components":   list,
+			"patterns":     list,
+			"technologies": list,
+			"diagram":      map[string]interface{}{"type": "string", "description": "Mermaid diagram source"},
+		}),
+	})
+}
+
+// extractJSONObject returns the first balanced {...} block in s, ignoring
+// braces inside JSON strings, or "" if there is none
+func extractJSONObject(s string) string {
+	start := -1
+	depth := 0
+	inString, escaped := false, false
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch c {
+		case '"':
+			if start >= 0 {
+				inString = true
+			}
+		case '{':
+			if start < 0 {
+				start = i
+			}
+			depth++
+		case '}':
+			if start < 0 {
+				continue
+			}
+			depth--
+			if depth == 0 {
+				return s[start : i+1]
+			}
+		}
+	}
+
+	return ""
+}
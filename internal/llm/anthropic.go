@@ -8,36 +8,79 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"time"
+
+	"github.com/docbrown/cli/internal/httpclient"
+	"github.com/docbrown/cli/internal/logging"
 )
 
 const anthropicAPIURL = "https://api.anthropic.com/v1/messages"
 
+// analysisToolName is the tool Anthropic is made to call with its analysis
+const analysisToolName = "record_analysis"
+
+// anthropicTool is a tool definition; the model's input to it follows
+// InputSchema
+type anthropicTool struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	InputSchema map[string]interface{} `json:"input_schema"`
+}
+
+// analysisTool has Anthropic return the analysis as structured tool input
+// instead of JSON in prose
+var analysisTool = &anthropicTool{
+	Name:        analysisToolName,
+	Description: "Record the analysis of the codebase component.",
+	InputSchema: analysisSchema(),
+}
+
 // AnthropicProvider implements the Provider interface for Anthropic Claude
 type AnthropicProvider struct {
 	apiKey    string
 	model     string
 	maxTokens int
+	timeout   time.Duration
 	client    *http.Client
-	usage     TokenUsage
+	usage     usageCounter
+	retry     RetryPolicy
+	sampling  SamplingParams
 }
 
 // NewAnthropicProvider creates a new Anthropic provider
-func NewAnthropicProvider(apiKey, model string, maxTokens int) *AnthropicProvider {
+func NewAnthropicProvider(apiKey, model string, maxTokens int, timeout time.Duration) *AnthropicProvider {
 	if model == "" {
 		model = "claude-sonnet-4-20250514"
 	}
 	if maxTokens == 0 {
 		maxTokens = 4096
 	}
+	if timeout == 0 {
+		timeout = 120 * time.Second
+	}
 
 	return &AnthropicProvider{
 		apiKey:    apiKey,
 		model:     model,
 		maxTokens: maxTokens,
-		client:    &http.Client{},
+		timeout:   timeout,
+		client:    httpclient.New(timeout),
+		retry:     DefaultRetryPolicy(),
+		sampling:  DefaultSamplingParams(),
 	}
 }
 
+// SetRetryPolicy sets the retry policy for transient API errors
+func (a *AnthropicProvider) SetRetryPolicy(policy RetryPolicy) {
+	a.retry = policy
+}
+
+// SetSamplingParams sets the temperature and top_p sent with each request.
+// Anthropic has no seed parameter, so params.Seed is ignored.
+func (a *AnthropicProvider) SetSamplingParams(params SamplingParams) {
+	a.sampling = params
+}
+
 // Name returns the provider name
 func (a *AnthropicProvider) Name() string {
 	return "anthropic"
@@ -51,29 +94,20 @@ func (a *AnthropicProvider) IsAvailable() bool {
 // Ping checks if the provider is reachable
 func (a *AnthropicProvider) Ping(ctx context.Context) error {
 	// Simple test call with minimal tokens
-	_, err := a.callAPI(ctx, "Hello", 10)
+	_, err := a.callAPI(ctx, "Hello", 10, nil)
 	return err
 }
 
-// Analyze analyzes a codebase component
+// Analyze analyzes a codebase component. The model is made to call a tool
+// whose input schema is AnalysisResult, so the API returns the analysis as
+// structured JSON. If it answers in text instead, the JSON is parsed out of
+// the text as for other providers.
 func (a *AnthropicProvider) Analyze(ctx context.Context, req AnalysisRequest) (*AnalysisResult, error) {
 	prompt := a.buildAnalysisPrompt(req)
 
-	response, err := a.callAPI(ctx, prompt, a.maxTokens)
-	if err != nil {
-		return nil, fmt.Errorf("analysis failed: %w", err)
-	}
-
-	// Parse JSON response
-	var result AnalysisResult
-	if err := json.Unmarshal([]byte(response), &result); err != nil {
-		// If JSON parsing fails, return a simple result
-		return &AnalysisResult{
-			Overview: response,
-		}, nil
-	}
-
-	return &result, nil
+	return analyzeWithRepair(ctx, prompt, func(ctx context.Context, prompt string) (string, error) {
+		return a.callAPI(ctx, prompt, a.maxTokens, analysisTool)
+	})
 }
 
 // Generate generates documentation content
@@ -83,7 +117,7 @@ func (a *AnthropicProvider) Generate(ctx context.Context, req GenerateRequest) (
 		prompt = a.buildGeneratePrompt(req)
 	}
 
-	response, err := a.callAPI(ctx, prompt, a.maxTokens)
+	response, err := a.callAPI(ctx, prompt, a.maxTokens, nil)
 	if err != nil {
 		return "", fmt.Errorf("generation failed: %w", err)
 	}
@@ -91,31 +125,57 @@ func (a *AnthropicProvider) Generate(ctx context.Context, req GenerateRequest) (
 	return response, nil
 }
 
-// EstimateCost estimates the cost for a given number of tokens
-func (a *AnthropicProvider) EstimateCost(tokens int) float64 {
-	// Assuming 50/50 split between input and output
-	inputTokens := tokens / 2
-	outputTokens := tokens / 2
-
-	inputCost := float64(inputTokens) * 0.003 / 1000
-	outputCost := float64(outputTokens) * 0.015 / 1000
+// EstimateCost estimates the cost of the given token usage
+func (a *AnthropicProvider) EstimateCost(usage TokenUsage) float64 {
+	inputCost := float64(usage.InputTokens) * 0.003 / 1000
+	outputCost := float64(usage.OutputTokens) * 0.015 / 1000
 
 	return inputCost + outputCost
 }
 
 // GetUsage returns the total token usage
 func (a *AnthropicProvider) GetUsage() TokenUsage {
-	return a.usage
+	return a.usage.get()
+}
+
+// callAPI makes a call to the Anthropic API, retrying transient failures.
+// With a tool, the model is required to call it and the tool input is
+// returned as JSON.
+func (a *AnthropicProvider) callAPI(ctx context.Context, prompt string, maxTokens int, tool *anthropicTool) (string, error) {
+	var text string
+	err := withRetry(ctx, a.retry, func() error {
+		var err error
+		text, err = a.doCallAPI(ctx, prompt, maxTokens, tool)
+		return err
+	})
+	return text, err
 }
 
-// callAPI makes a call to the Anthropic API
-func (a *AnthropicProvider) callAPI(ctx context.Context, prompt string, maxTokens int) (string, error) {
+// doCallAPI makes a single call to the Anthropic API
+func (a *AnthropicProvider) doCallAPI(ctx context.Context, prompt string, maxTokens int, tool *anthropicTool) (string, error) {
+	// Always bound the request, even if the caller didn't set a deadline
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, a.timeout)
+		defer cancel()
+	}
+
 	reqBody := map[string]interface{}{
 		"model":      a.model,
 		"max_tokens": maxTokens,
 		"messages": []map[string]string{
 			{"role": "user", "content": prompt},
 		},
+		"temperature": min(a.sampling.Temperature, anthropicMaxTemperature),
+	}
+
+	if a.sampling.TopP > 0 {
+		reqBody["top_p"] = a.sampling.TopP
+	}
+
+	if tool != nil {
+		reqBody["tools"] = []*anthropicTool{tool}
+		reqBody["tool_choice"] = map[string]string{"type": "tool", "name": tool.Name}
 	}
 
 	bodyBytes, err := json.Marshal(reqBody)
@@ -140,13 +200,15 @@ func (a *AnthropicProvider) callAPI(ctx context.Context, prompt string, maxToken
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+		return "", newAPIError(resp, body)
 	}
 
 	var response struct {
 		Content []struct {
-			Type string `json:"type"`
-			Text string `json:"text"`
+			Type  string          `json:"type"`
+			Text  string          `json:"text"`
+			Name  string          `json:"name"`  // tool_use blocks
+			Input json.RawMessage `json:"input"` // tool_use blocks
 		} `json:"content"`
 		Usage struct {
 			InputTokens  int `json:"input_tokens"`
@@ -159,13 +221,26 @@ func (a *AnthropicProvider) callAPI(ctx context.Context, prompt string, maxToken
 	}
 
 	// Track usage
-	a.usage.InputTokens += response.Usage.InputTokens
-	a.usage.OutputTokens += response.Usage.OutputTokens
+	a.usage.add(ctx, response.Usage.InputTokens, response.Usage.OutputTokens)
 
 	if len(response.Content) == 0 {
 		return "", fmt.Errorf("empty response from API")
 	}
 
+	if tool != nil {
+		for _, block := range response.Content {
+			if block.Type == "tool_use" && block.Name == tool.Name {
+				return string(block.Input), nil
+			}
+		}
+		logging.Debugf("Anthropic answered in text instead of calling %s; parsing it as JSON", tool.Name)
+		for _, block := range response.Content {
+			if block.Type == "text" {
+				return block.Text, nil
+			}
+		}
+	}
+
 	return response.Content[0].Text, nil
 }
 
@@ -3,23 +3,137 @@ package llm
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/docbrown/cli/internal/config"
+	"github.com/docbrown/cli/internal/logging"
 )
 
 // NewProvider creates a new LLM provider based on configuration
 func NewProvider(cfg *config.Config) (Provider, error) {
+	var provider Provider
+	var err error
+
 	switch cfg.LLM.Provider {
 	case "anthropic":
-		return newAnthropicFromConfig(cfg)
+		provider, err = newAnthropicFromConfig(cfg)
 	case "ollama":
-		return newOllamaFromConfig(cfg)
+		provider, err = newOllamaFromConfig(cfg)
+	case "openai":
+		provider, err = newOpenAIFromConfig(cfg)
+	case "mock":
+		provider = NewMockProvider()
 	case "auto":
-		return detectProvider(cfg)
+		provider, err = detectProvider(cfg)
 	default:
 		return nil, fmt.Errorf("unknown provider: %s", cfg.LLM.Provider)
 	}
+	if err != nil {
+		return nil, err
+	}
+
+	// An explicit model override is checked up front so a typo fails fast
+	// instead of after the repository has been analyzed
+	if cfg.LLM.Model != "" {
+		if err := validateModel(provider, cfg.LLM.Model); err != nil {
+			return nil, err
+		}
+	}
+
+	return provider, nil
+}
+
+// fallbackProviders are the providers llm.fallback may name
+var fallbackProviders = []string{"anthropic", "ollama", "openai", "mock"}
+
+// NewProviders creates the configured provider followed by the llm.fallback
+// providers, for a Pool to fall back through. A fallback that can't be
+// created (no API key, Ollama not running) is skipped with a warning, and so
+// is the configured provider as long as a fallback is left.
+func NewProviders(cfg *config.Config) ([]Provider, error) {
+	for _, name := range cfg.LLM.Fallback {
+		if !slices.Contains(fallbackProviders, name) {
+			return nil, fmt.Errorf("unknown fallback provider: %s (must be one of: %s)", name, strings.Join(fallbackProviders, ", "))
+		}
+	}
+
+	primary, err := NewProvider(cfg)
+	if len(cfg.LLM.Fallback) == 0 {
+		if err != nil {
+			return nil, err
+		}
+		return []Provider{primary}, nil
+	}
+
+	var providers []Provider
+	var failures []string
+	tried := []string{cfg.LLM.Provider}
+	if err != nil {
+		logging.Warnf("⚠ Provider %s unavailable, trying fallbacks: %v", cfg.LLM.Provider, err)
+		failures = append(failures, fmt.Sprintf("%s: %v", cfg.LLM.Provider, err))
+	} else {
+		providers = append(providers, primary)
+		tried = append(tried, primary.Name())
+	}
+
+	for _, name := range cfg.LLM.Fallback {
+		if slices.Contains(tried, name) {
+			continue
+		}
+		tried = append(tried, name)
+
+		// The model override names a model of the configured provider
+		fallbackCfg := *cfg
+		fallbackCfg.LLM.Provider = name
+		fallbackCfg.LLM.Model = ""
+
+		provider, err := NewProvider(&fallbackCfg)
+		if err != nil {
+			logging.Warnf("⚠ Fallback provider %s unavailable: %v", name, err)
+			failures = append(failures, fmt.Sprintf("%s: %v", name, err))
+			continue
+		}
+		providers = append(providers, provider)
+	}
+
+	if len(providers) == 0 {
+		return nil, fmt.Errorf("no LLM provider available (%s)", strings.Join(failures, "; "))
+	}
+
+	names := make([]string, len(providers))
+	for i, provider := range providers {
+		names[i] = provider.Name()
+	}
+	logging.Debugf("Provider fallback chain: %s", strings.Join(names, " → "))
+
+	return providers, nil
+}
+
+// modelFor returns the per-run model override if set, else the provider's configured model
+func modelFor(cfg *config.Config, configured string) string {
+	if cfg.LLM.Model != "" {
+		return cfg.LLM.Model
+	}
+	return configured
+}
+
+// validateModel asks the provider whether it can serve the model
+func validateModel(provider Provider, model string) error {
+	validator, ok := provider.(ModelValidator)
+	if !ok {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := validator.ValidateModel(ctx); err != nil {
+		return fmt.Errorf("model %q is not available from %s: %w", model, provider.Name(), err)
+	}
+
+	return nil
 }
 
 // newAnthropicFromConfig creates an Anthropic provider from config
@@ -28,21 +142,46 @@ func newAnthropicFromConfig(cfg *config.Config) (Provider, error) {
 		return nil, fmt.Errorf("Anthropic API key not configured (set ANTHROPIC_API_KEY)")
 	}
 
-	return NewAnthropicProvider(
+	provider := NewAnthropicProvider(
 		cfg.LLM.Anthropic.APIKey,
-		cfg.LLM.Anthropic.Model,
+		modelFor(cfg, cfg.LLM.Anthropic.Model),
 		cfg.LLM.Anthropic.MaxTokens,
-	), nil
+		cfg.LLM.Anthropic.Timeout,
+	)
+	provider.SetRetryPolicy(retryPolicyFromConfig(cfg))
+	provider.SetSamplingParams(samplingFromConfig(cfg))
+
+	if cfg.Documentation.Deterministic {
+		logging.Warnf("⚠ Anthropic does not support a seed; output may still vary slightly between runs")
+	} else if cfg.LLM.Seed != 0 {
+		logging.Debugf("Anthropic does not support seed; ignoring llm.seed")
+	}
+
+	return provider, nil
+}
+
+// newOpenAIFromConfig creates an OpenAI provider from config
+func newOpenAIFromConfig(cfg *config.Config) (Provider, error) {
+	if cfg.LLM.OpenAI.APIKey == "" {
+		return nil, fmt.Errorf("OpenAI API key not configured (set OPENAI_API_KEY)")
+	}
+
+	provider := NewOpenAIProvider(
+		cfg.LLM.OpenAI.APIKey,
+		modelFor(cfg, cfg.LLM.OpenAI.Model),
+		cfg.LLM.OpenAI.MaxTokens,
+		cfg.LLM.OpenAI.BaseURL,
+		cfg.LLM.OpenAI.Timeout,
+	)
+	provider.SetRetryPolicy(retryPolicyFromConfig(cfg))
+	provider.SetSamplingParams(samplingFromConfig(cfg))
+
+	return provider, nil
 }
 
 // newOllamaFromConfig creates an Ollama provider from config
 func newOllamaFromConfig(cfg *config.Config) (Provider, error) {
-	provider := NewOllamaProvider(
-		cfg.LLM.Ollama.Endpoint,
-		cfg.LLM.Ollama.Model,
-		cfg.LLM.Ollama.ContextSize,
-		cfg.LLM.Ollama.Timeout,
-	)
+	provider := newOllama(cfg)
 
 	// Check if Ollama is actually available
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
@@ -52,38 +191,108 @@ func newOllamaFromConfig(cfg *config.Config) (Provider, error) {
 		return nil, fmt.Errorf("Ollama not available: %w", err)
 	}
 
+	if err := selectOllamaModel(ctx, cfg, provider); err != nil {
+		return nil, err
+	}
+
 	return provider, nil
 }
 
-// detectProvider auto-detects the best available provider
-func detectProvider(cfg *config.Config) (Provider, error) {
-	// Try Ollama first (free and local)
-	ollama := NewOllamaProvider(
+// newOllama builds an Ollama provider with its fallback models. A --model
+// override is taken literally, so the fallback list is ignored.
+func newOllama(cfg *config.Config) *OllamaProvider {
+	provider := NewOllamaProvider(
 		cfg.LLM.Ollama.Endpoint,
-		cfg.LLM.Ollama.Model,
+		modelFor(cfg, cfg.LLM.Ollama.Model),
 		cfg.LLM.Ollama.ContextSize,
 		cfg.LLM.Ollama.Timeout,
 	)
+	provider.SetRetryPolicy(retryPolicyFromConfig(cfg))
+	provider.SetSamplingParams(samplingFromConfig(cfg))
+	provider.SetKeepAlive(cfg.LLM.Ollama.KeepAlive)
+
+	if cfg.LLM.Model == "" {
+		provider.SetFallbackModels(cfg.LLM.Ollama.Models)
+	}
+
+	return provider
+}
+
+// selectOllamaModel picks the first configured model that is pulled
+func selectOllamaModel(ctx context.Context, cfg *config.Config, provider *OllamaProvider) error {
+	// The override is validated separately with a more specific error
+	if cfg.LLM.Model != "" {
+		return nil
+	}
+
+	model, err := provider.SelectModel(ctx)
+	if err != nil {
+		return fmt.Errorf("Ollama model not available: %w", err)
+	}
+
+	logging.Debugf("Using Ollama model %s", model)
+
+	return nil
+}
+
+// detectProvider auto-detects the best available provider
+func detectProvider(cfg *config.Config) (Provider, error) {
+	// Try Ollama first (free and local)
+	ollama := newOllama(cfg)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 	defer cancel()
 
+	// A reachable server with none of our models pulled is no use either
 	if err := ollama.Ping(ctx); err == nil {
-		fmt.Println("Using Ollama (local, free)")
-		return ollama, nil
+		err := selectOllamaModel(ctx, cfg, ollama)
+		if err == nil {
+			logging.Infof("Using Ollama (local, free)")
+			return ollama, nil
+		}
+		logging.Debugf("Skipping Ollama: %v", err)
 	}
 
 	// Fall back to Anthropic
 	if cfg.LLM.Anthropic.APIKey != "" {
-		fmt.Println("Using Anthropic Claude")
-		return NewAnthropicProvider(
-			cfg.LLM.Anthropic.APIKey,
-			cfg.LLM.Anthropic.Model,
-			cfg.LLM.Anthropic.MaxTokens,
-		), nil
+		logging.Infof("Using Anthropic Claude")
+		return newAnthropicFromConfig(cfg)
 	}
 
-	return nil, fmt.Errorf("no LLM provider available (tried Ollama and Anthropic)")
+	// Fall back to OpenAI
+	if cfg.LLM.OpenAI.APIKey != "" {
+		logging.Infof("Using OpenAI")
+		return newOpenAIFromConfig(cfg)
+	}
+
+	return nil, fmt.Errorf("no LLM provider available (tried Ollama, Anthropic and OpenAI)")
+}
+
+// retryPolicyFromConfig builds the retry policy from performance settings
+func retryPolicyFromConfig(cfg *config.Config) RetryPolicy {
+	return RetryPolicy{
+		MaxRetries: cfg.Performance.MaxRetries,
+		Backoff:    cfg.Performance.RetryBackoff,
+	}
+}
+
+// samplingFromConfig builds the sampling parameters from LLM settings.
+// Deterministic runs use greedy sampling and a fixed seed.
+func samplingFromConfig(cfg *config.Config) SamplingParams {
+	params := SamplingParams{
+		Temperature: cfg.LLM.Temperature,
+		TopP:        cfg.LLM.TopP,
+		Seed:        cfg.LLM.Seed,
+	}
+
+	if cfg.Documentation.Deterministic {
+		params.Temperature = 0
+		if params.Seed == 0 {
+			params.Seed = DeterministicSeed
+		}
+	}
+
+	return params
 }
 
 // CheckProviderStatus checks the status of all configured providers
@@ -114,5 +323,12 @@ func CheckProviderStatus(cfg *config.Config) map[string]string {
 		status["anthropic"] = "not configured (missing API key)"
 	}
 
+	// Check OpenAI
+	if cfg.LLM.OpenAI.APIKey != "" {
+		status["openai"] = "configured"
+	} else {
+		status["openai"] = "not configured (missing API key)"
+	}
+
 	return status
 }
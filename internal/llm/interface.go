@@ -2,6 +2,7 @@ package llm
 
 import (
 	"context"
+	"sync"
 )
 
 // Provider defines the interface for LLM providers
@@ -21,8 +22,33 @@ type Provider interface {
 	// Generate generates documentation content
 	Generate(ctx context.Context, req GenerateRequest) (string, error)
 
-	// EstimateCost estimates the cost for a given number of tokens
-	EstimateCost(tokens int) float64
+	// EstimateCost estimates the cost of the given token usage, pricing
+	// input and output tokens separately
+	EstimateCost(usage TokenUsage) float64
+}
+
+// StreamingProvider is implemented by providers that can stream generated
+// content as it is produced
+type StreamingProvider interface {
+	Provider
+
+	// GenerateStream generates documentation content, calling onChunk with
+	// each partial piece of output. It returns the fully assembled content.
+	GenerateStream(ctx context.Context, req GenerateRequest, onChunk func(chunk string)) (string, error)
+}
+
+// Warmer is implemented by providers that can load their model ahead of the
+// first request
+type Warmer interface {
+	// Warmup loads the model, doing nothing if it is already loaded
+	Warmup(ctx context.Context) error
+}
+
+// UsageReporter is implemented by providers that report the tokens they have
+// consumed so far
+type UsageReporter interface {
+	// GetUsage returns the cumulative token usage
+	GetUsage() TokenUsage
 }
 
 // AnalysisRequest represents a request to analyze a codebase component
@@ -43,10 +69,10 @@ type FileContent struct {
 
 // AnalysisResult contains the structured analysis results
 type AnalysisResult struct {
-	Overview     string        `json:"overview"`
-	Components   []Component   `json:"components"`
-	Services     []Service     `json:"services"`
-	Architecture Architecture  `json:"architecture"`
+	Overview     string       `json:"overview"`
+	Components   []Component  `json:"components"`
+	Services     []Service    `json:"services"`
+	Architecture Architecture `json:"architecture"`
 }
 
 // Component represents a codebase component
@@ -109,3 +135,55 @@ type TokenUsage struct {
 	InputTokens  int
 	OutputTokens int
 }
+
+// usageCounter accumulates token usage; it is safe for concurrent use since
+// providers are called in parallel by the pool
+type usageCounter struct {
+	mu    sync.Mutex
+	usage TokenUsage
+}
+
+// add records the tokens used by one API call, also crediting any
+// UsageRecorder attached to the call's context
+func (c *usageCounter) add(ctx context.Context, inputTokens, outputTokens int) {
+	c.addTokens(inputTokens, outputTokens)
+
+	if recorder, ok := ctx.Value(usageRecorderKey{}).(*UsageRecorder); ok {
+		recorder.addTokens(inputTokens, outputTokens)
+	}
+}
+
+// addTokens adds to the running total
+func (c *usageCounter) addTokens(inputTokens, outputTokens int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.usage.InputTokens += inputTokens
+	c.usage.OutputTokens += outputTokens
+}
+
+// get returns the total usage so far
+func (c *usageCounter) get() TokenUsage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.usage
+}
+
+// usageRecorderKey is the context key for a UsageRecorder
+type usageRecorderKey struct{}
+
+// UsageRecorder collects the tokens used by API calls made with a context
+// from WithUsageRecorder. It lets callers attribute usage to one unit of work
+// while other work shares the same provider.
+type UsageRecorder struct {
+	usageCounter
+}
+
+// Usage returns the tokens recorded so far
+func (r *UsageRecorder) Usage() TokenUsage {
+	return r.get()
+}
+
+// WithUsageRecorder returns a context whose API calls are also counted by recorder
+func WithUsageRecorder(ctx context.Context, recorder *UsageRecorder) context.Context {
+	return context.WithValue(ctx, usageRecorderKey{}, recorder)
+}
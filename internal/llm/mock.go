@@ -0,0 +1,168 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// mockChunkSize is how many characters MockProvider streams per chunk
+const mockChunkSize = 64
+
+// MockProvider returns canned responses derived from the request, so the
+// full pipeline can run offline for demos and tests. The same request
+// always produces the same response.
+type MockProvider struct {
+	usage usageCounter
+}
+
+// NewMockProvider creates a mock provider
+func NewMockProvider() *MockProvider {
+	return &MockProvider{}
+}
+
+// Name returns the provider name
+func (m *MockProvider) Name() string {
+	return "mock"
+}
+
+// IsAvailable checks if the provider is available
+func (m *MockProvider) IsAvailable() bool {
+	return true
+}
+
+// Ping checks if the provider is reachable
+func (m *MockProvider) Ping(ctx context.Context) error {
+	return ctx.Err()
+}
+
+// Analyze returns a canned analysis of the component
+func (m *MockProvider) Analyze(ctx context.Context, req AnalysisRequest) (*AnalysisResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	name := mockName(req.ComponentName)
+	overview := fmt.Sprintf("%s is a %s %s located at `%s`. This overview was produced by the mock provider.",
+		name, mockValue(req.Language, "multi-language"), mockValue(req.ComponentType, "component"), mockValue(req.Path, "."))
+
+	result := &AnalysisResult{
+		Overview: overview,
+		Components: []Component{{
+			Name:        name,
+			Type:        req.ComponentType,
+			Language:    req.Language,
+			Path:        req.Path,
+			Description: overview,
+		}},
+		Architecture: Architecture{
+			Overview:     fmt.Sprintf("%s is organised around %d key files.", name, len(req.KeyFiles)),
+			Components:   []string{name},
+			Technologies: mockTechnologies(req.Language),
+		},
+	}
+
+	m.record(ctx, mockAnalysisPrompt(req), overview)
+	return result, nil
+}
+
+// Generate returns canned markdown documentation for the component
+func (m *MockProvider) Generate(ctx context.Context, req GenerateRequest) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	prompt := req.Prompt
+	if prompt == "" {
+		for _, file := range req.Files {
+			prompt += file.Content
+		}
+	}
+
+	content := mockDocs(req)
+	m.record(ctx, prompt, content)
+	return content, nil
+}
+
+// GenerateStream returns the same content as Generate, delivered in chunks
+func (m *MockProvider) GenerateStream(ctx context.Context, req GenerateRequest, onChunk func(chunk string)) (string, error) {
+	content, err := m.Generate(ctx, req)
+	if err != nil {
+		return "", err
+	}
+
+	if onChunk != nil {
+		for start := 0; start < len(content); start += mockChunkSize {
+			onChunk(content[start:min(start+mockChunkSize, len(content))])
+		}
+	}
+	return content, nil
+}
+
+// EstimateCost returns zero; the mock provider is free
+func (m *MockProvider) EstimateCost(usage TokenUsage) float64 {
+	return 0
+}
+
+// GetUsage returns the total token usage
+func (m *MockProvider) GetUsage() TokenUsage {
+	return m.usage.get()
+}
+
+// record counts tokens at roughly four characters each, so usage reporting
+// has realistic-looking numbers to show
+func (m *MockProvider) record(ctx context.Context, prompt, response string) {
+	m.usage.add(ctx, len(prompt)/4, len(response)/4)
+}
+
+// mockDocs builds the markdown returned by Generate
+func mockDocs(req GenerateRequest) string {
+	name := mockName(req.ComponentName)
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("## Purpose\n\n%s is a %s %s. This documentation was produced by the mock provider.\n\n",
+		name, mockValue(req.Language, "multi-language"), mockValue(req.ComponentType, "component")))
+
+	sb.WriteString("## Structure\n\n")
+	if len(req.Files) == 0 {
+		sb.WriteString(fmt.Sprintf("The component lives in `%s`.\n", mockValue(req.Path, ".")))
+	}
+	for _, file := range req.Files {
+		sb.WriteString(fmt.Sprintf("- `%s` (%d lines)\n", file.Path, strings.Count(file.Content, "\n")+1))
+	}
+
+	sb.WriteString(fmt.Sprintf("\n## Usage\n\n```bash\n# Replace with real usage instructions for %s\n```\n", name))
+	return sb.String()
+}
+
+// mockAnalysisPrompt approximates the prompt a real provider would receive,
+// for token accounting
+func mockAnalysisPrompt(req AnalysisRequest) string {
+	var sb strings.Builder
+	sb.WriteString(req.FileTree)
+	for _, file := range req.KeyFiles {
+		sb.WriteString(file.Content)
+	}
+	return sb.String()
+}
+
+// mockTechnologies lists the technologies reported for a language
+func mockTechnologies(language string) []string {
+	if language == "" {
+		return nil
+	}
+	return []string{language}
+}
+
+// mockName returns a display name for a component
+func mockName(name string) string {
+	return mockValue(name, "This repository")
+}
+
+// mockValue returns value, or fallback if value is empty
+func mockValue(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
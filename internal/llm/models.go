@@ -0,0 +1,119 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const anthropicModelsURL = "https://api.anthropic.com/v1/models/"
+
+// ModelValidator is implemented by providers that can check, without
+// generating anything, that their configured model exists
+type ModelValidator interface {
+	// ValidateModel returns an error if the provider rejects the model
+	ValidateModel(ctx context.Context) error
+}
+
+// ValidateModel checks the model against Anthropic's models endpoint
+func (a *AnthropicProvider) ValidateModel(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", anthropicModelsURL+url.PathEscape(a.model), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("x-api-key", a.apiKey)
+	req.Header.Set("anthropic-version", "2023-06-01")
+
+	return checkModelResponse(a.client, req)
+}
+
+// ValidateModel checks the model against the OpenAI models endpoint. Azure
+// deployments are named by the resource owner and can't be listed this way.
+func (o *OpenAIProvider) ValidateModel(ctx context.Context) error {
+	if o.isAzure() {
+		return nil
+	}
+
+	u, err := url.Parse(o.baseURL)
+	if err != nil {
+		return fmt.Errorf("invalid base_url: %w", err)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/models/" + url.PathEscape(o.model)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+o.apiKey)
+
+	return checkModelResponse(o.client, req)
+}
+
+// ValidateModel checks that the model has been pulled into Ollama
+func (o *OllamaProvider) ValidateModel(ctx context.Context) error {
+	pulled, err := o.pulledModels(ctx)
+	if err != nil {
+		return err
+	}
+
+	model := o.currentModel()
+	if !containsModel(pulled, model) {
+		return fmt.Errorf("model not found locally (run: ollama pull %s)", model)
+	}
+
+	return nil
+}
+
+// pulledModels lists the models available on the Ollama server
+func (o *OllamaProvider) pulledModels(ctx context.Context) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", o.endpoint+"/api/tags", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("ollama not available: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ollama returned status %d", resp.StatusCode)
+	}
+
+	var tags struct {
+		Models []struct {
+			Name string `json:"name"`
+		} `json:"models"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tags); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	names := make([]string, 0, len(tags.Models))
+	for _, model := range tags.Models {
+		names = append(names, model.Name)
+	}
+	return names, nil
+}
+
+// checkModelResponse performs a model lookup request and turns a not-found
+// response into an error
+func checkModelResponse(client *http.Client, req *http.Request) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return newAPIError(resp, body)
+	}
+
+	return nil
+}
@@ -4,20 +4,32 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/docbrown/cli/internal/httpclient"
+	"github.com/docbrown/cli/internal/logging"
 )
 
 // OllamaProvider implements the Provider interface for Ollama
 type OllamaProvider struct {
 	endpoint    string
-	model       string
+	mu          sync.Mutex
+	model       string   // current model, guarded by mu
+	models      []string // candidates in priority order, starting with model
 	contextSize int
+	keepAlive   string // how long the server keeps the model loaded, "" for its default
 	timeout     time.Duration
 	client      *http.Client
+	usage       usageCounter
+	retry       RetryPolicy
+	sampling    SamplingParams
 }
 
 // NewOllamaProvider creates a new Ollama provider
@@ -38,14 +50,132 @@ func NewOllamaProvider(endpoint, model string, contextSize int, timeout time.Dur
 	return &OllamaProvider{
 		endpoint:    endpoint,
 		model:       model,
+		models:      []string{model},
 		contextSize: contextSize,
 		timeout:     timeout,
-		client: &http.Client{
-			Timeout: timeout,
-		},
+		client:      httpclient.New(timeout),
+		retry:       DefaultRetryPolicy(),
+		sampling:    DefaultSamplingParams(),
 	}
 }
 
+// SetRetryPolicy sets the retry policy for transient API errors
+func (o *OllamaProvider) SetRetryPolicy(policy RetryPolicy) {
+	o.retry = policy
+}
+
+// SetSamplingParams sets the temperature, top_p and seed sent with each request
+func (o *OllamaProvider) SetSamplingParams(params SamplingParams) {
+	o.sampling = params
+}
+
+// SetKeepAlive sets how long Ollama keeps the model loaded after a request,
+// as a duration ("10m") or a number of seconds (-1 keeps it loaded)
+func (o *OllamaProvider) SetKeepAlive(keepAlive string) {
+	o.keepAlive = keepAlive
+}
+
+// SetFallbackModels adds models to try, in order, when the preferred one
+// isn't pulled or disappears mid-run
+func (o *OllamaProvider) SetFallbackModels(models []string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for _, model := range models {
+		if model != "" && !containsModel(o.models, model) {
+			o.models = append(o.models, model)
+		}
+	}
+}
+
+// SelectModel switches to the first candidate model that is actually pulled
+// on the Ollama server and returns its name
+func (o *OllamaProvider) SelectModel(ctx context.Context) (string, error) {
+	pulled, err := o.pulledModels(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for _, model := range o.models {
+		if containsModel(pulled, model) {
+			o.model = model
+			return model, nil
+		}
+	}
+
+	return "", fmt.Errorf("none of the configured models are pulled (%s); run: ollama pull %s",
+		strings.Join(o.models, ", "), o.models[0])
+}
+
+// currentModel returns the model requests are currently sent to
+func (o *OllamaProvider) currentModel() string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.model
+}
+
+// nextModel moves past a model that the server no longer has. If another
+// request already moved on, the current model is returned instead.
+func (o *OllamaProvider) nextModel(failed string) (string, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.model != failed {
+		return o.model, true
+	}
+
+	for i, model := range o.models {
+		if model == failed && i+1 < len(o.models) {
+			o.model = o.models[i+1]
+			return o.model, true
+		}
+	}
+
+	return "", false
+}
+
+// withModelFallback runs fn against the current model, moving down the
+// candidate list whenever Ollama reports the model as not found
+func (o *OllamaProvider) withModelFallback(fn func(model string) error) error {
+	model := o.currentModel()
+	for {
+		err := fn(model)
+		if !isModelNotFound(err) {
+			return err
+		}
+
+		next, ok := o.nextModel(model)
+		if !ok {
+			return err
+		}
+		logging.Warnf("  ⚠ Ollama model %s not found, falling back to %s", model, next)
+		model = next
+	}
+}
+
+// isModelNotFound reports whether Ollama rejected a request because the
+// model isn't pulled
+func isModelNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) &&
+		apiErr.StatusCode == http.StatusNotFound &&
+		strings.Contains(apiErr.Body, "not found")
+}
+
+// containsModel reports whether a model list includes model, treating a
+// missing tag as ":latest"
+func containsModel(models []string, model string) bool {
+	for _, m := range models {
+		if m == model || m == model+":latest" || m+":latest" == model {
+			return true
+		}
+	}
+	return false
+}
+
 // Name returns the provider name
 func (o *OllamaProvider) Name() string {
 	return "ollama"
@@ -82,21 +212,9 @@ func (o *OllamaProvider) Ping(ctx context.Context) error {
 func (o *OllamaProvider) Analyze(ctx context.Context, req AnalysisRequest) (*AnalysisResult, error) {
 	prompt := o.buildAnalysisPrompt(req)
 
-	response, err := o.generateWithFormat(ctx, prompt, true)
-	if err != nil {
-		return nil, fmt.Errorf("analysis failed: %w", err)
-	}
-
-	// Parse JSON response
-	var result AnalysisResult
-	if err := json.Unmarshal([]byte(response), &result); err != nil {
-		// If JSON parsing fails, return a simple result
-		return &AnalysisResult{
-			Overview: response,
-		}, nil
-	}
-
-	return &result, nil
+	return analyzeWithRepair(ctx, prompt, func(ctx context.Context, prompt string) (string, error) {
+		return o.generateWithFormat(ctx, prompt, true)
+	})
 }
 
 // Generate generates documentation content
@@ -115,60 +233,353 @@ func (o *OllamaProvider) Generate(ctx context.Context, req GenerateRequest) (str
 }
 
 // EstimateCost estimates the cost (Ollama is free)
-func (o *OllamaProvider) EstimateCost(tokens int) float64 {
+func (o *OllamaProvider) EstimateCost(usage TokenUsage) float64 {
 	return 0.0 // Ollama is free
 }
 
-// generateWithFormat makes a generation request to Ollama
-func (o *OllamaProvider) generateWithFormat(ctx context.Context, prompt string, jsonFormat bool) (string, error) {
+// GetUsage returns the total token usage
+func (o *OllamaProvider) GetUsage() TokenUsage {
+	return o.usage.get()
+}
+
+// Warmup loads the current model into memory so the first real request
+// doesn't pay for it, which after idle can take longer than the timeout.
+// It does nothing if the model is already loaded.
+func (o *OllamaProvider) Warmup(ctx context.Context) error {
+	model := o.currentModel()
+	if loaded, err := o.loadedModels(ctx); err == nil && containsModel(loaded, model) {
+		logging.Debugf("Ollama model %s is already loaded", model)
+		return nil
+	}
+
+	logging.Infof("⏳ Loading Ollama model %s (the first call after idle can be slow)...", model)
+	start := time.Now()
+
+	// A generate request without a prompt just loads the model
 	reqBody := map[string]interface{}{
-		"model":  o.model,
-		"prompt": prompt,
+		"model":  model,
 		"stream": false,
-		"options": map[string]interface{}{
-			"temperature": 0.7,
-			"num_ctx":     o.contextSize,
-		},
 	}
-
-	// Only add format for JSON responses
-	if jsonFormat {
-		reqBody["format"] = "json"
+	if keepAlive := o.keepAliveValue(); keepAlive != nil {
+		reqBody["keep_alive"] = keepAlive
 	}
 
 	bodyBytes, err := json.Marshal(reqBody)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %w", err)
+		return fmt.Errorf("failed to marshal request: %w", err)
 	}
 
 	req, err := http.NewRequestWithContext(ctx, "POST", o.endpoint+"/api/generate", bytes.NewReader(bodyBytes))
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return fmt.Errorf("failed to create request: %w", err)
 	}
-
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := o.client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("request failed: %w", err)
+		return fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+		return newAPIError(resp, body)
 	}
+	io.Copy(io.Discard, resp.Body)
+
+	logging.Debugf("Loaded Ollama model %s in %s", model, time.Since(start).Round(time.Millisecond))
+	return nil
+}
+
+// loadedModels lists the models Ollama currently has in memory
+func (o *OllamaProvider) loadedModels(ctx context.Context) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", o.endpoint+"/api/ps", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ollama returned status %d", resp.StatusCode)
+	}
+
+	var ps struct {
+		Models []struct {
+			Name string `json:"name"`
+		} `json:"models"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&ps); err != nil {
+		return nil, err
+	}
+
+	models := make([]string, 0, len(ps.Models))
+	for _, m := range ps.Models {
+		models = append(models, m.Name)
+	}
+	return models, nil
+}
+
+// keepAliveValue returns keep_alive as Ollama expects it: a number of
+// seconds or a duration string, or nil to use the server default
+func (o *OllamaProvider) keepAliveValue() interface{} {
+	if o.keepAlive == "" {
+		return nil
+	}
+	if seconds, err := strconv.Atoi(o.keepAlive); err == nil {
+		return seconds
+	}
+	return o.keepAlive
+}
+
+// GenerateStream generates documentation content, streaming partial output
+// to onChunk as it arrives. The returned string is the fully assembled response.
+func (o *OllamaProvider) GenerateStream(ctx context.Context, req GenerateRequest, onChunk func(chunk string)) (string, error) {
+	prompt := req.Prompt
+	if prompt == "" {
+		prompt = o.buildGeneratePrompt(req)
+	}
+
+	response, err := o.streamWithFormat(ctx, prompt, false, onChunk)
+	if err != nil {
+		return "", fmt.Errorf("generation failed: %w", err)
+	}
+
+	return response, nil
+}
+
+// PullProgress is one status update streamed while Ollama downloads a model
+type PullProgress struct {
+	Status    string `json:"status"`
+	Digest    string `json:"digest"`
+	Total     int64  `json:"total"`
+	Completed int64  `json:"completed"`
+}
+
+// Pull downloads a model into Ollama, passing each streamed status update
+// to onProgress. Downloads can take far longer than a generation request, so
+// only ctx bounds the call.
+func (o *OllamaProvider) Pull(ctx context.Context, model string, onProgress func(PullProgress)) error {
+	bodyBytes, err := json.Marshal(map[string]interface{}{
+		"model":  model,
+		"stream": true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", o.endpoint+"/api/pull", bytes.NewReader(bodyBytes))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpclient.New(0).Do(req)
+	if err != nil {
+		return fmt.Errorf("ollama not available: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return newAPIError(resp, body)
+	}
+
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		var chunk struct {
+			PullProgress
+			Error string `json:"error"`
+		}
+
+		if err := decoder.Decode(&chunk); err != nil {
+			if err == io.EOF {
+				// The stream ends with "success"; anything else was cut short
+				return fmt.Errorf("pull ended before completing")
+			}
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			return fmt.Errorf("failed to decode pull status: %w", err)
+		}
+
+		if chunk.Error != "" {
+			return fmt.Errorf("pull failed: %s", chunk.Error)
+		}
+
+		if onProgress != nil {
+			onProgress(chunk.PullProgress)
+		}
+
+		if chunk.Status == "success" {
+			return nil
+		}
+	}
+}
+
+// generateWithFormat makes a generation request to Ollama, retrying transient failures
+func (o *OllamaProvider) generateWithFormat(ctx context.Context, prompt string, jsonFormat bool) (string, error) {
+	var text string
+	err := o.withModelFallback(func(model string) error {
+		return withRetry(ctx, o.retry, func() error {
+			return o.generateOnce(ctx, model, prompt, jsonFormat, &text)
+		})
+	})
+	return text, err
+}
+
+// generateOnce makes a single non-streaming generation request
+func (o *OllamaProvider) generateOnce(ctx context.Context, model, prompt string, jsonFormat bool, text *string) error {
+	resp, err := o.doGenerate(ctx, model, prompt, jsonFormat, false)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
 
 	var response struct {
-		Response string `json:"response"`
-		Done     bool   `json:"done"`
+		Response        string `json:"response"`
+		Done            bool   `json:"done"`
+		PromptEvalCount int    `json:"prompt_eval_count"`
+		EvalCount       int    `json:"eval_count"`
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return "", fmt.Errorf("failed to decode response: %w", err)
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	// Track usage
+	o.usage.add(ctx, response.PromptEvalCount, response.EvalCount)
+
+	*text = response.Response
+	return nil
+}
+
+// streamWithFormat makes a streaming generation request to Ollama, decoding
+// the newline-delimited JSON chunks and passing each partial response to onChunk
+func (o *OllamaProvider) streamWithFormat(ctx context.Context, prompt string, jsonFormat bool, onChunk func(chunk string)) (string, error) {
+	// Only the initial request is retried; chunks already emitted can't be replayed
+	var resp *http.Response
+	err := o.withModelFallback(func(model string) error {
+		return withRetry(ctx, o.retry, func() error {
+			var err error
+			resp, err = o.doGenerate(ctx, model, prompt, jsonFormat, true)
+			return err
+		})
+	})
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var sb strings.Builder
+	decoder := json.NewDecoder(resp.Body)
+
+	for {
+		// Abort mid-stream if the caller gave up
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+
+		var chunk struct {
+			Response        string `json:"response"`
+			Done            bool   `json:"done"`
+			Error           string `json:"error"`
+			PromptEvalCount int    `json:"prompt_eval_count"`
+			EvalCount       int    `json:"eval_count"`
+		}
+
+		if err := decoder.Decode(&chunk); err != nil {
+			if err == io.EOF {
+				break
+			}
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return "", ctxErr
+			}
+			return "", fmt.Errorf("failed to decode stream chunk: %w", err)
+		}
+
+		if chunk.Error != "" {
+			return "", fmt.Errorf("stream error: %s", chunk.Error)
+		}
+
+		if chunk.Response != "" {
+			sb.WriteString(chunk.Response)
+			if onChunk != nil {
+				onChunk(chunk.Response)
+			}
+		}
+
+		if chunk.Done {
+			// Token counts are only reported on the final chunk
+			o.usage.add(ctx, chunk.PromptEvalCount, chunk.EvalCount)
+			break
+		}
+	}
+
+	return sb.String(), nil
+}
+
+// options builds the generation options for a request
+func (o *OllamaProvider) options() map[string]interface{} {
+	options := map[string]interface{}{
+		"temperature": o.sampling.Temperature,
+		"num_ctx":     o.contextSize,
+	}
+	if o.sampling.TopP > 0 {
+		options["top_p"] = o.sampling.TopP
+	}
+	if o.sampling.Seed != 0 {
+		options["seed"] = o.sampling.Seed
+	}
+	return options
+}
+
+// doGenerate sends a request to the Ollama generate endpoint and returns the
+// response once the status has been checked. The caller must close the body.
+func (o *OllamaProvider) doGenerate(ctx context.Context, model, prompt string, jsonFormat, stream bool) (*http.Response, error) {
+	reqBody := map[string]interface{}{
+		"model":   model,
+		"prompt":  prompt,
+		"stream":  stream,
+		"options": o.options(),
+	}
+
+	// Only add format for JSON responses
+	if jsonFormat {
+		reqBody["format"] = "json"
+	}
+	if keepAlive := o.keepAliveValue(); keepAlive != nil {
+		reqBody["keep_alive"] = keepAlive
+	}
+
+	bodyBytes, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", o.endpoint+"/api/generate", bytes.NewReader(bodyBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		return nil, newAPIError(resp, body)
 	}
 
-	return response.Response, nil
+	return resp, nil
 }
 
 // buildAnalysisPrompt builds the analysis prompt
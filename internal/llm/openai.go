@@ -0,0 +1,297 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/docbrown/cli/internal/httpclient"
+)
+
+const openAIDefaultBaseURL = "https://api.openai.com/v1"
+
+// OpenAIProvider implements the Provider interface for OpenAI chat completions
+type OpenAIProvider struct {
+	apiKey    string
+	model     string
+	maxTokens int
+	baseURL   string
+	timeout   time.Duration
+	client    *http.Client
+	usage     usageCounter
+	retry     RetryPolicy
+	sampling  SamplingParams
+}
+
+// NewOpenAIProvider creates a new OpenAI provider
+func NewOpenAIProvider(apiKey, model string, maxTokens int, baseURL string, timeout time.Duration) *OpenAIProvider {
+	if model == "" {
+		model = "gpt-4o"
+	}
+	if maxTokens == 0 {
+		maxTokens = 4096
+	}
+	if baseURL == "" {
+		baseURL = openAIDefaultBaseURL
+	}
+	if timeout == 0 {
+		timeout = 120 * time.Second
+	}
+
+	return &OpenAIProvider{
+		apiKey:    apiKey,
+		model:     model,
+		maxTokens: maxTokens,
+		baseURL:   baseURL,
+		timeout:   timeout,
+		client:    httpclient.New(timeout),
+		retry:     DefaultRetryPolicy(),
+		sampling:  DefaultSamplingParams(),
+	}
+}
+
+// SetRetryPolicy sets the retry policy for transient API errors
+func (o *OpenAIProvider) SetRetryPolicy(policy RetryPolicy) {
+	o.retry = policy
+}
+
+// SetSamplingParams sets the temperature, top_p and seed sent with each request
+func (o *OpenAIProvider) SetSamplingParams(params SamplingParams) {
+	o.sampling = params
+}
+
+// Name returns the provider name
+func (o *OpenAIProvider) Name() string {
+	return "openai"
+}
+
+// IsAvailable checks if the provider is available
+func (o *OpenAIProvider) IsAvailable() bool {
+	return o.apiKey != ""
+}
+
+// Ping checks if the provider is reachable
+func (o *OpenAIProvider) Ping(ctx context.Context) error {
+	// Simple test call with minimal tokens
+	_, err := o.callAPI(ctx, "Hello", 10, false)
+	return err
+}
+
+// Analyze analyzes a codebase component
+func (o *OpenAIProvider) Analyze(ctx context.Context, req AnalysisRequest) (*AnalysisResult, error) {
+	prompt := o.buildAnalysisPrompt(req)
+
+	return analyzeWithRepair(ctx, prompt, func(ctx context.Context, prompt string) (string, error) {
+		return o.callAPI(ctx, prompt, o.maxTokens, true)
+	})
+}
+
+// Generate generates documentation content
+func (o *OpenAIProvider) Generate(ctx context.Context, req GenerateRequest) (string, error) {
+	prompt := req.Prompt
+	if prompt == "" {
+		prompt = o.buildGeneratePrompt(req)
+	}
+
+	response, err := o.callAPI(ctx, prompt, o.maxTokens, false)
+	if err != nil {
+		return "", fmt.Errorf("generation failed: %w", err)
+	}
+
+	return response, nil
+}
+
+// EstimateCost estimates the cost of the given token usage (GPT-4o pricing)
+func (o *OpenAIProvider) EstimateCost(usage TokenUsage) float64 {
+	inputCost := float64(usage.InputTokens) * 0.0025 / 1000
+	outputCost := float64(usage.OutputTokens) * 0.010 / 1000
+
+	return inputCost + outputCost
+}
+
+// GetUsage returns the total token usage
+func (o *OpenAIProvider) GetUsage() TokenUsage {
+	return o.usage.get()
+}
+
+// completionsURL builds the chat completions URL, preserving any query
+// string on the base URL (Azure OpenAI requires ?api-version=...)
+func (o *OpenAIProvider) completionsURL() (string, error) {
+	u, err := url.Parse(o.baseURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid base_url: %w", err)
+	}
+
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/chat/completions"
+	return u.String(), nil
+}
+
+// isAzure reports whether the base URL points at an Azure OpenAI resource
+func (o *OpenAIProvider) isAzure() bool {
+	return strings.Contains(o.baseURL, ".openai.azure.com")
+}
+
+// callAPI makes a call to the OpenAI chat completions API, retrying transient failures
+func (o *OpenAIProvider) callAPI(ctx context.Context, prompt string, maxTokens int, jsonFormat bool) (string, error) {
+	var text string
+	err := withRetry(ctx, o.retry, func() error {
+		var err error
+		text, err = o.doCallAPI(ctx, prompt, maxTokens, jsonFormat)
+		return err
+	})
+	return text, err
+}
+
+// doCallAPI makes a single call to the OpenAI chat completions API
+func (o *OpenAIProvider) doCallAPI(ctx context.Context, prompt string, maxTokens int, jsonFormat bool) (string, error) {
+	// Always bound the request, even if the caller didn't set a deadline
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.timeout)
+		defer cancel()
+	}
+
+	reqBody := map[string]interface{}{
+		"model":      o.model,
+		"max_tokens": maxTokens,
+		"messages": []map[string]string{
+			{"role": "user", "content": prompt},
+		},
+		"temperature": o.sampling.Temperature,
+	}
+
+	if o.sampling.TopP > 0 {
+		reqBody["top_p"] = o.sampling.TopP
+	}
+	if o.sampling.Seed != 0 {
+		reqBody["seed"] = o.sampling.Seed
+	}
+
+	// Only request JSON mode for analysis responses
+	if jsonFormat {
+		reqBody["response_format"] = map[string]string{"type": "json_object"}
+	}
+
+	bodyBytes, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	endpoint, err := o.completionsURL()
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if o.isAzure() {
+		req.Header.Set("api-key", o.apiKey)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+o.apiKey)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", newAPIError(resp, body)
+	}
+
+	var response struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+		Usage struct {
+			PromptTokens     int `json:"prompt_tokens"`
+			CompletionTokens int `json:"completion_tokens"`
+		} `json:"usage"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	// Track usage
+	o.usage.add(ctx, response.Usage.PromptTokens, response.Usage.CompletionTokens)
+
+	if len(response.Choices) == 0 {
+		return "", fmt.Errorf("empty response from API")
+	}
+
+	return response.Choices[0].Message.Content, nil
+}
+
+// buildAnalysisPrompt builds the analysis prompt
+func (o *OpenAIProvider) buildAnalysisPrompt(req AnalysisRequest) string {
+	var sb strings.Builder
+
+	sb.WriteString("Analyze this codebase and return JSON with the following structure:\n")
+	sb.WriteString("{\n")
+	sb.WriteString(`  "overview": "High-level description of the project",` + "\n")
+	sb.WriteString(`  "components": [{"name": "...", "type": "service|library|frontend", "language": "...", "path": "...", "description": "..."}],` + "\n")
+	sb.WriteString(`  "services": [{"name": "...", "type": "rest|grpc|graphql", "description": "..."}],` + "\n")
+	sb.WriteString(`  "architecture": {"overview": "...", "patterns": [], "technologies": []}` + "\n")
+	sb.WriteString("}\n\n")
+
+	sb.WriteString("File Tree:\n")
+	sb.WriteString(req.FileTree)
+	sb.WriteString("\n\n")
+
+	if len(req.KeyFiles) > 0 {
+		sb.WriteString("Key Files:\n")
+		for _, file := range req.KeyFiles {
+			sb.WriteString(fmt.Sprintf("\n--- %s ---\n", file.Path))
+			sb.WriteString(file.Content)
+			sb.WriteString("\n")
+		}
+	}
+
+	return sb.String()
+}
+
+// buildGeneratePrompt builds the generation prompt
+func (o *OpenAIProvider) buildGeneratePrompt(req GenerateRequest) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("Document the following %s component in detail.\n\n", req.ComponentType))
+	sb.WriteString(fmt.Sprintf("Component: %s\n", req.ComponentName))
+	sb.WriteString(fmt.Sprintf("Type: %s\n", req.ComponentType))
+	sb.WriteString(fmt.Sprintf("Language: %s\n", req.Language))
+	sb.WriteString(fmt.Sprintf("Path: %s\n\n", req.Path))
+
+	if len(req.Files) > 0 {
+		sb.WriteString("Source Files:\n")
+		for _, file := range req.Files {
+			sb.WriteString(fmt.Sprintf("\n--- %s ---\n", file.Path))
+			sb.WriteString(file.Content)
+			sb.WriteString("\n")
+		}
+	}
+
+	sb.WriteString("\nGenerate comprehensive documentation covering:\n")
+	sb.WriteString("1. Purpose and Overview\n")
+	sb.WriteString("2. Architecture\n")
+	sb.WriteString("3. Public APIs and Interfaces\n")
+	sb.WriteString("4. Dependencies\n")
+	sb.WriteString("5. Configuration\n")
+	sb.WriteString("6. Usage Examples\n\n")
+	sb.WriteString("Output as well-formatted markdown.\n")
+
+	return sb.String()
+}
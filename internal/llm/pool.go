@@ -2,18 +2,33 @@ package llm
 
 import (
 	"context"
-	"fmt"
+	"sort"
 	"sync"
+
+	"github.com/docbrown/cli/internal/logging"
 )
 
-// Pool manages concurrent LLM operations
+// Pool manages concurrent LLM operations. When the provider becomes
+// unavailable, calls move on to the next of its fallbacks.
 type Pool struct {
-	provider      Provider
+	providers     []Provider // the provider, then its fallbacks in order
+	current       int        // index of the provider calls go to
 	semaphore     chan struct{}
 	maxConcurrent int
 	mu            sync.Mutex
 	totalCost     float64
 	totalTokens   int
+	lastUsage     map[string]TokenUsage     // per provider, as last reported
+	served        map[string]*ProviderUsage // per provider
+	active        map[string]int            // in-flight calls per component
+}
+
+// ProviderUsage is what one provider served during a run
+type ProviderUsage struct {
+	Provider string  `json:"provider"`
+	Requests int     `json:"requests"`
+	Tokens   int     `json:"tokens"`
+	Cost     float64 `json:"cost"`
 }
 
 // NewPool creates a new LLM pool
@@ -23,12 +38,21 @@ func NewPool(provider Provider, maxConcurrent int) *Pool {
 	}
 
 	return &Pool{
-		provider:      provider,
+		providers:     []Provider{provider},
 		semaphore:     make(chan struct{}, maxConcurrent),
 		maxConcurrent: maxConcurrent,
+		lastUsage:     make(map[string]TokenUsage),
+		served:        make(map[string]*ProviderUsage),
+		active:        make(map[string]int),
 	}
 }
 
+// SetFallbacks sets the providers to try, in order, once the provider is
+// unavailable
+func (p *Pool) SetFallbacks(providers ...Provider) {
+	p.providers = append(p.providers[:1], providers...)
+}
+
 // Execute executes a function with concurrency control
 func (p *Pool) Execute(ctx context.Context, fn func() error) error {
 	select {
@@ -46,7 +70,11 @@ func (p *Pool) Analyze(ctx context.Context, req AnalysisRequest) (*AnalysisResul
 	var err error
 
 	execErr := p.Execute(ctx, func() error {
-		result, err = p.provider.Analyze(ctx, req)
+		defer p.track(req.ComponentName)()
+		err = p.call(ctx, func(provider Provider) error {
+			result, err = provider.Analyze(ctx, req)
+			return err
+		})
 		return err
 	})
 
@@ -63,7 +91,11 @@ func (p *Pool) Generate(ctx context.Context, req GenerateRequest) (string, error
 	var err error
 
 	execErr := p.Execute(ctx, func() error {
-		result, err = p.provider.Generate(ctx, req)
+		defer p.track(req.ComponentName)()
+		err = p.call(ctx, func(provider Provider) error {
+			result, err = provider.Generate(ctx, req)
+			return err
+		})
 		return err
 	})
 
@@ -74,49 +106,194 @@ func (p *Pool) Generate(ctx context.Context, req GenerateRequest) (string, error
 	return result, err
 }
 
-// GenerateParallel generates documentation for multiple components in parallel
-func (p *Pool) GenerateParallel(ctx context.Context, requests []GenerateRequest) ([]string, error) {
-	results := make([]string, len(requests))
-	errors := make([]error, len(requests))
+// GenerateStream performs streaming generation with concurrency control.
+// Providers without streaming support fall back to a single chunk containing
+// the full response.
+func (p *Pool) GenerateStream(ctx context.Context, req GenerateRequest, onChunk func(chunk string)) (string, error) {
+	var result string
+	var err error
+
+	execErr := p.Execute(ctx, func() error {
+		defer p.track(req.ComponentName)()
+		err = p.call(ctx, func(provider Provider) error {
+			if sp, ok := provider.(StreamingProvider); ok {
+				result, err = sp.GenerateStream(ctx, req, onChunk)
+				return err
+			}
 
-	var wg sync.WaitGroup
+			result, err = provider.Generate(ctx, req)
+			if err == nil && onChunk != nil {
+				onChunk(result)
+			}
+			return err
+		})
+		return err
+	})
 
-	for i, req := range requests {
-		wg.Add(1)
+	if execErr != nil {
+		return "", execErr
+	}
 
-		go func(idx int, request GenerateRequest) {
-			defer wg.Done()
+	return result, err
+}
 
-			result, err := p.Generate(ctx, request)
-			results[idx] = result
-			errors[idx] = err
-		}(i, req)
+// call runs fn with the current provider. If the provider is unavailable
+// (after its own retries), fn is retried with the next one in the chain,
+// which then serves all later calls too.
+func (p *Pool) call(ctx context.Context, fn func(provider Provider) error) error {
+	for {
+		index, provider := p.currentProvider()
+		err := fn(provider)
+		p.trackUsage(provider, err == nil)
+
+		if err == nil || ctx.Err() != nil || !isUnavailable(err) || !p.fallBack(index, err) {
+			return err
+		}
 	}
+}
 
-	wg.Wait()
+// currentProvider returns the provider calls go to and its index
+func (p *Pool) currentProvider() (int, Provider) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.current, p.providers[p.current]
+}
 
-	// Check for errors
-	for i, err := range errors {
-		if err != nil {
-			return results, fmt.Errorf("generation failed for request %d: %w", i, err)
+// fallBack moves past the provider at index after it failed with err. It
+// reports whether there is a later provider to retry with, which another
+// call may already have switched to.
+func (p *Pool) fallBack(index int, err error) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.current == index && index+1 < len(p.providers) {
+		p.current++
+		logging.Warnf("⚠ %s unavailable (%v), falling back to %s",
+			p.providers[index].Name(), err, p.providers[p.current].Name())
+	}
+	return p.current > index
+}
+
+// track marks a call for a component as in flight; the returned func ends it
+func (p *Pool) track(component string) func() {
+	p.mu.Lock()
+	p.active[component]++
+	p.mu.Unlock()
+
+	return func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		if p.active[component]--; p.active[component] <= 0 {
+			delete(p.active, component)
 		}
 	}
+}
+
+// Active returns the components with LLM calls currently in flight, sorted by name
+func (p *Pool) Active() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	names := make([]string, 0, len(p.active))
+	for name := range p.active {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
 
-	return results, nil
+// Warmup loads the model of the provider calls currently go to, if it
+// supports that. Fallbacks aren't warmed up since they may never be used. A
+// failure isn't fatal; the first real request will report the problem if it
+// persists.
+func (p *Pool) Warmup(ctx context.Context) {
+	warmer, ok := p.GetProvider().(Warmer)
+	if !ok {
+		return
+	}
+	if err := warmer.Warmup(ctx); err != nil && ctx.Err() == nil {
+		logging.Warnf("⚠ Failed to load %s model ahead of time: %v", p.GetProvider().Name(), err)
+	}
 }
 
-// GetProvider returns the underlying provider
+// GetProvider returns the provider calls currently go to
 func (p *Pool) GetProvider() Provider {
-	return p.provider
+	_, provider := p.currentProvider()
+	return provider
 }
 
-// TrackCost tracks the cost of an operation
-func (p *Pool) TrackCost(tokens int) {
+// HasFallbacks reports whether the pool can fall back to other providers
+func (p *Pool) HasFallbacks() bool {
+	return len(p.providers) > 1
+}
+
+// Usage returns what each provider that was called served, in fallback
+// chain order
+func (p *Pool) Usage() []ProviderUsage {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
+	var usage []ProviderUsage
+	for _, provider := range p.providers {
+		if served, ok := p.served[provider.Name()]; ok {
+			usage = append(usage, *served)
+		}
+	}
+	return usage
+}
+
+// MaxConcurrent returns the maximum number of concurrent LLM calls
+func (p *Pool) MaxConcurrent() int {
+	return p.maxConcurrent
+}
+
+// servedBy returns the usage record for provider; p.mu must be held
+func (p *Pool) servedBy(provider Provider) *ProviderUsage {
+	served, ok := p.served[provider.Name()]
+	if !ok {
+		served = &ProviderUsage{Provider: provider.Name()}
+		p.served[provider.Name()] = served
+	}
+	return served
+}
+
+// trackUsage records a call to provider and the tokens it has reported since
+// the last call, and is the only place cost is counted. Usage is cumulative,
+// so concurrent calls may be attributed to each other but the totals are
+// exact.
+func (p *Pool) trackUsage(provider Provider, succeeded bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if succeeded {
+		p.servedBy(provider).Requests++
+	}
+
+	reporter, ok := provider.(UsageReporter)
+	if !ok {
+		return
+	}
+
+	usage := reporter.GetUsage()
+	last := p.lastUsage[provider.Name()]
+	delta := TokenUsage{
+		InputTokens:  usage.InputTokens - last.InputTokens,
+		OutputTokens: usage.OutputTokens - last.OutputTokens,
+	}
+	p.lastUsage[provider.Name()] = usage
+
+	tokens := delta.InputTokens + delta.OutputTokens
+	if tokens <= 0 {
+		return
+	}
+
+	cost := provider.EstimateCost(delta)
 	p.totalTokens += tokens
-	p.totalCost += p.provider.EstimateCost(tokens)
+	p.totalCost += cost
+
+	served := p.servedBy(provider)
+	served.Tokens += tokens
+	served.Cost += cost
 }
 
 // GetTotalCost returns the total cost
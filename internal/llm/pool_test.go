@@ -0,0 +1,118 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"testing"
+)
+
+// fixedProvider uses a fixed number of tokens per call
+type fixedProvider struct {
+	name  string
+	err   error // returned by every call if set
+	usage usageCounter
+}
+
+func (p *fixedProvider) Name() string                   { return p.name }
+func (p *fixedProvider) IsAvailable() bool              { return true }
+func (p *fixedProvider) Ping(ctx context.Context) error { return nil }
+func (p *fixedProvider) EstimateCost(usage TokenUsage) float64 {
+	return float64(usage.InputTokens)*0.001 + float64(usage.OutputTokens)*0.005
+}
+func (p *fixedProvider) GetUsage() TokenUsage { return p.usage.get() }
+
+func (p *fixedProvider) Analyze(ctx context.Context, req AnalysisRequest) (*AnalysisResult, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	p.usage.add(ctx, 100, 20)
+	return &AnalysisResult{Overview: req.ComponentName}, nil
+}
+
+func (p *fixedProvider) Generate(ctx context.Context, req GenerateRequest) (string, error) {
+	if p.err != nil {
+		return "", p.err
+	}
+	p.usage.add(ctx, 10, 5)
+	return req.ComponentName, nil
+}
+
+func TestPoolConcurrentUsage(t *testing.T) {
+	const components, callsEach = 20, 25
+
+	provider := &fixedProvider{name: "fixed"}
+	pool := NewPool(provider, 4)
+
+	recorders := make([]*UsageRecorder, components)
+	var wg sync.WaitGroup
+	for i := range recorders {
+		recorders[i] = &UsageRecorder{}
+		ctx := WithUsageRecorder(context.Background(), recorders[i])
+		name := fmt.Sprintf("component-%d", i)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := pool.Analyze(ctx, AnalysisRequest{ComponentName: name}); err != nil {
+				t.Error(err)
+			}
+			for j := 1; j < callsEach; j++ {
+				if _, err := pool.Generate(ctx, GenerateRequest{ComponentName: name}); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	perComponent := 120 + (callsEach-1)*15
+	wantTokens := components * perComponent
+	wantInput, wantOutput := components*(100+(callsEach-1)*10), components*(20+(callsEach-1)*5)
+
+	if got := pool.GetTotalTokens(); got != wantTokens {
+		t.Errorf("GetTotalTokens = %d, want %d", got, wantTokens)
+	}
+	if got, want := pool.GetTotalCost(), float64(wantInput)*0.001+float64(wantOutput)*0.005; math.Abs(got-want) > 1e-9 {
+		t.Errorf("GetTotalCost = %f, want %f", got, want)
+	}
+
+	usage := pool.Usage()
+	if len(usage) != 1 || usage[0].Requests != components*callsEach || usage[0].Tokens != wantTokens {
+		t.Errorf("Usage = %+v, want %d requests and %d tokens", usage, components*callsEach, wantTokens)
+	}
+
+	for i, recorder := range recorders {
+		usage := recorder.Usage()
+		if usage.InputTokens+usage.OutputTokens != perComponent {
+			t.Errorf("component-%d recorded %+v, want %d tokens", i, usage, perComponent)
+		}
+	}
+
+	if active := pool.Active(); len(active) != 0 {
+		t.Errorf("Active = %v after all calls finished", active)
+	}
+}
+
+func TestPoolFallbackUsage(t *testing.T) {
+	primary := &fixedProvider{name: "primary", err: &APIError{StatusCode: 529, Body: "overloaded"}}
+	fallback := &fixedProvider{name: "fallback"}
+
+	pool := NewPool(primary, 2)
+	pool.SetFallbacks(fallback)
+
+	for i := 0; i < 3; i++ {
+		if _, err := pool.Generate(context.Background(), GenerateRequest{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	usage := pool.Usage()
+	if len(usage) != 1 || usage[0].Provider != "fallback" || usage[0].Requests != 3 || usage[0].Tokens != 45 {
+		t.Errorf("Usage = %+v, want all 3 requests served by the fallback", usage)
+	}
+	if pool.GetProvider() != Provider(fallback) {
+		t.Errorf("calls still go to %s", pool.GetProvider().Name())
+	}
+}
@@ -0,0 +1,154 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/docbrown/cli/internal/logging"
+)
+
+// RetryPolicy controls how transient LLM API errors are retried
+type RetryPolicy struct {
+	MaxRetries int
+	Backoff    time.Duration // base delay, doubled on each attempt
+}
+
+// DefaultRetryPolicy returns the retry policy used when none is configured
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries: 3,
+		Backoff:    1 * time.Second,
+	}
+}
+
+// APIError is returned when an LLM API responds with a non-success status
+type APIError struct {
+	StatusCode int
+	Body       string
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API error (status %d): %s", e.StatusCode, e.Body)
+}
+
+// newAPIError builds an APIError from a response, honoring Retry-After
+func newAPIError(resp *http.Response, body []byte) *APIError {
+	return &APIError{
+		StatusCode: resp.StatusCode,
+		Body:       string(body),
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+	}
+}
+
+// parseRetryAfter parses a Retry-After header (seconds or HTTP date)
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if when, err := http.ParseTime(value); err == nil {
+		if d := time.Until(when); d > 0 {
+			return d
+		}
+	}
+
+	return 0
+}
+
+// isRetryable reports whether an error is transient and worth retrying
+func isRetryable(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			529: // Anthropic "overloaded"
+			return true
+		}
+		return false
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return false
+}
+
+// isUnavailable reports whether an error means the provider can't serve
+// requests at the moment, rather than rejecting this one, so another
+// provider may succeed: transient errors that outlasted the retries, and
+// failures to reach the provider at all
+func isUnavailable(err error) bool {
+	if isRetryable(err) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// withRetry runs fn, retrying transient failures with exponential backoff.
+// It stops as soon as the context is done or its deadline would be exceeded.
+func withRetry(ctx context.Context, policy RetryPolicy, fn func() error) error {
+	backoff := policy.Backoff
+	if backoff <= 0 {
+		backoff = time.Second
+	}
+
+	var err error
+	for attempt := 0; ; attempt++ {
+		err = fn()
+		if err == nil {
+			return nil
+		}
+
+		// Never retry once the caller has given up
+		if ctx.Err() != nil {
+			return err
+		}
+
+		if attempt >= policy.MaxRetries || !isRetryable(err) {
+			return err
+		}
+
+		wait := backoff << attempt
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+			wait = apiErr.RetryAfter
+		}
+
+		// Don't start a wait that would outlive the context deadline
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			return err
+		}
+
+		reason := "timeout"
+		if apiErr != nil {
+			reason = fmt.Sprintf("status %d", apiErr.StatusCode)
+		}
+		logging.Warnf("  ⚠ LLM request failed (%s), retrying in %s (attempt %d/%d)",
+			reason, wait, attempt+1, policy.MaxRetries)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package llm
+
+// SamplingParams controls how deterministic generated text is. A zero TopP
+// or Seed leaves the provider's own default in place.
+type SamplingParams struct {
+	Temperature float64
+	TopP        float64
+	Seed        int
+}
+
+// DefaultSamplingParams returns a low temperature so repeated runs produce
+// similar documentation
+func DefaultSamplingParams() SamplingParams {
+	return SamplingParams{Temperature: 0.2}
+}
+
+// DeterministicSeed is the seed used by --deterministic when llm.seed is unset
+const DeterministicSeed = 42
+
+// anthropicMaxTemperature is the top of Anthropic's temperature range (0-1);
+// Ollama and OpenAI accept up to 2
+const anthropicMaxTemperature = 1.0
@@ -0,0 +1,265 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Separator is the horizontal rule framing each stage of console output
+const Separator = "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━"
+
+var (
+	mu         sync.RWMutex
+	minLevel   = slog.LevelInfo
+	structured bool
+	output     io.Writer = os.Stdout
+
+	// writeMu serializes console writes so the status line can be cleared
+	// and redrawn around each one
+	writeMu sync.Mutex
+	status  string
+)
+
+func init() {
+	slog.SetDefault(slog.New(&consoleHandler{}))
+}
+
+// Setup configures the default logger. Console output keeps the friendly,
+// emoji-prefixed lines; jsonLogs switches to one JSON object per record for
+// CI. quiet is shorthand for the error level.
+func Setup(level string, quiet, jsonLogs bool) error {
+	lvl, err := ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	if quiet {
+		lvl = slog.LevelError
+	}
+
+	mu.Lock()
+	minLevel = lvl
+	structured = jsonLogs
+	mu.Unlock()
+
+	if jsonLogs {
+		slog.SetDefault(slog.New(slog.NewJSONHandler(writer{}, &slog.HandlerOptions{Level: lvl})))
+	} else {
+		slog.SetDefault(slog.New(&consoleHandler{}))
+	}
+
+	return nil
+}
+
+// SetOutput sets where logs and console output are written (stdout by
+// default). Commands whose stdout carries a result, such as --json, send
+// their progress to stderr.
+func SetOutput(w io.Writer) {
+	mu.Lock()
+	defer mu.Unlock()
+	output = w
+}
+
+// Output returns the writer logs are written to, for prompts that belong
+// with the progress output
+func Output() io.Writer {
+	return writer{}
+}
+
+// ParseLevel converts a level name (debug, info, warn, error) to a slog level
+func ParseLevel(name string) (slog.Level, error) {
+	switch strings.ToLower(name) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return 0, fmt.Errorf("invalid log level: %s (must be one of: debug, info, warn, error)", name)
+	}
+}
+
+// Structured reports whether logs are being written as JSON
+func Structured() bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return structured
+}
+
+// Debugf logs a formatted message at debug level
+func Debugf(format string, args ...any) {
+	logf(slog.LevelDebug, format, args...)
+}
+
+// Infof logs a formatted message at info level
+func Infof(format string, args ...any) {
+	logf(slog.LevelInfo, format, args...)
+}
+
+// Warnf logs a formatted message at warn level
+func Warnf(format string, args ...any) {
+	logf(slog.LevelWarn, format, args...)
+}
+
+// Errorf logs a formatted message at error level
+func Errorf(format string, args ...any) {
+	logf(slog.LevelError, format, args...)
+}
+
+// Blank prints an empty line between console sections. It has no
+// structured equivalent and is dropped from JSON logs.
+func Blank() {
+	console("")
+}
+
+// Rule prints the separator line between console sections
+func Rule() {
+	console(Separator)
+}
+
+// logf formats and logs a message. Structured messages are trimmed of the
+// indentation and leading emoji that only decorate console output.
+func logf(level slog.Level, format string, args ...any) {
+	logger := slog.Default()
+	if !logger.Enabled(context.Background(), level) {
+		return
+	}
+
+	msg := fmt.Sprintf(format, args...)
+	if Structured() {
+		msg = strings.TrimSpace(strings.TrimLeftFunc(msg, isDecoration))
+	}
+	logger.Log(context.Background(), level, msg)
+}
+
+// isDecoration reports whether r is whitespace or a pictographic symbol
+// (including the emoji variation selector)
+func isDecoration(r rune) bool {
+	return unicode.IsSpace(r) || unicode.Is(unicode.So, r) || r == '\uFE0F'
+}
+
+// console writes a decorative line when console output is at info level or below
+func console(line string) {
+	mu.RLock()
+	show := !structured && minLevel <= slog.LevelInfo
+	mu.RUnlock()
+
+	if show {
+		writeConsole(line + "\n")
+	}
+}
+
+// StatusSupported reports whether an in-place status line can be shown:
+// console output at info level or below, going to a terminal
+func StatusSupported() bool {
+	mu.RLock()
+	show := !structured && minLevel <= slog.LevelInfo
+	mu.RUnlock()
+
+	if !show {
+		return false
+	}
+	mu.RLock()
+	f, ok := output.(*os.File)
+	mu.RUnlock()
+
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// SetStatus shows line beneath the console output, redrawing it in place as
+// other lines are logged. Callers should check StatusSupported first.
+func SetStatus(line string) {
+	writeMu.Lock()
+	defer writeMu.Unlock()
+
+	status = line
+	io.WriteString(writer{}, clearLine+status)
+}
+
+// ClearStatus removes the status line
+func ClearStatus() {
+	writeMu.Lock()
+	defer writeMu.Unlock()
+
+	if status != "" {
+		io.WriteString(writer{}, clearLine)
+		status = ""
+	}
+}
+
+// clearLine returns the cursor to the start of the line and erases it
+const clearLine = "\r\033[K"
+
+// writeConsole writes text, keeping any status line below it
+func writeConsole(text string) error {
+	writeMu.Lock()
+	defer writeMu.Unlock()
+
+	if status != "" {
+		text = clearLine + text + status
+	}
+	_, err := io.WriteString(writer{}, text)
+	return err
+}
+
+// writer writes to the output set at the time of the write, so handlers
+// created before SetOutput still honour it
+type writer struct{}
+
+func (writer) Write(p []byte) (int, error) {
+	mu.RLock()
+	w := output
+	mu.RUnlock()
+	return w.Write(p)
+}
+
+var _ io.Writer = writer{}
+
+// consoleHandler prints each record's message as-is, followed by any
+// attributes as key=value pairs
+type consoleHandler struct {
+	attrs []slog.Attr
+}
+
+func (h *consoleHandler) Enabled(_ context.Context, level slog.Level) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return level >= minLevel
+}
+
+func (h *consoleHandler) Handle(_ context.Context, r slog.Record) error {
+	var sb strings.Builder
+	sb.WriteString(r.Message)
+
+	writeAttr := func(a slog.Attr) bool {
+		fmt.Fprintf(&sb, " %s=%v", a.Key, a.Value)
+		return true
+	}
+	for _, a := range h.attrs {
+		writeAttr(a)
+	}
+	r.Attrs(writeAttr)
+
+	sb.WriteString("\n")
+	return writeConsole(sb.String())
+}
+
+func (h *consoleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &consoleHandler{attrs: append(append([]slog.Attr{}, h.attrs...), attrs...)}
+}
+
+func (h *consoleHandler) WithGroup(string) slog.Handler {
+	return h
+}
@@ -0,0 +1,122 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/docbrown/cli/internal/httpclient"
+)
+
+// Events reported to the webhook
+const (
+	EventGenerated = "docs_generated"
+	EventPRCreated = "pr_created"
+	EventPRUpdated = "pr_updated"
+)
+
+// defaultTimeout bounds a webhook call when none is configured
+const defaultTimeout = 10 * time.Second
+
+// Summary is the outcome of a run, posted as JSON by the generic format
+type Summary struct {
+	Event        string  `json:"event"`
+	Repo         string  `json:"repo"`
+	Components   int     `json:"components"`
+	QualityScore float64 `json:"quality_score"`
+	PRURL        string  `json:"pr_url,omitempty"`
+	Provider     string  `json:"provider,omitempty"`
+	Cost         float64 `json:"cost"`
+}
+
+// Notifier posts run summaries to a webhook
+type Notifier struct {
+	url     string
+	format  string // generic or slack
+	timeout time.Duration
+}
+
+// NewNotifier creates a notifier for url. It returns nil when url is empty,
+// and Notify on a nil notifier does nothing.
+func NewNotifier(url, format string, timeout time.Duration) *Notifier {
+	if url == "" {
+		return nil
+	}
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	return &Notifier{
+		url:     url,
+		format:  format,
+		timeout: timeout,
+	}
+}
+
+// Notify posts summary to the webhook
+func (n *Notifier) Notify(ctx context.Context, summary Summary) error {
+	if n == nil {
+		return nil
+	}
+
+	var payload interface{} = summary
+	if n.format == "slack" {
+		payload = map[string]string{"text": slackText(summary)}
+	}
+
+	bodyBytes, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, n.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", n.url, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpclient.New(0).Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("webhook returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return nil
+}
+
+// slackText formats a summary as a Slack message (mrkdwn)
+func slackText(summary Summary) string {
+	var b strings.Builder
+
+	switch summary.Event {
+	case EventPRCreated:
+		fmt.Fprintf(&b, ":memo: Documentation PR opened for *%s*", summary.Repo)
+	case EventPRUpdated:
+		fmt.Fprintf(&b, ":memo: Documentation PR updated for *%s*", summary.Repo)
+	default:
+		fmt.Fprintf(&b, ":books: Documentation generated for *%s*", summary.Repo)
+	}
+
+	fmt.Fprintf(&b, "\n• Components: %d\n• Quality score: %.1f/10.0", summary.Components, summary.QualityScore)
+	if summary.Provider != "" {
+		fmt.Fprintf(&b, "\n• Provider: %s (cost $%.2f)", summary.Provider, summary.Cost)
+	}
+	if summary.PRURL != "" {
+		fmt.Fprintf(&b, "\n• Pull request: <%s>", summary.PRURL)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,127 @@
+package orchestrator
+
+import (
+	"cmp"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/docbrown/cli/internal/git"
+	"github.com/docbrown/cli/internal/logging"
+	"github.com/docbrown/cli/internal/template"
+)
+
+// timestamp returns the generation time shown in the docs. Deterministic
+// runs use SOURCE_DATE_EPOCH or the HEAD commit time, so an unchanged
+// repository renders the same date every time.
+func (o *Orchestrator) timestamp(gitOps *git.Operations) time.Time {
+	if !o.config.Documentation.Deterministic {
+		return time.Now()
+	}
+
+	if epoch := os.Getenv("SOURCE_DATE_EPOCH"); epoch != "" {
+		if seconds, err := strconv.ParseInt(epoch, 10, 64); err == nil {
+			return time.Unix(seconds, 0).UTC()
+		}
+		logging.Warnf("⚠ Ignoring invalid SOURCE_DATE_EPOCH %q", epoch)
+	}
+
+	if gitOps != nil {
+		if when, err := gitOps.HeadCommitTime(); err == nil {
+			return when.UTC()
+		}
+	}
+
+	return time.Unix(0, 0).UTC()
+}
+
+// sortTemplateData orders every collection the templates range over, so the
+// rendered output doesn't depend on detection or discovery order. Slices are
+// copied before sorting because some are shared with the analyzer's results.
+func sortTemplateData(data *template.TemplateData) {
+	data.Components = slices.Clone(data.Components)
+	for i := range data.Components {
+		comp := &data.Components[i]
+		comp.APIs = sortedAPIs(comp.APIs)
+		comp.Functions = sortedFunctions(comp.Functions)
+		comp.Dependencies = sortedDependencies(comp.Dependencies)
+		comp.DirectDependencies = sortedDependencies(comp.DirectDependencies)
+		comp.TransitiveDependencies = sortedDependencies(comp.TransitiveDependencies)
+		comp.DevDependencies = sortedDependencies(comp.DevDependencies)
+		comp.Ports = sortedCopy(comp.Ports)
+	}
+	slices.SortStableFunc(data.Components, func(a, b template.ComponentData) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	data.Services = slices.Clone(data.Services)
+	for i := range data.Services {
+		svc := &data.Services[i]
+		svc.Endpoints = sortedAPIs(svc.Endpoints)
+		svc.Dependencies = sortedDependencies(svc.Dependencies)
+		svc.Ports = sortedCopy(svc.Ports)
+		if len(svc.Ports) > 0 {
+			svc.Port = svc.Ports[0]
+		}
+	}
+	slices.SortStableFunc(data.Services, func(a, b template.ServiceData) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	data.Libraries = slices.Clone(data.Libraries)
+	for i := range data.Libraries {
+		data.Libraries[i].Functions = sortedFunctions(data.Libraries[i].Functions)
+	}
+	slices.SortStableFunc(data.Libraries, func(a, b template.LibraryData) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	data.Frontends = slices.Clone(data.Frontends)
+	slices.SortStableFunc(data.Frontends, func(a, b template.FrontendData) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	data.APIs = slices.Clone(data.APIs)
+	slices.SortStableFunc(data.APIs, func(a, b template.APIEntityData) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	data.Architecture.Components = sortedCopy(data.Architecture.Components)
+	data.Architecture.Patterns = sortedCopy(data.Architecture.Patterns)
+	// Technologies are already in a stable order, by prevalence
+}
+
+// sortedAPIs returns endpoints ordered by path, then method
+func sortedAPIs(apis []template.APIData) []template.APIData {
+	sorted := slices.Clone(apis)
+	slices.SortStableFunc(sorted, func(a, b template.APIData) int {
+		return cmp.Or(cmp.Compare(a.Path, b.Path), cmp.Compare(a.Method, b.Method))
+	})
+	return sorted
+}
+
+// sortedFunctions returns functions ordered by name
+func sortedFunctions(functions []template.FunctionData) []template.FunctionData {
+	sorted := slices.Clone(functions)
+	slices.SortStableFunc(sorted, func(a, b template.FunctionData) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return sorted
+}
+
+// sortedDependencies returns dependencies ordered by name
+func sortedDependencies(deps []template.DependencyData) []template.DependencyData {
+	sorted := slices.Clone(deps)
+	slices.SortStableFunc(sorted, func(a, b template.DependencyData) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return sorted
+}
+
+// sortedCopy returns a sorted copy of a slice
+func sortedCopy[T cmp.Ordered](values []T) []T {
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	return sorted
+}
@@ -0,0 +1,110 @@
+package orchestrator
+
+import (
+	"bytes"
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docbrown/cli/internal/config"
+)
+
+// writeRepo creates files, given as slash-separated paths and contents,
+// under dir
+func writeRepo(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// readTree returns the contents of every file under dir by relative path
+func readTree(t *testing.T, dir string) map[string][]byte {
+	t.Helper()
+
+	tree := make(map[string][]byte)
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, path)
+		tree[filepath.ToSlash(rel)] = content
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tree
+}
+
+// mockConfig returns a config generating docs offline with the mock provider
+func mockConfig() *config.Config {
+	cfg := config.DefaultConfig()
+	cfg.LLM.Provider = "mock"
+	cfg.Documentation.Deterministic = true
+	return cfg
+}
+
+// testRepo is a small monorepo with services in two languages
+var testRepo = map[string]string{
+	"README.md":                    "# shop\n",
+	"services/orders/go.mod":       "module example.com/orders\n\ngo 1.22\n\nrequire github.com/google/uuid v1.6.0\n",
+	"services/orders/main.go":      "package main\n\nimport (\n\t\"net/http\"\n\t\"os\"\n)\n\nfunc main() {\n\thttp.HandleFunc(\"/orders\", nil)\n\thttp.HandleFunc(\"/health\", nil)\n\thttp.ListenAndServe(\":\"+os.Getenv(\"PORT\"), nil)\n}\n",
+	"services/orders/Dockerfile":   "FROM golang:1.22\nEXPOSE 8080\n",
+	"services/payments/go.mod":     "module example.com/payments\n\ngo 1.22\n",
+	"services/payments/main.go":    "package main\n\nimport \"net/http\"\n\nfunc main() {\n\thttp.HandleFunc(\"/refunds\", nil)\n\thttp.HandleFunc(\"/charges\", nil)\n}\n",
+	"services/web/package.json":    `{"name": "web", "dependencies": {"react": "^18.2.0", "express": "^4.18.0"}}`,
+	"services/web/index.js":        "const express = require('express')\nconst app = express()\napp.get('/', (req, res) => res.send('ok'))\n",
+	"services/web/src/api/user.js": "export function getUser(id) { return fetch(`/users/${id}`) }\n",
+}
+
+func TestDeterministicGenerate(t *testing.T) {
+	repo := t.TempDir()
+	writeRepo(t, repo, testRepo)
+	t.Chdir(repo)
+	t.Setenv("HOME", t.TempDir()) // no user templates
+	t.Setenv("SOURCE_DATE_EPOCH", "1700000000")
+
+	var runs []map[string][]byte
+	for i := 0; i < 2; i++ {
+		cfg := mockConfig()
+		o, err := NewOrchestrator(cfg)
+		if err != nil {
+			t.Fatalf("NewOrchestrator: %v", err)
+		}
+		if _, err := o.ExecuteGenerate(context.Background(), GenerateOptions{}); err != nil {
+			t.Fatalf("run %d: %v", i+1, err)
+		}
+
+		runs = append(runs, readTree(t, cfg.Documentation.OutputDir))
+		if err := os.RemoveAll(cfg.Documentation.OutputDir); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	first, second := runs[0], runs[1]
+	if len(first) == 0 {
+		t.Fatal("nothing was generated")
+	}
+	if len(first) != len(second) {
+		t.Fatalf("first run wrote %d files, second %d", len(first), len(second))
+	}
+	for name, content := range first {
+		if !bytes.Equal(content, second[name]) {
+			t.Errorf("%s differs between runs:\n--- first\n%s\n--- second\n%s", name, content, second[name])
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/docbrown/cli/internal/analyzer"
+	"github.com/docbrown/cli/internal/llm"
+	"github.com/docbrown/cli/internal/logging"
+)
+
+// ExecuteExplain documents a single file or directory with the generate
+// prompt and returns the markdown. Nothing is cached or written.
+func (o *Orchestrator) ExecuteExplain(ctx context.Context, path string) (string, error) {
+	path = filepath.Clean(path)
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+
+	name := filepath.Base(path)
+	if abs, err := filepath.Abs(path); err == nil {
+		name = filepath.Base(abs)
+	}
+
+	comp := analyzer.Component{
+		Name: name,
+		Type: "file",
+		Path: path,
+	}
+
+	if info.IsDir() {
+		comp.Type = "directory"
+		comp.Files, comp.Language, err = o.explainFiles(path)
+		if err != nil {
+			return "", err
+		}
+	} else {
+		if o.isSensitive(path) {
+			return "", fmt.Errorf("refusing to send sensitive file %s", path)
+		}
+		comp.Files = []string{path}
+		comp.Language = analyzer.DetectLanguage(path)
+	}
+
+	selection := o.selectKeyFiles(comp)
+	if len(selection.Files) == 0 {
+		return "", fmt.Errorf("no readable files in %s", path)
+	}
+	if selection.Skipped > 0 {
+		logging.Warnf("⚠ Skipped %d files over the context token budget", selection.Skipped)
+	}
+	if selection.Sensitive > 0 {
+		logging.Infof("🔒 Skipped %d sensitive files", selection.Sensitive)
+	}
+	if selection.Redacted > 0 {
+		logging.Infof("🔒 Redacted %d secrets", selection.Redacted)
+	}
+
+	o.llmPool.Warmup(ctx)
+	logging.Infof("🤖 Explaining %s (%d files) with %s...", path, len(selection.Files), o.llmPool.GetProvider().Name())
+
+	return o.llmPool.Generate(ctx, llm.GenerateRequest{
+		ComponentName: comp.Name,
+		ComponentType: comp.Type,
+		Language:      comp.Language,
+		Path:          comp.Path,
+		Files:         selection.Files,
+	})
+}
+
+// explainFiles lists the files under dir that a repository scan would
+// include, and their most common language
+func (o *Orchestrator) explainFiles(dir string) ([]string, string, error) {
+	scanner := analyzer.NewScanner(dir, o.config.Documentation.IncludePatterns, o.config.Documentation.ExcludePatterns)
+	scanner.SetRespectGitignore(o.config.Documentation.RespectGitignore)
+	scanner.SetSensitivePatterns(o.config.Documentation.ExcludeSensitive)
+	scanner.SetGeneratedPatterns(o.config.Documentation.ExcludeGenerated, o.config.Documentation.DetectGenerated)
+
+	infos, err := scanner.ListFiles()
+	if err != nil {
+		return nil, "", err
+	}
+
+	files := make([]string, 0, len(infos))
+	counts := make(map[string]int)
+	language := ""
+	for _, info := range infos {
+		files = append(files, filepath.Join(dir, info.Path))
+		if info.Language == "" {
+			continue
+		}
+		counts[info.Language]++
+		if counts[info.Language] > counts[language] || (counts[info.Language] == counts[language] && info.Language < language) {
+			language = info.Language
+		}
+	}
+
+	return files, language, nil
+}
@@ -0,0 +1,79 @@
+package orchestrator
+
+import (
+	"encoding/json"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/docbrown/cli/internal/llm"
+	"github.com/docbrown/cli/internal/logging"
+	"github.com/docbrown/cli/internal/validator"
+)
+
+// ComponentMetrics records what documenting one component cost
+type ComponentMetrics struct {
+	Name         string        `json:"name"`
+	Files        int           `json:"files"`     // files in the component
+	KeyFiles     int           `json:"key_files"` // files sent to the LLM
+	InputTokens  int           `json:"input_tokens"`
+	OutputTokens int           `json:"output_tokens"`
+	Duration     time.Duration `json:"-"`
+	Cached       bool          `json:"cached"`
+}
+
+// MarshalJSON reports the duration in seconds rather than nanoseconds
+func (m ComponentMetrics) MarshalJSON() ([]byte, error) {
+	type metrics ComponentMetrics
+	return json.Marshal(struct {
+		metrics
+		DurationSeconds float64 `json:"duration_seconds"`
+	}{metrics(m), m.Duration.Seconds()})
+}
+
+// RunSummary is the outcome of a complete documentation run
+type RunSummary struct {
+	Provider        string                       `json:"provider"`
+	Components      int                          `json:"components"`
+	QualityScore    float64                      `json:"quality_score"`
+	DurationSeconds float64                      `json:"duration_seconds"`
+	Tokens          int                          `json:"tokens"`
+	Cost            float64                      `json:"cost"`
+	Providers       []llm.ProviderUsage          `json:"providers,omitempty"` // what each provider served, in fallback order
+	Metrics         []ComponentMetrics           `json:"component_metrics"`
+	Validation      *validator.ValidationResults `json:"validation,omitempty"`
+}
+
+// printMetricsTable prints per-component usage, most expensive first, so
+// components worth excluding stand out. JSON logs get one record per component.
+func printMetricsTable(metrics []ComponentMetrics) {
+	sorted := make([]ComponentMetrics, len(metrics))
+	copy(sorted, metrics)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].InputTokens+sorted[i].OutputTokens > sorted[j].InputTokens+sorted[j].OutputTokens
+	})
+
+	if logging.Structured() {
+		for _, m := range sorted {
+			slog.Info("component usage",
+				"component", m.Name,
+				"files", m.Files,
+				"key_files", m.KeyFiles,
+				"input_tokens", m.InputTokens,
+				"output_tokens", m.OutputTokens,
+				"duration_seconds", m.Duration.Seconds(),
+				"cached", m.Cached)
+		}
+		return
+	}
+
+	logging.Infof("%-30s %6s %6s %10s %10s %8s %s", "COMPONENT", "FILES", "SENT", "INPUT", "OUTPUT", "TIME", "CACHED")
+	for _, m := range sorted {
+		cached := "no"
+		if m.Cached {
+			cached = "yes"
+		}
+		logging.Infof("%-30s %6d %6d %10d %10d %8s %s", m.Name, m.Files, m.KeyFiles,
+			m.InputTokens, m.OutputTokens, m.Duration.Round(100*time.Millisecond), cached)
+	}
+}
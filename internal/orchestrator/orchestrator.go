@@ -1,49 +1,69 @@
 package orchestrator
 
 import (
+	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/docbrown/cli/internal/analyzer"
 	"github.com/docbrown/cli/internal/cache"
 	"github.com/docbrown/cli/internal/config"
+	"github.com/docbrown/cli/internal/git"
 	"github.com/docbrown/cli/internal/llm"
+	"github.com/docbrown/cli/internal/logging"
+	"github.com/docbrown/cli/internal/redact"
 	"github.com/docbrown/cli/internal/template"
 	"github.com/docbrown/cli/internal/validator"
 )
 
+// streamProgressInterval is how many streamed characters pass between progress lines
+const streamProgressInterval = 1000
+
 // Orchestrator coordinates the documentation generation workflow
 type Orchestrator struct {
-	config      *config.Config
-	analyzer    *analyzer.Analyzer
-	llmPool     *llm.Pool
-	templateEng *template.Engine
+	config       *config.Config
+	analyzer     *analyzer.Analyzer
+	llmPool      *llm.Pool
+	templateEng  *template.Engine
 	cacheManager *cache.Manager
+	redactor     *redact.Redactor // nil when redaction is disabled
+	metrics      []ComponentMetrics
+	structure    *analyzer.RepoStructure // from the last analysis
+	persistMu    sync.Mutex              // serializes cache updates as components finish
+
+	// selections memoizes selectKeyFiles by component until the next analysis
+	selectionMu sync.Mutex
+	selections  map[string]keyFileSelection
 }
 
 // NewOrchestrator creates a new orchestrator
 func NewOrchestrator(cfg *config.Config) (*Orchestrator, error) {
-	// Create LLM provider
-	provider, err := llm.NewProvider(cfg)
+	// Create LLM provider and its fallbacks
+	providers, err := llm.NewProviders(cfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create LLM provider: %w", err)
 	}
 
 	// Create LLM pool
-	llmPool := llm.NewPool(provider, cfg.Performance.MaxConcurrent)
+	llmPool := llm.NewPool(providers[0], cfg.Performance.MaxConcurrent)
+	llmPool.SetFallbacks(providers[1:]...)
 
 	// Create analyzer
-	analyzer := analyzer.NewAnalyzer(".", cfg.Documentation.ExcludePatterns)
+	analyzer := analyzer.NewAnalyzer(".", cfg.Documentation.IncludePatterns, cfg.Documentation.ExcludePatterns)
+	analyzer.SetRespectGitignore(cfg.Documentation.RespectGitignore)
+	analyzer.SetSensitivePatterns(cfg.Documentation.ExcludeSensitive)
+	analyzer.SetGeneratedPatterns(cfg.Documentation.ExcludeGenerated, cfg.Documentation.DetectGenerated)
+	analyzer.SetIncludeDevDependencies(cfg.Documentation.IncludeDevDependencies)
+	analyzer.SetExternalCommand(cfg.Analyzer.ExternalCommand, cfg.Analyzer.ExternalMode == "replace", cfg.Analyzer.ExternalTimeout)
 
 	// Create template engine
-	templatePath := cfg.Documentation.TemplatePath
-	if templatePath == "" {
-		templatePath = "templates"
-	}
-	templateEng := template.NewEngine(templatePath)
+	templateEng := template.NewEngine(cfg.Documentation.TemplateSearchPaths()...)
 
 	// Create cache manager
 	cacheManager := cache.NewManager(
@@ -52,259 +72,797 @@ func NewOrchestrator(cfg *config.Config) (*Orchestrator, error) {
 		cfg.Cache.TTL,
 	)
 
+	// Create secret redactor
+	var redactor *redact.Redactor
+	if cfg.Documentation.RedactSecrets {
+		redactor, err = redact.NewRedactor(cfg.Documentation.RedactPatterns)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &Orchestrator{
 		config:       cfg,
 		analyzer:     analyzer,
 		llmPool:      llmPool,
 		templateEng:  templateEng,
 		cacheManager: cacheManager,
+		redactor:     redactor,
 	}, nil
 }
 
+// SetTargets limits analysis and generation to the components under paths
+// or with the given names. Other components keep their cached docs.
+func (o *Orchestrator) SetTargets(paths, components []string) {
+	o.analyzer.SetTargets(paths, components)
+}
+
 // ExecuteAnalyze performs repository analysis
 func (o *Orchestrator) ExecuteAnalyze(ctx context.Context) (*analyzer.RepoStructure, error) {
-	fmt.Println("🔍 Analyzing repository...")
+	logging.Infof("🔍 Analyzing repository...")
 
 	structure, err := o.analyzer.Analyze()
 	if err != nil {
 		return nil, fmt.Errorf("analysis failed: %w", err)
 	}
 
-	fmt.Printf("✓ Analysis complete\n")
-	fmt.Printf("  - Files: %d\n", structure.TotalFiles)
-	fmt.Printf("  - Components: %d\n", len(structure.Components))
+	logging.Infof("✓ Analysis complete")
+	logging.Infof("  - Files: %d", structure.TotalFiles)
+	logging.Infof("  - Components: %d", len(structure.Components))
 
-	for lang, count := range structure.Languages {
-		fmt.Printf("  - %s: %d files\n", lang, count)
+	if structure.PrimaryLanguage != "" {
+		logging.Infof("  - Primary language: %s", structure.PrimaryLanguage)
+	}
+	for _, stat := range structure.LanguageStats {
+		logging.Infof("  - %s: %d files (%.1f%%, %.1f%% of bytes)", stat.Language, stat.Files, stat.Percent, stat.BytePercent)
 	}
 
+	o.structure = structure
+	o.selectionMu.Lock()
+	o.selections = make(map[string]keyFileSelection)
+	o.selectionMu.Unlock()
 	return structure, nil
 }
 
-// ExecuteGenerate performs documentation generation
-func (o *Orchestrator) ExecuteGenerate(ctx context.Context) error {
-	fmt.Println("🤖 Generating documentation...")
+// GenerateOptions controls how documentation generation runs
+type GenerateOptions struct {
+	// DryRun reports what would be generated without calling the LLM or writing files
+	DryRun bool
+
+	// Since limits generation to components with files changed since this
+	// git ref, instead of consulting the cache
+	Since string
+
+	// Resume skips components completed by an earlier (possibly interrupted)
+	// run. When false, cached progress is ignored and everything is regenerated.
+	Resume bool
+}
+
+// ExecuteGenerate performs documentation generation and returns the paths of
+// the files it wrote
+func (o *Orchestrator) ExecuteGenerate(ctx context.Context, opts GenerateOptions) ([]string, error) {
+	logging.Infof("🤖 Generating documentation...")
 
 	// Step 1: Analyze
 	structure, err := o.ExecuteAnalyze(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// Step 2: Load cache
+	// Step 2: Load cache. When starting over it isn't consulted for what to
+	// regenerate, but still supplies the overviews of untargeted components.
 	if err := o.cacheManager.Load(); err != nil {
-		fmt.Printf("⚠ Failed to load cache: %v\n", err)
+		logging.Warnf("⚠ Failed to load cache: %v", err)
 	}
 
 	// Step 3: Determine what needs to be regenerated
-	componentsToGen := o.getComponentsToGenerate(structure)
+	var changedFiles map[string]bool
+	if opts.Since != "" {
+		changedFiles, err = o.changedFilesSince(opts.Since)
+		if err != nil {
+			return nil, err
+		}
+	}
+	componentsToGen := structure.Components
+	if opts.Resume || changedFiles != nil {
+		componentsToGen = o.getComponentsToGenerate(structure, changedFiles)
+	}
+	o.metrics = skippedMetrics(structure.Components, componentsToGen)
 
 	if len(componentsToGen) == 0 {
-		fmt.Println("✓ All components up to date (using cache)")
-		return nil
+		if changedFiles != nil {
+			logging.Infof("✓ No components changed since %s", opts.Since)
+		} else {
+			logging.Infof("✓ All components up to date (using cache)")
+		}
+		return nil, nil
 	}
 
-	fmt.Printf("Generating %d components (skipping %d cached)\n",
+	skippedReason := "cached"
+	if changedFiles != nil {
+		skippedReason = "unchanged since " + opts.Since
+	}
+	logging.Infof("Generating %d components (skipping %d %s)",
 		len(componentsToGen),
-		len(structure.Components)-len(componentsToGen))
+		len(structure.Components)-len(componentsToGen),
+		skippedReason)
 
 	// Step 4: Load template
 	tmpl, err := o.templateEng.LoadTemplate(o.config.Documentation.Template)
 	if err != nil {
-		return fmt.Errorf("failed to load template: %w", err)
+		return nil, fmt.Errorf("failed to load template: %w", err)
+	}
+
+	if opts.DryRun {
+		o.reportDryRun(structure, componentsToGen)
+		return nil, nil
+	}
+
+	if err := o.checkCostLimit(structure, componentsToGen); err != nil {
+		return nil, err
+	}
+
+	// Fail before spending tokens if the docs can't be written
+	if err := ensureWritableDir(o.config.Documentation.OutputDir); err != nil {
+		return nil, err
 	}
 
 	// Step 5: Use LLM to generate content for each component
+	logging.Blank()
+	logging.Rule()
+	logging.Infof("🤖 Calling LLM to generate content for %d components (up to %d at a time)...",
+		len(componentsToGen), o.llmPool.MaxConcurrent())
+	logging.Rule()
+
+	enrichedComponents, metrics, err := o.generateWithLLM(ctx, structure, componentsToGen, tmpl)
+	if err != nil {
+		return nil, fmt.Errorf("LLM generation failed: %w", err)
+	}
+	o.metrics = append(metrics, o.metrics...)
+
+	// On cancellation, write out and cache the components that finished so
+	// the next run picks up where this one stopped
+	cancelled := ctx.Err()
+	if cancelled != nil {
+		enrichedComponents, componentsToGen = completedComponents(enrichedComponents)
+		logging.Blank()
+		logging.Warnf("⚠ Generation cancelled: saving %d completed components", len(componentsToGen))
+		if len(componentsToGen) == 0 {
+			return nil, cancelled
+		}
+	} else {
+		logging.Blank()
+		logging.Rule()
+		logging.Infof("✅ LLM content generation complete")
+		logging.Rule()
+	}
+
+	// Step 6: Render the regenerated components' pages. They were written as
+	// each component finished (see persistComponent), as was the cache.
+	outputDir := o.config.Documentation.OutputDir
+	generatedFiles, err := o.templateEng.RenderComponents(tmpl, o.buildTemplateData(structure, enrichedComponents), outputDir)
+	if err != nil {
+		return nil, fmt.Errorf("template rendering failed: %w", err)
+	}
+
+	// Step 7: Render the repository pages (index, architecture, catalog) from
+	// every component, so partial runs don't drop the ones left alone
+	repoData := o.buildTemplateData(structure, o.allEnriched(structure, enrichedComponents))
+	repoFiles, err := o.templateEng.RenderRepository(tmpl, repoData, outputDir)
+	generatedFiles = append(generatedFiles, repoFiles...)
+	if err != nil {
+		return nil, fmt.Errorf("template rendering failed: %w", err)
+	}
+
+	// Record what was written so 'docbrown pr' stages exactly these files
+	componentNames := make([]string, len(enrichedComponents))
+	for i, ec := range enrichedComponents {
+		componentNames[i] = ec.Component.Name
+	}
+	if err := cache.SaveManifest(o.manifestPath(), generatedFiles, componentNames); err != nil {
+		logging.Warnf("⚠ Failed to save generated file list: %v", err)
+	}
+
+	logging.Blank()
+	logging.Infof("✓ Generated %d files", len(generatedFiles))
+	for _, file := range generatedFiles {
+		logging.Infof("  - %s", file)
+	}
+
+	if cancelled != nil {
+		return generatedFiles, cancelled
+	}
+
+	return generatedFiles, nil
+}
+
+// completedComponents drops components whose processing was cancelled,
+// returning the rest alongside their analyzer components
+func completedComponents(enriched []EnrichedComponent) ([]EnrichedComponent, []analyzer.Component) {
+	var done []EnrichedComponent
+	var components []analyzer.Component
+	for _, ec := range enriched {
+		if !ec.Incomplete {
+			done = append(done, ec)
+			components = append(components, ec.Component)
+		}
+	}
+	return done, components
+}
+
+// allEnriched returns every component in analysis order, taking the
+// regenerated ones from enriched and the rest's overviews from the cache
+func (o *Orchestrator) allEnriched(structure *analyzer.RepoStructure, enriched []EnrichedComponent) []EnrichedComponent {
+	regenerated := make(map[string]EnrichedComponent, len(enriched))
+	for _, ec := range enriched {
+		regenerated[ec.Component.Name] = ec
+	}
+
+	all := make([]EnrichedComponent, len(structure.AllComponents))
+	for i, comp := range structure.AllComponents {
+		if ec, ok := regenerated[comp.Name]; ok {
+			all[i] = ec
+			continue
+		}
+
+		overview, ok := o.cacheManager.GetOverview(comp.Name)
+		if !ok {
+			overview = "Documentation for " + comp.Name
+		}
+		all[i] = EnrichedComponent{Component: comp, Overview: overview}
+	}
+	return all
+}
+
+// ComponentMetrics returns per-component usage from the last generation run
+func (o *Orchestrator) ComponentMetrics() []ComponentMetrics {
+	return o.metrics
+}
+
+// skippedMetrics records the components that generation will not touch
+func skippedMetrics(all, toGenerate []analyzer.Component) []ComponentMetrics {
+	generating := make(map[string]bool, len(toGenerate))
+	for _, comp := range toGenerate {
+		generating[comp.Name] = true
+	}
+
+	var metrics []ComponentMetrics
+	for _, comp := range all {
+		if !generating[comp.Name] {
+			metrics = append(metrics, ComponentMetrics{
+				Name:   comp.Name,
+				Files:  len(comp.Files),
+				Cached: true,
+			})
+		}
+	}
+	return metrics
+}
+
+// manifestPath returns the path of the generated-files manifest
+func (o *Orchestrator) manifestPath() string {
+	return filepath.Join(o.config.Cache.Dir, cache.ManifestFile)
+}
+
+// reportDryRun prints what generation would do: the components to regenerate,
+// the key files sent for each, and an estimated token count and cost
+func (o *Orchestrator) reportDryRun(structure *analyzer.RepoStructure, components []analyzer.Component) {
+	provider := o.llmPool.GetProvider()
+
 	fmt.Println()
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-	fmt.Printf("🤖 Calling LLM to generate content for %d components...\n", len(componentsToGen))
+	fmt.Println("🔎 Dry run: no LLM calls will be made and no files written")
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 
-	enrichedComponents, err := o.generateWithLLM(ctx, structure, componentsToGen)
-	if err != nil {
-		return fmt.Errorf("LLM generation failed: %w", err)
+	var total llm.TokenUsage
+	for i, comp := range components {
+		selection := o.keyFiles(comp)
+		keyFiles := selection.Files
+		usage := estimateComponentUsage(structure, keyFiles)
+		tokens := usage.InputTokens + usage.OutputTokens
+		total.InputTokens += usage.InputTokens
+		total.OutputTokens += usage.OutputTokens
+
+		fmt.Printf("\n[%d/%d] %s (%s, %s)\n", i+1, len(components), comp.Name, comp.Type, comp.Language)
+		fmt.Printf("  📄 %d key files:\n", len(keyFiles))
+		for _, file := range keyFiles {
+			fmt.Printf("    - %s (~%d tokens)\n", file.Path, estimateTokens(file.Content))
+		}
+		if selection.Skipped > 0 {
+			fmt.Printf("  ⚠ %d files skipped (context budget)\n", selection.Skipped)
+		}
+		if selection.Sensitive > 0 {
+			fmt.Printf("  🔒 %d sensitive files skipped\n", selection.Sensitive)
+		}
+		if selection.Redacted > 0 {
+			fmt.Printf("  🔒 %d secrets redacted\n", selection.Redacted)
+		}
+		fmt.Printf("  Estimated tokens: ~%d\n", tokens)
 	}
 
 	fmt.Println()
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-	fmt.Println("✅ LLM content generation complete")
+	fmt.Println("Dry run summary:")
+	fmt.Printf("  Provider: %s\n", provider.Name())
+	fmt.Printf("  Components to generate: %d\n", len(components))
+	fmt.Printf("  Estimated tokens: ~%d\n", total.InputTokens+total.OutputTokens)
+	fmt.Printf("  Estimated cost: $%.2f\n", provider.EstimateCost(total))
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+}
 
-	// Step 6: Build template data with LLM-generated content
-	templateData := o.buildTemplateData(structure, enrichedComponents)
+// ExecuteCost estimates the tokens and cost of a generation run without
+// calling the LLM. Components the cache would skip are listed but not costed.
+func (o *Orchestrator) ExecuteCost(ctx context.Context) error {
+	logging.Infof("💰 Estimating generation cost...")
 
-	// Step 7: Render templates
-	generatedFiles, err := o.templateEng.RenderAll(tmpl, templateData, o.config.Documentation.OutputDir)
+	structure, err := o.ExecuteAnalyze(ctx)
 	if err != nil {
-		return fmt.Errorf("template rendering failed: %w", err)
+		return err
 	}
 
-	// Step 8: Update cache
-	for _, comp := range componentsToGen {
-		o.cacheManager.Update(comp.Name, comp.Files)
+	if err := o.cacheManager.Load(); err != nil {
+		logging.Warnf("⚠ Failed to load cache: %v", err)
 	}
 
-	if err := o.cacheManager.Save(); err != nil {
-		fmt.Printf("⚠ Failed to save cache: %v\n", err)
+	components := o.getComponentsToGenerate(structure, nil)
+	toGenerate := make(map[string]bool, len(components))
+	for _, comp := range components {
+		toGenerate[comp.Name] = true
 	}
 
+	provider := o.llmPool.GetProvider()
+
 	fmt.Println()
-	fmt.Printf("✓ Generated %d files\n", len(generatedFiles))
-	for _, file := range generatedFiles {
-		fmt.Printf("  - %s\n", file)
+	fmt.Printf("%-30s %10s %10s %10s\n", "COMPONENT", "INPUT", "OUTPUT", "COST")
+
+	var total llm.TokenUsage
+	for _, comp := range components {
+		usage := estimateComponentUsage(structure, o.keyFiles(comp).Files)
+		total.InputTokens += usage.InputTokens
+		total.OutputTokens += usage.OutputTokens
+
+		fmt.Printf("%-30s %10d %10d %10s\n", comp.Name, usage.InputTokens, usage.OutputTokens,
+			fmt.Sprintf("$%.2f", provider.EstimateCost(usage)))
+	}
+
+	var cached []string
+	for _, comp := range structure.Components {
+		if !toGenerate[comp.Name] {
+			cached = append(cached, comp.Name)
+		}
+	}
+
+	fmt.Println()
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	fmt.Println("Cost estimate:")
+	fmt.Printf("  Provider: %s\n", provider.Name())
+	fmt.Printf("  Components to generate: %d\n", len(components))
+	if len(cached) > 0 {
+		fmt.Printf("  Skipped (cached): %d (%s)\n", len(cached), strings.Join(cached, ", "))
+	}
+	fmt.Printf("  Estimated input tokens: ~%d\n", total.InputTokens)
+	fmt.Printf("  Estimated output tokens: ~%d\n", total.OutputTokens)
+
+	cost := provider.EstimateCost(total)
+	if cost == 0 {
+		fmt.Printf("  Estimated cost: $0.00 (%s is free)\n", provider.Name())
+	} else {
+		fmt.Printf("  Estimated cost: $%.2f\n", cost)
+	}
+	if limit := o.config.Performance.CostLimit; limit > 0 && cost > limit {
+		fmt.Printf("  ⚠ Exceeds the $%.2f cost limit\n", limit)
+	}
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+
+	return nil
+}
+
+// estimatedOutputTokens is a rough allowance for the LLM's responses per component
+const estimatedOutputTokens = 2000
+
+// estimateComponentUsage estimates the tokens used to document one component.
+// Input is the analysis prompt (file tree + key files) and the generation
+// prompt (key files); output is an allowance for the responses.
+func estimateComponentUsage(structure *analyzer.RepoStructure, keyFiles []llm.FileContent) llm.TokenUsage {
+	fileTokens := 0
+	for _, file := range keyFiles {
+		fileTokens += estimateTokens(file.Content)
+	}
+
+	return llm.TokenUsage{
+		InputTokens:  estimateTokens(structure.FileTree) + 2*fileTokens,
+		OutputTokens: estimatedOutputTokens,
+	}
+}
+
+// checkCostLimit estimates the cost of generating components and, if it
+// exceeds the configured cost limit, asks the user to confirm. Without an
+// interactive terminal generation is aborted instead.
+func (o *Orchestrator) checkCostLimit(structure *analyzer.RepoStructure, components []analyzer.Component) error {
+	provider := o.llmPool.GetProvider()
+
+	var total llm.TokenUsage
+	for _, comp := range components {
+		usage := estimateComponentUsage(structure, o.keyFiles(comp).Files)
+		total.InputTokens += usage.InputTokens
+		total.OutputTokens += usage.OutputTokens
+	}
+
+	cost := provider.EstimateCost(total)
+	if cost == 0 {
+		return nil // Free provider
+	}
+
+	logging.Infof("💰 Estimated cost: $%.2f (~%d tokens)", cost, total.InputTokens+total.OutputTokens)
+
+	limit := o.config.Performance.CostLimit
+	if limit <= 0 || cost <= limit {
+		return nil
+	}
+
+	logging.Warnf("⚠ Estimated cost exceeds the $%.2f cost limit", limit)
+
+	if !isInteractive() {
+		return fmt.Errorf("estimated cost $%.2f exceeds cost limit $%.2f (raise performance.cost_limit to continue)", cost, limit)
+	}
+
+	fmt.Fprint(logging.Output(), "Continue? [y/N]: ")
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	if answer != "y" && answer != "yes" {
+		return fmt.Errorf("generation cancelled: estimated cost $%.2f exceeds cost limit $%.2f", cost, limit)
 	}
 
 	return nil
 }
 
-// ExecuteAuto performs the complete workflow
-func (o *Orchestrator) ExecuteAuto(ctx context.Context) error {
+// isInteractive reports whether stdin is a terminal
+func isInteractive() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// ExecuteAuto performs the complete workflow and returns a summary of the run
+func (o *Orchestrator) ExecuteAuto(ctx context.Context, opts GenerateOptions) (*RunSummary, error) {
 	startTime := time.Now()
 
-	fmt.Println("DocBrown - Automated Documentation")
-	fmt.Println()
+	logging.Infof("DocBrown - Automated Documentation")
+	logging.Blank()
 
 	// Step 1: Analyze
-	fmt.Println("🔍 Step 1/4: Analyzing codebase...")
+	logging.Infof("🔍 Step 1/4: Analyzing codebase...")
 	structure, err := o.ExecuteAnalyze(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	fmt.Println()
+	logging.Blank()
 
 	// Step 2: Generate
-	fmt.Println("🤖 Step 2/4: Generating documentation...")
-	if err := o.ExecuteGenerate(ctx); err != nil {
-		return err
+	logging.Infof("🤖 Step 2/4: Generating documentation...")
+	if _, err := o.ExecuteGenerate(ctx, opts); err != nil {
+		return nil, err
 	}
-	fmt.Println()
+	logging.Blank()
 
 	// Step 3: Validate
-	fmt.Println("✅ Step 3/4: Validating quality...")
-	score, err := o.ExecuteValidate()
+	logging.Infof("✅ Step 3/4: Validating quality...")
+	results, err := o.ExecuteValidate()
 	if err != nil {
-		return err
+		return nil, err
 	}
-	fmt.Println()
+	score := results.QualityScore
+	logging.Blank()
 
 	// Step 4: Summary
-	fmt.Println("🎉 Step 4/4: Complete")
-	fmt.Println()
+	logging.Infof("🎉 Step 4/4: Complete")
+	logging.Blank()
 
 	duration := time.Since(startTime)
-	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-	fmt.Println("Summary:")
-	fmt.Printf("  Components processed: %d\n", len(structure.Components))
-	fmt.Printf("  Quality score: %.1f/10.0\n", score)
-	fmt.Printf("  Time: %s\n", duration.Round(time.Second))
+	provider := o.llmPool.GetProvider()
+	summary := &RunSummary{
+		Provider:        provider.Name(),
+		Components:      len(structure.Components),
+		QualityScore:    score,
+		DurationSeconds: duration.Seconds(),
+		Tokens:          o.llmPool.GetTotalTokens(),
+		Cost:            o.llmPool.GetTotalCost(),
+		Providers:       o.llmPool.Usage(),
+		Metrics:         o.metrics,
+		Validation:      results,
+	}
+
+	logging.Rule()
+	logging.Infof("Summary:")
+	logging.Infof("  Components processed: %d", summary.Components)
+	logging.Infof("  Quality score: %.1f/10.0", score)
+	logging.Infof("  Time: %s", duration.Round(time.Second))
+
+	logging.Infof("  Tokens: %d", summary.Tokens)
 
 	// Show cost if using paid provider
-	if o.llmPool.GetProvider().Name() == "anthropic" {
-		cost := o.llmPool.GetTotalCost()
-		fmt.Printf("  Cost: $%.2f\n", cost)
+	if provider.Name() != "ollama" || o.llmPool.HasFallbacks() {
+		logging.Infof("  Cost: $%.2f", summary.Cost)
 	} else {
-		fmt.Println("  Cost: $0.00 (Ollama)")
+		logging.Infof("  Cost: $0.00 (Ollama)")
 	}
 
-	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-	fmt.Println()
-	fmt.Println("Next steps:")
-	fmt.Println("  - Review generated documentation in docs/")
-	fmt.Println("  - Run: docbrown pr (to create pull request)")
-	fmt.Println("  - Or: docbrown pr --push-direct (to push directly)")
+	// Show which providers served the run when it could fall back
+	if o.llmPool.HasFallbacks() {
+		for _, usage := range summary.Providers {
+			logging.Infof("    - %s: %d requests, %d tokens, $%.2f", usage.Provider, usage.Requests, usage.Tokens, usage.Cost)
+		}
+	}
+
+	if len(o.metrics) > 0 {
+		logging.Blank()
+		printMetricsTable(o.metrics)
+	}
+
+	logging.Rule()
+	logging.Blank()
+	logging.Infof("Next steps:")
+	logging.Infof("  - Review generated documentation in %s/", filepath.Clean(o.config.Documentation.OutputDir))
+	logging.Infof("  - Run: docbrown pr (to create pull request)")
+	logging.Infof("  - Or: docbrown pr --push-direct (to push directly)")
+
+	return summary, nil
+}
+
+// ensureWritableDir creates dir (and its parents) and checks files can be
+// written to it
+func ensureWritableDir(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory %s: %w", dir, err)
+	}
+
+	probe, err := os.CreateTemp(dir, ".docbrown-write-*")
+	if err != nil {
+		return fmt.Errorf("output directory %s is not writable: %w", dir, err)
+	}
+	probe.Close()
+	os.Remove(probe.Name())
 
 	return nil
 }
 
-// ExecuteValidate performs validation
-func (o *Orchestrator) ExecuteValidate() (float64, error) {
+// ExecuteValidate performs validation. The results are saved to the cache
+// directory so 'docbrown pr' can include them in the PR description.
+func (o *Orchestrator) ExecuteValidate() (*validator.ValidationResults, error) {
 	v := validator.NewValidator(o.config.Documentation.OutputDir, o.config.Quality.StrictMode)
+	tmpl, _ := o.templateEng.LoadTemplate(o.config.Documentation.Template)
+	v.SetLayout(validator.LayoutForTemplate(tmpl))
+	if o.config.Quality.SpellCheck {
+		v.EnableSpellCheck(o.config.Quality.SpellAllowlist)
+	}
 
 	results, err := v.Validate()
 	if err != nil {
-		return 0.0, err
+		return nil, err
+	}
+
+	if manifest, err := cache.ReadManifest(o.manifestPath()); err == nil {
+		path := filepath.Join(o.config.Cache.Dir, cache.ValidationFile)
+		if err := validator.SaveResults(path, manifest.Generated, results); err != nil {
+			logging.Warnf("⚠ Failed to save validation results: %v", err)
+		}
 	}
 
 	// Check minimum score
 	if results.QualityScore < o.config.Quality.MinScore {
-		fmt.Printf("⚠ Quality score %.1f below minimum %.1f\n",
+		logging.Warnf("⚠ Quality score %.1f below minimum %.1f",
 			results.QualityScore, o.config.Quality.MinScore)
 	} else {
-		fmt.Printf("✓ Quality score: %.1f/10.0\n", results.QualityScore)
+		logging.Infof("✓ Quality score: %.1f/10.0", results.QualityScore)
 	}
 
-	return results.QualityScore, nil
+	return results, nil
 }
 
-// generateWithLLM uses the LLM to generate content for components
-func (o *Orchestrator) generateWithLLM(ctx context.Context, structure *analyzer.RepoStructure, components []analyzer.Component) ([]EnrichedComponent, error) {
+// generateWithLLM uses the LLM to generate content for components. Components
+// are processed concurrently; the LLM pool bounds the number of in-flight calls.
+func (o *Orchestrator) generateWithLLM(ctx context.Context, structure *analyzer.RepoStructure, components []analyzer.Component, tmpl *template.Template) ([]EnrichedComponent, []ComponentMetrics, error) {
+	o.llmPool.Warmup(ctx)
+
 	enriched := make([]EnrichedComponent, len(components))
+	metrics := make([]ComponentMetrics, len(components))
+	tracker := newProgress(len(components), o.llmPool.Active)
+
+	var wg sync.WaitGroup
 
 	for i, comp := range components {
-		fmt.Printf("\n[%d/%d] Processing: %s\n", i+1, len(components), comp.Name)
-		fmt.Printf("  Type: %s | Language: %s | Files: %d\n", comp.Type, comp.Language, len(comp.Files))
+		wg.Add(1)
 
-		// Prepare context for LLM
-		keyFiles := o.selectKeyFiles(comp)
-		fmt.Printf("  📄 Selected %d key files for analysis\n", len(keyFiles))
+		go func(idx int, component analyzer.Component) {
+			defer wg.Done()
 
-		// Call LLM to analyze and generate overview
-		fmt.Printf("  🤖 Analyzing component structure...\n")
-		analysisReq := llm.AnalysisRequest{
-			ComponentName: comp.Name,
-			ComponentType: comp.Type,
-			Language:      comp.Language,
-			Path:          comp.Path,
-			FileTree:      structure.FileTree,
-			KeyFiles:      keyFiles,
-		}
+			label := fmt.Sprintf("[%d/%d %s]", idx+1, len(components), component.Name)
 
-		result, err := o.llmPool.Analyze(ctx, analysisReq)
-		if err != nil {
-			fmt.Printf("  ⚠ LLM analysis failed: %v\n", comp.Name, err)
-			// Continue with basic info
-			enriched[i] = EnrichedComponent{
-				Component: comp,
-				Overview:  "Documentation for " + comp.Name,
+			// Attribute tokens to this component even though calls run in parallel
+			recorder := &llm.UsageRecorder{}
+			start := time.Now()
+			enriched[idx] = o.processComponent(llm.WithUsageRecorder(ctx, recorder), structure, component, label, &metrics[idx])
+
+			usage := recorder.Usage()
+			metrics[idx].Name = component.Name
+			metrics[idx].Files = len(component.Files)
+			metrics[idx].InputTokens = usage.InputTokens
+			metrics[idx].OutputTokens = usage.OutputTokens
+			metrics[idx].Duration = time.Since(start)
+
+			if !enriched[idx].Incomplete {
+				o.persistComponent(tmpl, structure, enriched[idx], label)
+				tracker.finish(component.Name)
 			}
-			continue
-		}
-		fmt.Printf("  ✓ Analysis complete (%d chars)\n", len(result.Overview))
+		}(i, comp)
+	}
 
-		// Generate detailed documentation
-		fmt.Printf("  🤖 Generating detailed documentation...\n")
-		generateReq := llm.GenerateRequest{
-			ComponentName: comp.Name,
-			ComponentType: comp.Type,
-			Language:      comp.Language,
-			Path:          comp.Path,
-			Files:         keyFiles,
-		}
+	wg.Wait()
+	tracker.close()
 
-		detailedDocs, err := o.llmPool.Generate(ctx, generateReq)
-		if err != nil {
-			fmt.Printf("  ⚠ Documentation generation failed: %v\n", comp.Name, err)
-			detailedDocs = "## " + comp.Name + "\n\n" + result.Overview
-		}
-		fmt.Printf("  ✓ Documentation complete (%d chars)\n", len(detailedDocs))
+	return enriched, metrics, nil
+}
+
+// persistComponent writes a finished component's pages and then records it
+// in the cache, so an interrupted run resumes after it. Pages are written
+// first (atomically) so the cache never claims a component whose docs are
+// missing or half-written. Components with placeholder content are written
+// but not cached, so the next run retries them.
+func (o *Orchestrator) persistComponent(tmpl *template.Template, structure *analyzer.RepoStructure, ec EnrichedComponent, label string) {
+	data := o.buildTemplateData(structure, []EnrichedComponent{ec})
+	if _, err := o.templateEng.RenderComponents(tmpl, data, o.config.Documentation.OutputDir); err != nil {
+		logging.Warnf("%s ⚠ Failed to write component docs: %v", label, err)
+		return
+	}
+	if ec.Failed {
+		return
+	}
+
+	o.persistMu.Lock()
+	defer o.persistMu.Unlock()
+
+	o.cacheManager.Update(ec.Component.Name, ec.Component.Files, ec.KeyFiles, ec.Overview)
+	if err := o.cacheManager.Save(); err != nil {
+		logging.Warnf("%s ⚠ Failed to save cache: %v", label, err)
+	}
+}
+
+// processComponent analyzes and documents a single component. Failures are
+// reported and replaced with basic content so other components are unaffected.
+// Every line of output is prefixed with label since components run in parallel.
+// The number of key files sent is recorded in metrics.
+func (o *Orchestrator) processComponent(ctx context.Context, structure *analyzer.RepoStructure, comp analyzer.Component, label string, metrics *ComponentMetrics) EnrichedComponent {
+	// Components still waiting for the pool when the run is cancelled are skipped
+	if ctx.Err() != nil {
+		return EnrichedComponent{Component: comp, Incomplete: true}
+	}
 
-		enriched[i] = EnrichedComponent{
-			Component:    comp,
-			Overview:     result.Overview,
-			DetailedDocs: detailedDocs,
-			Architecture: detailedDocs, // Use the LLM-generated detailed docs as architecture
+	logging.Infof("%s Processing (type: %s | language: %s | files: %d)", label, comp.Type, comp.Language, len(comp.Files))
+
+	// Prepare context for LLM
+	selection := o.keyFiles(comp)
+	keyFiles := selection.Files
+	metrics.KeyFiles = len(keyFiles)
+	logging.Infof("%s 📄 Selected %d key files for analysis", label, len(keyFiles))
+	if selection.Skipped > 0 {
+		logging.Warnf("%s ⚠ Skipped %d files that would exceed the %d token context budget",
+			label, selection.Skipped, o.config.Performance.MaxContextTokens)
+	}
+	if selection.Sensitive > 0 {
+		logging.Infof("%s 🔒 Skipped %d sensitive files", label, selection.Sensitive)
+	}
+	if selection.Redacted > 0 {
+		logging.Infof("%s 🔒 Redacted %d secrets", label, selection.Redacted)
+	}
+
+	// Call LLM to analyze and generate overview
+	logging.Infof("%s 🤖 Analyzing component structure...", label)
+	analysisReq := llm.AnalysisRequest{
+		ComponentName: comp.Name,
+		ComponentType: comp.Type,
+		Language:      comp.Language,
+		Path:          comp.Path,
+		FileTree:      structure.FileTree,
+		KeyFiles:      keyFiles,
+	}
+
+	result, err := o.llmPool.Analyze(ctx, analysisReq)
+	var parseErr *llm.AnalysisParseError
+	if errors.As(err, &parseErr) {
+		// Keep the unstructured response as the overview rather than losing it
+		logging.Warnf("%s ⚠ %v; using the raw response as the overview", label, err)
+		result, err = &llm.AnalysisResult{Overview: parseErr.Response}, nil
+	}
+	if err != nil && ctx.Err() != nil {
+		return EnrichedComponent{Component: comp, Incomplete: true}
+	}
+	if err != nil {
+		logging.Warnf("%s ⚠ LLM analysis failed: %v", label, err)
+		// Continue with basic info
+		return EnrichedComponent{
+			Component: comp,
+			Overview:  "Documentation for " + comp.Name,
+			KeyFiles:  selection.Paths(),
+			Failed:    true,
 		}
+	}
+	logging.Infof("%s ✓ Analysis complete (%d chars)", label, len(result.Overview))
+
+	// Generate detailed documentation
+	logging.Infof("%s 🤖 Generating detailed documentation...", label)
+	generateReq := llm.GenerateRequest{
+		ComponentName: comp.Name,
+		ComponentType: comp.Type,
+		Language:      comp.Language,
+		Path:          comp.Path,
+		Files:         keyFiles,
+	}
 
-		fmt.Printf("  ✅ Component processing complete\n")
+	var detailedDocs string
+	if o.config.LLM.Stream {
+		// Report progress on whole lines so parallel components stay readable
+		received, reported := 0, 0
+		detailedDocs, err = o.llmPool.GenerateStream(ctx, generateReq, func(chunk string) {
+			received += len(chunk)
+			if received-reported >= streamProgressInterval {
+				reported = received
+				logging.Infof("%s    ... %d chars received", label, received)
+			}
+		})
+	} else {
+		detailedDocs, err = o.llmPool.Generate(ctx, generateReq)
 	}
+	if err != nil && ctx.Err() != nil {
+		return EnrichedComponent{Component: comp, Incomplete: true}
+	}
+	failed := err != nil
+	if failed {
+		logging.Warnf("%s ⚠ Documentation generation failed: %v", label, err)
+		detailedDocs = "## " + comp.Name + "\n\n" + result.Overview
+	}
+	logging.Infof("%s ✓ Documentation complete (%d chars)", label, len(detailedDocs))
+
+	logging.Infof("%s ✅ Component processing complete", label)
+
+	return EnrichedComponent{
+		Component:    comp,
+		Overview:     result.Overview,
+		DetailedDocs: detailedDocs,
+		Architecture: detailedDocs, // Use the LLM-generated detailed docs as architecture
+		KeyFiles:     selection.Paths(),
+		Failed:       failed,
+	}
+}
+
+// keyFileSelection is the result of selecting a component's key files
+type keyFileSelection struct {
+	Files     []llm.FileContent
+	Skipped   int // files that would exceed the token budget
+	Sensitive int // files matching the sensitive file patterns
+	Redacted  int // secrets redacted from the selected files
+}
 
-	return enriched, nil
+// Paths returns the paths of the selected files
+func (s keyFileSelection) Paths() []string {
+	paths := make([]string, len(s.Files))
+	for i, file := range s.Files {
+		paths[i] = file.Path
+	}
+	return paths
 }
 
-// selectKeyFiles selects the most important files for a component
-func (o *Orchestrator) selectKeyFiles(comp analyzer.Component) []llm.FileContent {
-	var keyFiles []llm.FileContent
-	maxFiles := 20 // Limit files sent to LLM
+// selectKeyFiles selects the most important files for a component, staying
+// within the configured file count and context token budget
+func (o *Orchestrator) selectKeyFiles(comp analyzer.Component) keyFileSelection {
+	var selection keyFileSelection
+
+	maxFiles := o.config.Performance.MaxFilesPerComponent
+	if maxFiles <= 0 {
+		maxFiles = 20
+	}
+	maxTokens := o.config.Performance.MaxContextTokens // 0 means unlimited
 
 	// Priority files
 	priorityPatterns := []string{
@@ -329,35 +887,43 @@ func (o *Orchestrator) selectKeyFiles(comp analyzer.Component) []llm.FileContent
 		}
 	}
 
-	// Take all priority files
-	for _, file := range priority {
-		if len(keyFiles) >= maxFiles {
+	usedTokens := 0
+
+	// Take priority files first, then fill remaining with other files
+	for _, file := range append(priority, others...) {
+		if len(selection.Files) >= maxFiles {
 			break
 		}
-		content, err := readFileContent(file)
-		if err == nil {
-			keyFiles = append(keyFiles, llm.FileContent{
-				Path:    file,
-				Content: content,
-			})
+		if o.isSensitive(file) {
+			selection.Sensitive++
+			continue
 		}
-	}
-
-	// Fill remaining with other files
-	for _, file := range others {
-		if len(keyFiles) >= maxFiles {
-			break
+		content, redacted, err := o.readFileContent(file)
+		if err != nil {
+			continue
 		}
-		content, err := readFileContent(file)
-		if err == nil {
-			keyFiles = append(keyFiles, llm.FileContent{
-				Path:    file,
-				Content: content,
-			})
+
+		// Skip files that would blow the context window; smaller ones may still fit
+		tokens := estimateTokens(content)
+		if maxTokens > 0 && usedTokens+tokens > maxTokens {
+			selection.Skipped++
+			continue
 		}
+
+		usedTokens += tokens
+		selection.Redacted += redacted
+		selection.Files = append(selection.Files, llm.FileContent{
+			Path:    file,
+			Content: content,
+		})
 	}
 
-	return keyFiles
+	return selection
+}
+
+// estimateTokens roughly estimates the token count of text (~4 chars per token)
+func estimateTokens(text string) int {
+	return (len(text) + 3) / 4
 }
 
 // EnrichedComponent contains component with LLM-generated content
@@ -366,6 +932,9 @@ type EnrichedComponent struct {
 	Overview     string
 	DetailedDocs string
 	Architecture string
+	KeyFiles     []string // paths of the files sent to the LLM
+	Incomplete   bool     // processing was cut short by cancellation
+	Failed       bool     // an LLM call failed and placeholder content was used
 }
 
 func contains(s, substr string) bool {
@@ -373,31 +942,163 @@ func contains(s, substr string) bool {
 		(s[:len(substr)] == substr || s[len(s)-len(substr):] == substr))
 }
 
-func readFileContent(path string) (string, error) {
-	content, err := os.ReadFile(path)
+// isSensitive reports whether a file matches the configured sensitive patterns
+func (o *Orchestrator) isSensitive(path string) bool {
+	return analyzer.IsSensitive(path, o.config.Documentation.ExcludeSensitive)
+}
+
+// readFileContent reads a file for the LLM and redacts any secrets in it,
+// returning how many were redacted. Sensitive files are refused here so they
+// can never be sent, whichever code path asks for them.
+func (o *Orchestrator) readFileContent(path string) (string, int, error) {
+	if o.isSensitive(path) {
+		return "", 0, fmt.Errorf("refusing to read sensitive file %s", path)
+	}
+
+	data, err := os.ReadFile(path)
 	if err != nil {
-		return "", err
+		return "", 0, err
+	}
+	content := string(data)
+
+	// Secrets are redacted before truncating, so a cut can't leave part of
+	// one (e.g. a PEM block without its END line) unrecognised
+	count := 0
+	if o.redactor != nil {
+		content, count = o.redactor.Redact(content)
+	}
+
+	// Large files are cut at a line boundary (~4 chars per token)
+	return truncateContent(content, o.maxFileTokens()*4), count, nil
+}
+
+// redactConfiguration redacts secrets from documented configuration values,
+// since .env example files sometimes hold real credentials
+func (o *Orchestrator) redactConfiguration(config map[string]string) map[string]string {
+	if o.redactor == nil || len(config) == 0 {
+		return config
 	}
-	// Limit file size
-	if len(content) > 50000 {
-		content = content[:50000]
+
+	redacted := make(map[string]string, len(config))
+	for key, value := range config {
+		redacted[key], _ = o.redactor.Redact(value)
 	}
-	return string(content), nil
+	return redacted
 }
 
-// getComponentsToGenerate determines which components need regeneration
-func (o *Orchestrator) getComponentsToGenerate(structure *analyzer.RepoStructure) []analyzer.Component {
+// changedFilesSince returns the set of files changed since a git ref
+func (o *Orchestrator) changedFilesSince(ref string) (map[string]bool, error) {
+	gitOps, err := git.NewOperations(o.config.Git.Remote, o.config.Git.BaseBranch)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := gitOps.ChangedFilesSince(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	logging.Infof("  %d files changed since %s", len(files), ref)
+
+	changed := make(map[string]bool, len(files))
+	for _, file := range files {
+		changed[file] = true
+	}
+	return changed, nil
+}
+
+// getComponentsToGenerate determines which components need regeneration.
+// With a set of changed files, components containing any of them are
+// regenerated and the cache isn't consulted.
+func (o *Orchestrator) getComponentsToGenerate(structure *analyzer.RepoStructure, changedFiles map[string]bool) []analyzer.Component {
 	var components []analyzer.Component
 
+	if changedFiles != nil {
+		for _, comp := range structure.Components {
+			if componentChanged(comp, changedFiles) {
+				components = append(components, comp)
+			}
+		}
+		return components
+	}
+
 	for _, comp := range structure.Components {
-		if o.cacheManager.IsStale(comp.Name, comp.Files) {
-			components = append(components, comp)
+		if !o.cacheManager.IsStale(comp.Name, comp.Files) {
+			continue
 		}
+
+		// Only regenerate if something the LLM would see has changed
+		if !o.cacheManager.KeyFilesChanged(comp.Name, o.keyFiles(comp).Paths()) {
+			changed := o.cacheManager.ChangedFiles(comp.Name, comp.Files)
+			logging.Infof("  ✓ %s: %d files changed, none sent to the LLM (using cache)",
+				comp.Name, len(changed))
+			continue
+		}
+
+		components = append(components, comp)
 	}
 
 	return components
 }
 
+// componentChanged reports whether any changed file lies within the component
+func componentChanged(comp analyzer.Component, changedFiles map[string]bool) bool {
+	dir := filepath.ToSlash(filepath.Clean(comp.Path))
+	for file := range changedFiles {
+		if dir == "." || file == dir || strings.HasPrefix(file, dir+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// keyFiles returns the key file selection for a component, selecting once per
+// analysis so the cost check, cache check and generation see the same files
+func (o *Orchestrator) keyFiles(comp analyzer.Component) keyFileSelection {
+	o.selectionMu.Lock()
+	selection, ok := o.selections[comp.Name]
+	o.selectionMu.Unlock()
+	if ok {
+		return selection
+	}
+
+	selection = o.selectKeyFiles(comp)
+
+	o.selectionMu.Lock()
+	if o.selections != nil {
+		o.selections[comp.Name] = selection
+	}
+	o.selectionMu.Unlock()
+	return selection
+}
+
+// defaultBranch returns the branch the docs are based on, falling back to
+// "main" outside a git repository
+func defaultBranch(gitOps *git.Operations) string {
+	if gitOps == nil {
+		return "main"
+	}
+
+	branch, err := gitOps.GetBaseBranch()
+	if err != nil {
+		return "main"
+	}
+	return branch
+}
+
+// repoURL returns the HTTPS browse URL of the repository, or "" without a remote
+func repoURL(gitOps *git.Operations) string {
+	if gitOps == nil {
+		return ""
+	}
+
+	remoteURL, err := gitOps.GetRemoteURL()
+	if err != nil {
+		return ""
+	}
+	return git.BrowseURL(remoteURL)
+}
+
 // buildTemplateData builds the data structure for templates using LLM-generated content
 func (o *Orchestrator) buildTemplateData(structure *analyzer.RepoStructure, enriched []EnrichedComponent) template.TemplateData {
 	// Use configured attribution or default
@@ -406,13 +1107,17 @@ func (o *Orchestrator) buildTemplateData(structure *analyzer.RepoStructure, enri
 		generatedBy = "Generated by DocBrown v1.0.0"
 	}
 
+	// Outside a git repository gitOps is nil and git details are left at defaults
+	gitOps, _ := git.NewOperations(o.config.Git.Remote, o.config.Git.BaseBranch)
+
 	data := template.TemplateData{
 		RepoName:      getRepoName(),
+		RepoURL:       repoURL(gitOps),
 		Description:   "Automatically generated documentation",
-		Timestamp:     time.Now(),
+		Timestamp:     o.timestamp(gitOps),
 		GeneratedBy:   generatedBy,
 		Version:       "1.0.0",
-		DefaultBranch: "main",
+		DefaultBranch: defaultBranch(gitOps),
 	}
 
 	// Build overview from LLM-generated content
@@ -425,43 +1130,279 @@ func (o *Orchestrator) buildTemplateData(structure *analyzer.RepoStructure, enri
 		comp := ec.Component
 
 		compData := template.ComponentData{
-			Name:         comp.Name,
-			Type:         comp.Type,
-			Language:     comp.Language,
-			Path:         comp.Path,
-			Description:  comp.Description,
-			Overview:     ec.Overview,
-			Architecture: ec.Architecture,
-			HasTests:     comp.HasTests,
+			APIs:                   componentAPIs(comp),
+			Functions:              comp.Functions,
+			Framework:              comp.Framework,
+			Routes:                 comp.Routes,
+			Dependencies:           internalDependencies(comp),
+			DirectDependencies:     externalDependencies(comp, "direct"),
+			TransitiveDependencies: externalDependencies(comp, "indirect"),
+			DevDependencies:        externalDependencies(comp, "dev"),
+			Ports:                  comp.Ports,
+			BaseImage:              comp.BaseImage,
+			Image:                  comp.Image,
+			Configuration:          o.redactConfiguration(comp.Configuration),
+			Name:                   comp.Name,
+			Type:                   comp.Type,
+			Language:               comp.Language,
+			Runtime:                comp.Runtime,
+			RuntimeVersion:         comp.RuntimeVersion,
+			Path:                   comp.Path,
+			Description:            comp.Description,
+			Overview:               ec.Overview,
+			Architecture:           ec.Architecture,
+			HasTests:               comp.HasTests,
+			GeneratedBy:            generatedBy,
 		}
 
 		data.Components = append(data.Components, compData)
 
+		if comp.Type == "library" {
+			data.Libraries = append(data.Libraries, template.LibraryData{
+				Name:        comp.Name,
+				Language:    comp.Language,
+				Description: comp.Description,
+				Functions:   comp.Functions,
+			})
+		}
+
+		if comp.Type == "frontend" {
+			data.Frontends = append(data.Frontends, template.FrontendData{
+				Name:        comp.Name,
+				Framework:   comp.Framework,
+				Description: comp.Description,
+				Routes:      comp.Routes,
+			})
+		}
+
 		// Add to services if applicable
 		if comp.Type == "service" {
-			data.Services = append(data.Services, template.ServiceData{
+			protocol := comp.Protocol
+			if protocol == "" {
+				protocol = "rest"
+			}
+
+			svc := template.ServiceData{
 				Name:        comp.Name,
-				Type:        "rest",
+				Type:        protocol,
 				Description: ec.Overview,
-			})
+				Endpoints:   compData.APIs,
+				Ports:       comp.Ports,
+				BaseImage:   comp.BaseImage,
+				Image:       comp.Image,
+			}
+			if len(comp.Ports) > 0 {
+				svc.Port = comp.Ports[0]
+			}
+			data.Services = append(data.Services, svc)
 		}
 	}
 
+	// API entities cover every component, not just the regenerated ones,
+	// because the catalog describes the whole repository
+	data.APIs = apiEntities(structure.AllComponents, data.RepoName, o.config.Documentation.OutputDir)
+
 	// Build architecture data
 	data.Architecture = template.ArchitectureData{
 		Overview: "This repository contains " + fmt.Sprintf("%d", len(enriched)) + " components",
 	}
 
-	for lang := range structure.Languages {
-		data.Architecture.Technologies = append(data.Architecture.Technologies, lang)
+	// Most prevalent language first
+	data.Architecture.PrimaryLanguage = structure.PrimaryLanguage
+	for _, stat := range structure.LanguageStats {
+		data.Architecture.Technologies = append(data.Architecture.Technologies, stat.Language)
+		data.Architecture.Languages = append(data.Architecture.Languages, template.LanguageData{
+			Name:        stat.Language,
+			Files:       stat.Files,
+			Percent:     stat.Percent,
+			BytePercent: stat.BytePercent,
+		})
 	}
 
+	data.Architecture.Diagram = dependencyDiagram(structure.AllComponents)
+
 	// Generate getting started content
 	data.GettingStarted = "Follow the steps below to set up and run this project."
 
+	if o.config.Documentation.Deterministic {
+		sortTemplateData(&data)
+	}
+
 	return data
 }
 
+// componentAPIs returns a component's API documentation, preferring parsed
+// OpenAPI specs over endpoints detected in source
+func componentAPIs(comp analyzer.Component) []template.APIData {
+	if len(comp.APIs) > 0 {
+		return comp.APIs
+	}
+
+	var apis []template.APIData
+	for _, ep := range comp.Endpoints {
+		apis = append(apis, template.APIData{
+			Method:      ep.Method,
+			Path:        ep.Path,
+			Description: ep.Description,
+		})
+	}
+	return apis
+}
+
+// apiEntities builds a Backstage API entity for each API definition file.
+// Definitions are referenced relative to the output directory, where the
+// catalog file is written.
+func apiEntities(components []analyzer.Component, repoName, outputDir string) []template.APIEntityData {
+	var entities []template.APIEntityData
+	used := make(map[string]bool)
+
+	for _, comp := range components {
+		owner := comp.Name
+		if owner == "." {
+			owner = repoName
+		}
+
+		for _, spec := range comp.APISpecs {
+			name := entityName(owner) + "-api"
+			if len(comp.APISpecs) > 1 {
+				stem := strings.TrimSuffix(filepath.Base(spec.Path), filepath.Ext(spec.Path))
+				name = entityName(owner + "-" + stem)
+			}
+			for base, i := name, 2; used[name]; i++ {
+				name = fmt.Sprintf("%s-%d", base, i)
+			}
+			used[name] = true
+
+			entities = append(entities, template.APIEntityData{
+				Name:        name,
+				Type:        spec.Type,
+				Description: fmt.Sprintf("%s API provided by %s", spec.Type, owner),
+				Component:   owner,
+				Definition:  relativeTo(outputDir, spec.Path),
+			})
+		}
+	}
+
+	return entities
+}
+
+// entityName converts a name to a valid Backstage entity name: letters,
+// digits and single [-_.] separators, at most 63 characters
+func entityName(name string) string {
+	var sb strings.Builder
+	sep := false
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			if sep && sb.Len() > 0 {
+				sb.WriteByte('-')
+			}
+			sb.WriteRune(r)
+			sep = false
+		default:
+			sep = true
+		}
+	}
+
+	result := sb.String()
+	if len(result) > 63 {
+		result = strings.TrimRight(result[:63], "-")
+	}
+	if result == "" {
+		result = "api"
+	}
+	return result
+}
+
+// relativeTo returns path relative to dir with forward slashes, or the
+// absolute path if no relative path exists
+func relativeTo(dir, path string) string {
+	absDir, err1 := filepath.Abs(dir)
+	absPath, err2 := filepath.Abs(path)
+	if err1 != nil || err2 != nil {
+		return filepath.ToSlash(path)
+	}
+
+	rel, err := filepath.Rel(absDir, absPath)
+	if err != nil {
+		return filepath.ToSlash(absPath)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// internalDependencies returns the other components a component imports
+func internalDependencies(comp analyzer.Component) []template.DependencyData {
+	var deps []template.DependencyData
+	for _, dep := range comp.Dependencies {
+		if dep.Type != "internal" {
+			continue
+		}
+		deps = append(deps, template.DependencyData{
+			Name:    dep.Name,
+			Type:    dep.Type,
+			Purpose: "Internal component",
+		})
+	}
+	return deps
+}
+
+// externalDependencies returns the manifest dependencies with the given
+// scope. Manifests that don't record a scope only list direct dependencies.
+func externalDependencies(comp analyzer.Component, scope string) []template.DependencyData {
+	var deps []template.DependencyData
+	for _, dep := range comp.Dependencies {
+		depScope := dep.Scope
+		switch {
+		case dep.Type == "dev":
+			depScope = "dev"
+		case depScope == "":
+			depScope = "direct"
+		}
+		if dep.Type == "internal" || depScope != scope {
+			continue
+		}
+		deps = append(deps, template.DependencyData{
+			Name:    dep.Name,
+			Version: dep.Version,
+			Type:    dep.Type,
+			Scope:   depScope,
+			Purpose: dep.Purpose,
+		})
+	}
+	return deps
+}
+
+// dependencyDiagram builds a Mermaid graph of internal dependencies between
+// components, or returns "" if no component depends on another
+func dependencyDiagram(components []analyzer.Component) string {
+	ids := make(map[string]string, len(components))
+	for i, comp := range components {
+		ids[comp.Name] = fmt.Sprintf("c%d", i)
+	}
+
+	var edges []string
+	for _, comp := range components {
+		for _, dep := range comp.Dependencies {
+			if target, ok := ids[dep.Name]; ok && dep.Type == "internal" {
+				edges = append(edges, fmt.Sprintf("    %s --> %s", ids[comp.Name], target))
+			}
+		}
+	}
+
+	if len(edges) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("graph TD\n")
+	for _, comp := range components {
+		sb.WriteString(fmt.Sprintf("    %s[\"%s\"]\n", ids[comp.Name], comp.Name))
+	}
+	sb.WriteString(strings.Join(edges, "\n"))
+
+	return sb.String()
+}
+
 func getRepoName() string {
 	// Try to get from git config or directory name
 	if dir, err := os.Getwd(); err == nil {
@@ -0,0 +1,132 @@
+package orchestrator
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docbrown/cli/internal/logging"
+)
+
+// progressBarWidth is the number of cells in the progress bar
+const progressBarWidth = 20
+
+// progressRefresh is how often the live progress line is redrawn
+const progressRefresh = 500 * time.Millisecond
+
+// progress reports overall generation progress. On a terminal it keeps a
+// live status line below the per-component output; otherwise it logs a
+// plain line as each component finishes.
+type progress struct {
+	mu     sync.Mutex
+	total  int
+	done   int
+	start  time.Time
+	active func() []string // components currently being worked on
+
+	live bool
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newProgress starts tracking progress over total components
+func newProgress(total int, active func() []string) *progress {
+	p := &progress{
+		total:  total,
+		start:  time.Now(),
+		active: active,
+		live:   logging.StatusSupported(),
+		stop:   make(chan struct{}),
+	}
+
+	if p.live {
+		p.wg.Add(1)
+		go p.refresh()
+	}
+
+	return p
+}
+
+// finish records a completed component
+func (p *progress) finish(name string) {
+	p.mu.Lock()
+	p.done++
+	done := p.done
+	p.mu.Unlock()
+
+	if p.live {
+		logging.SetStatus(p.line())
+		return
+	}
+
+	logging.Infof("[%d/%d] %s finished (%s elapsed)", done, p.total, name, p.elapsed())
+}
+
+// close stops live updates and removes the status line
+func (p *progress) close() {
+	if !p.live {
+		return
+	}
+
+	close(p.stop)
+	p.wg.Wait()
+	logging.ClearStatus()
+}
+
+// refresh redraws the status line so the elapsed time and current
+// components stay up to date between completions
+func (p *progress) refresh() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(progressRefresh)
+	defer ticker.Stop()
+
+	logging.SetStatus(p.line())
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			logging.SetStatus(p.line())
+		}
+	}
+}
+
+// line renders the status line, e.g.
+// "[3/10] ██████░░░░░░░░░░░░░░  30%  1m05s  api, web"
+func (p *progress) line() string {
+	p.mu.Lock()
+	done := p.done
+	p.mu.Unlock()
+
+	filled := 0
+	if p.total > 0 {
+		filled = done * progressBarWidth / p.total
+	}
+	bar := strings.Repeat("█", filled) + strings.Repeat("░", progressBarWidth-filled)
+
+	percent := 0
+	if p.total > 0 {
+		percent = done * 100 / p.total
+	}
+
+	line := fmt.Sprintf("[%d/%d] %s %3d%%  %s", done, p.total, bar, percent, p.elapsed())
+	if current := p.active(); len(current) > 0 {
+		line += "  " + summarizeNames(current, 3)
+	}
+	return line
+}
+
+// elapsed returns the time since generation started, rounded for display
+func (p *progress) elapsed() time.Duration {
+	return time.Since(p.start).Round(time.Second)
+}
+
+// summarizeNames joins up to max names, noting how many were left out
+func summarizeNames(names []string, max int) string {
+	if len(names) <= max {
+		return strings.Join(names, ", ")
+	}
+	return fmt.Sprintf("%s +%d", strings.Join(names[:max], ", "), len(names)-max)
+}
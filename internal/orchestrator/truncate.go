@@ -0,0 +1,84 @@
+package orchestrator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// defaultMaxFileTokens caps a file when neither max_file_tokens nor
+// max_context_tokens is set (~50KB)
+const defaultMaxFileTokens = 12500
+
+// declarationLine matches unindented top-level declarations in the common
+// languages: functions, types, classes, constants and so on
+var declarationLine = regexp.MustCompile(`^(?:(?:export|pub(?:\([a-z]+\))?|public|private|protected|internal|static|abstract|final|async|default|data|sealed|open)\s+)*(?:func|function|type|class|def|fn|interface|struct|enum|trait|impl|module|object|const|var|let|val)\b`)
+
+// maxFileTokens returns the most tokens of a single file sent to the LLM.
+// Unless set explicitly it is half the context budget, so one large file
+// can't crowd out the rest of a component.
+func (o *Orchestrator) maxFileTokens() int {
+	if o.config.Performance.MaxFileTokens > 0 {
+		return o.config.Performance.MaxFileTokens
+	}
+	if o.config.Performance.MaxContextTokens > 0 {
+		return o.config.Performance.MaxContextTokens / 2
+	}
+	return defaultMaxFileTokens
+}
+
+// truncateContent shortens content to at most maxBytes, cutting at a line
+// boundary. A quarter of the budget lists the top-level declarations from
+// the cut part, so the LLM still sees the file's overall shape, followed by
+// a marker saying how many lines were dropped.
+func truncateContent(content string, maxBytes int) string {
+	if len(content) <= maxBytes {
+		return content
+	}
+
+	lines := strings.SplitAfter(content, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	var head strings.Builder
+	kept := 0
+	for _, line := range lines {
+		if head.Len()+len(line) > maxBytes*3/4 {
+			break
+		}
+		head.WriteString(line)
+		kept++
+	}
+	rest := lines[kept:]
+
+	// A huge first line (minified code) is cut mid-line rather than dropped
+	if kept == 0 {
+		head.WriteString(strings.ToValidUTF8(content[:maxBytes*3/4], ""))
+		rest = lines[1:]
+	}
+
+	var outline strings.Builder
+	for _, line := range rest {
+		if !declarationLine.MatchString(line) {
+			continue
+		}
+		declaration := strings.TrimRight(strings.TrimSpace(line), "{ ") + "\n"
+		if head.Len()+outline.Len()+len(declaration) > maxBytes {
+			break
+		}
+		outline.WriteString(declaration)
+	}
+
+	var b strings.Builder
+	b.WriteString(head.String())
+	if !strings.HasSuffix(head.String(), "\n") {
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "... (truncated %d lines)\n", len(lines)-kept)
+	if outline.Len() > 0 {
+		b.WriteString("... top-level declarations in the truncated part:\n")
+		b.WriteString(outline.String())
+	}
+	return b.String()
+}
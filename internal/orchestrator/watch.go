@@ -0,0 +1,290 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/docbrown/cli/internal/logging"
+)
+
+// defaultWatchDebounce is how long files must stop changing before a rerun
+const defaultWatchDebounce = 500 * time.Millisecond
+
+// WatchOptions controls how ExecuteWatch reruns
+type WatchOptions struct {
+	// Generate regenerates documentation on changes instead of only
+	// reanalyzing
+	Generate bool
+
+	// Debounce is how long files must stop changing before a rerun
+	Debounce time.Duration
+}
+
+// ExecuteWatch analyzes (or generates) once, then again whenever files that
+// aren't excluded change, until ctx is cancelled. Generation reuses the
+// cache, so only components whose files changed are rebuilt. A failed rerun
+// is reported and watching continues.
+func (o *Orchestrator) ExecuteWatch(ctx context.Context, opts WatchOptions) error {
+	if opts.Debounce <= 0 {
+		opts.Debounce = defaultWatchDebounce
+	}
+
+	// The first run also reads the ignore files the watcher filters with
+	if err := o.watchRun(ctx, opts); err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return err
+	}
+
+	watcher, err := newRepoWatcher(o.watchIgnores)
+	if err != nil {
+		return fmt.Errorf("failed to start watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	if _, err := watcher.addTree("."); err != nil {
+		return fmt.Errorf("failed to watch repository: %w", err)
+	}
+
+	logging.Blank()
+	logging.Infof("👀 Watching %d directories for changes (Ctrl-C to stop)...", len(watcher.dirs))
+
+	debounce := time.NewTimer(opts.Debounce)
+	debounce.Stop()
+	defer debounce.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logging.Blank()
+			logging.Infof("👋 Stopped watching")
+			return nil
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			logging.Warnf("⚠ Watch error: %v", err)
+
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if watcher.handle(event) {
+				debounce.Reset(opts.Debounce)
+			}
+
+		case <-debounce.C:
+			changed := watcher.take()
+			logging.Blank()
+			o.reportChanges(changed, opts.Generate)
+
+			if err := o.watchRun(ctx, opts); err != nil && ctx.Err() == nil {
+				logging.Warnf("⚠ %v", err)
+			}
+
+			logging.Blank()
+			logging.Infof("👀 Watching for changes...")
+		}
+	}
+}
+
+// watchRun runs one analysis or generation pass
+func (o *Orchestrator) watchRun(ctx context.Context, opts WatchOptions) error {
+	if opts.Generate {
+		_, err := o.ExecuteGenerate(ctx, GenerateOptions{Resume: true})
+		return err
+	}
+	_, err := o.ExecuteAnalyze(ctx)
+	return err
+}
+
+// reportChanges prints the one-line summary of a batch of changes. The
+// affected components come from the previous analysis, so files outside
+// all of them may belong to new ones.
+func (o *Orchestrator) reportChanges(paths []string, generate bool) {
+	files := fmt.Sprintf("%d files", len(paths))
+	if len(paths) == 1 {
+		files = "1 file"
+	}
+
+	if !generate || o.structure == nil {
+		logging.Infof("🔄 changed: %s, reanalyzing", files)
+		return
+	}
+
+	changedFiles := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		changedFiles[path] = true
+	}
+	affected := 0
+	for _, comp := range o.structure.Components {
+		if componentChanged(comp, changedFiles) {
+			affected++
+		}
+	}
+
+	components := fmt.Sprintf("%d components", affected)
+	if affected == 1 {
+		components = "1 component"
+	}
+	if o.outsideComponents(paths) {
+		components += " and any new ones"
+	}
+	logging.Infof("🔄 changed: %s, regenerating %s", files, components)
+}
+
+// outsideComponents reports whether any path is in none of the last
+// analysis's components
+func (o *Orchestrator) outsideComponents(paths []string) bool {
+	for _, path := range paths {
+		inside := false
+		for _, comp := range o.structure.Components {
+			if componentChanged(comp, map[string]bool{path: true}) {
+				inside = true
+				break
+			}
+		}
+		if !inside {
+			return true
+		}
+	}
+	return false
+}
+
+// watchIgnores reports whether changes to path don't matter: excluded or
+// ignored files, and the docs and cache written by generation itself. Files
+// outside the include patterns still count, as manifests (go.mod, package.json,
+// Dockerfile, ...) and specs shape the analysis.
+func (o *Orchestrator) watchIgnores(path string, isDir bool) bool {
+	for _, dir := range []string{o.config.Documentation.OutputDir, o.config.Cache.Dir} {
+		dir = filepath.Clean(dir)
+		if dir == "." {
+			continue
+		}
+		if rel, err := filepath.Rel(dir, path); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return o.analyzer.Excludes(path, isDir)
+}
+
+// repoWatcher collects changes to the files analysis may read. fsnotify
+// doesn't watch recursively, so each directory is added on its own.
+type repoWatcher struct {
+	*fsnotify.Watcher
+	ignores func(path string, isDir bool) bool
+	dirs    map[string]bool // watched directories
+	changed map[string]bool // slash-separated paths changed since the last take
+}
+
+// newRepoWatcher creates a watcher skipping the paths ignores reports
+func newRepoWatcher(ignores func(path string, isDir bool) bool) (*repoWatcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	return &repoWatcher{
+		Watcher: watcher,
+		ignores: ignores,
+		dirs:    make(map[string]bool),
+		changed: make(map[string]bool),
+	}, nil
+}
+
+// addTree watches root and the directories below it that aren't ignored,
+// returning the files found in them
+func (w *repoWatcher) addTree(root string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			// Removed while walking
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+
+		if !entry.IsDir() {
+			if !w.ignores(path, false) {
+				files = append(files, filepath.ToSlash(path))
+			}
+			return nil
+		}
+
+		if path != "." && w.ignores(path, true) {
+			return filepath.SkipDir
+		}
+		if err := w.Add(path); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		w.dirs[path] = true
+		return nil
+	})
+	return files, err
+}
+
+// handle records an event and reports whether it changed anything that
+// matters. New directories are watched, and their files count as changed.
+func (w *repoWatcher) handle(event fsnotify.Event) bool {
+	path := filepath.Clean(event.Name)
+
+	switch {
+	case event.Op == fsnotify.Chmod:
+		return false
+
+	case w.dirs[path] && (event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename)):
+		// Everything below a removed directory is gone too
+		for dir := range w.dirs {
+			if dir == path || strings.HasPrefix(dir, path+string(filepath.Separator)) {
+				delete(w.dirs, dir)
+			}
+		}
+		w.Remove(path)
+		w.changed[filepath.ToSlash(path)] = true
+		return true
+
+	case event.Has(fsnotify.Create):
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			if w.ignores(path, true) {
+				return false
+			}
+			// Files may have been created before the directory was watched
+			files, err := w.addTree(path)
+			if err != nil {
+				logging.Warnf("⚠ Failed to watch %s: %v", path, err)
+			}
+			for _, file := range files {
+				w.changed[file] = true
+			}
+			return len(files) > 0
+		}
+	}
+
+	if w.ignores(path, false) {
+		return false
+	}
+	w.changed[filepath.ToSlash(path)] = true
+	return true
+}
+
+// take returns the changed paths, sorted, and starts a new batch
+func (w *repoWatcher) take() []string {
+	paths := make([]string, 0, len(w.changed))
+	for path := range w.changed {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	clear(w.changed)
+	return paths
+}
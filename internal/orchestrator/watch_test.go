@@ -0,0 +1,59 @@
+package orchestrator
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestWatchIgnores(t *testing.T) {
+	repo := t.TempDir()
+	writeRepo(t, repo, testRepo)
+	writeRepo(t, repo, map[string]string{
+		".docbrownignore":                "*.log\n",
+		"services/orders/orders.proto":   "syntax = \"proto3\";\n",
+		"services/orders/openapi.yaml":   "openapi: 3.0.0\n",
+		"services/orders/main_test.go":   "package main\n",
+		"services/orders/debug.log":      "",
+		"services/web/node_modules/x.js": "",
+	})
+	t.Chdir(repo)
+	t.Setenv("HOME", t.TempDir())
+
+	cfg := mockConfig()
+	// Only sources are scanned; manifests are still read by detection
+	cfg.Documentation.IncludePatterns = []string{"**/*.go", "**/*.js"}
+	o, err := NewOrchestrator(cfg)
+	if err != nil {
+		t.Fatalf("NewOrchestrator: %v", err)
+	}
+	// Reads the ignore files
+	if _, err := o.ExecuteAnalyze(context.Background()); err != nil {
+		t.Fatalf("ExecuteAnalyze: %v", err)
+	}
+
+	tests := []struct {
+		path    string
+		isDir   bool
+		ignored bool
+	}{
+		{path: "services/orders/main.go"},
+		{path: "services/orders/go.mod"},
+		{path: "services/orders/Dockerfile"},
+		{path: "services/orders/orders.proto"},
+		{path: "services/orders/openapi.yaml"},
+		{path: "services/web/package.json"},
+		{path: "README.md"},
+		{path: "services/orders/main_test.go", ignored: true},
+		{path: "services/orders/debug.log", ignored: true},
+		{path: "services/web/node_modules", isDir: true, ignored: true},
+		{path: "docs/index.md", ignored: true},
+		{path: ".docbrown/cache.yaml", ignored: true},
+	}
+
+	for _, tt := range tests {
+		if got := o.watchIgnores(filepath.FromSlash(tt.path), tt.isDir); got != tt.ignored {
+			t.Errorf("watchIgnores(%q) = %v, want %v", tt.path, got, tt.ignored)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package preview
+
+import (
+	"archive/zip"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	idAttrPattern   = regexp.MustCompile(`id="([^"]*)"`)
+	hrefAttrPattern = regexp.MustCompile(`href="([^"]*)"`)
+	srcAttrPattern  = regexp.MustCompile(`src="([^"]*)"`)
+)
+
+// exportPage is one markdown file in the documentation tree
+type exportPage struct {
+	rel    string // slash-separated path relative to the docs root
+	anchor string
+}
+
+// ExportHTML renders every markdown file under dir into a single
+// self-contained HTML page. Links between pages become in-page anchors and
+// local images are embedded as data URIs.
+func ExportHTML(dir, title string, w io.Writer) error {
+	pages, err := markdownPages(dir)
+	if err != nil {
+		return err
+	}
+	if len(pages) == 0 {
+		return fmt.Errorf("no markdown files found in %s", dir)
+	}
+
+	anchors := make(map[string]string, len(pages))
+	for _, page := range pages {
+		anchors[page.rel] = page.anchor
+	}
+
+	var toc, body strings.Builder
+	for _, page := range pages {
+		content, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(page.rel)))
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", page.rel, err)
+		}
+
+		toc.WriteString(fmt.Sprintf("<li><a href=\"#%s\">%s</a></li>\n", page.anchor, html.EscapeString(page.rel)))
+
+		rendered := RenderMarkdown(string(content))
+		rendered = rewriteExportLinks(rendered, page, anchors)
+		rendered = embedImages(rendered, dir, page.rel)
+
+		body.WriteString(fmt.Sprintf("<section class=\"page\" id=\"%s\">\n%s</section>\n", page.anchor, rendered))
+	}
+
+	escapedTitle := html.EscapeString(title)
+	header := fmt.Sprintf("<h1>%s</h1>\n<nav class=\"toc\">\n<ul>\n%s</ul>\n</nav>", escapedTitle, toc.String())
+	return writePage(w, escapedTitle, header, body.String())
+}
+
+// ExportZip writes every file under dir to a zip archive
+func ExportZip(dir string, w io.Writer) error {
+	archive := zip.NewWriter(w)
+
+	err := filepath.Walk(dir, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, file)
+		if err != nil {
+			return err
+		}
+
+		header, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(rel)
+		header.Method = zip.Deflate
+
+		entry, err := archive.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		_, err = io.Copy(entry, f)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to archive %s: %w", dir, err)
+	}
+
+	return archive.Close()
+}
+
+// markdownPages lists the markdown files under dir. Index pages come first,
+// then the rest in path order.
+func markdownPages(dir string) ([]exportPage, error) {
+	var rels []string
+	err := filepath.Walk(dir, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && strings.HasSuffix(file, ".md") {
+			rel, err := filepath.Rel(dir, file)
+			if err != nil {
+				return err
+			}
+			rels = append(rels, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+
+	sort.Slice(rels, func(i, j int) bool {
+		ri, rj := pageRank(rels[i]), pageRank(rels[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return rels[i] < rels[j]
+	})
+
+	pages := make([]exportPage, len(rels))
+	for i, rel := range rels {
+		pages[i] = exportPage{
+			rel:    rel,
+			anchor: "page-" + slug(strings.NewReplacer("/", "-", ".", "-").Replace(strings.TrimSuffix(rel, ".md"))),
+		}
+	}
+	return pages, nil
+}
+
+// pageRank sorts top-level index pages before everything else
+func pageRank(rel string) int {
+	switch strings.ToLower(rel) {
+	case "index.md", "readme.md":
+		return 0
+	}
+	return 1
+}
+
+// rewriteExportLinks prefixes heading ids with the page anchor so they stay
+// unique, and points links to other pages at their section in the bundle
+func rewriteExportLinks(rendered string, page exportPage, anchors map[string]string) string {
+	rendered = idAttrPattern.ReplaceAllString(rendered, `id="`+page.anchor+`--$1"`)
+
+	return hrefAttrPattern.ReplaceAllStringFunc(rendered, func(attr string) string {
+		target := html.UnescapeString(hrefAttrPattern.FindStringSubmatch(attr)[1])
+		if isExternalLink(target) {
+			return attr
+		}
+
+		file, fragment, _ := strings.Cut(target, "#")
+		anchor := page.anchor
+		if file != "" {
+			var ok bool
+			anchor, ok = anchors[path.Join(path.Dir(page.rel), file)]
+			if !ok {
+				return attr
+			}
+		}
+
+		if fragment != "" {
+			anchor += "--" + fragment
+		}
+		return `href="#` + html.EscapeString(anchor) + `"`
+	})
+}
+
+// embedImages replaces local image sources with data URIs so the bundle has
+// no external files
+func embedImages(rendered, dir, rel string) string {
+	return srcAttrPattern.ReplaceAllStringFunc(rendered, func(attr string) string {
+		target := html.UnescapeString(srcAttrPattern.FindStringSubmatch(attr)[1])
+		if isExternalLink(target) {
+			return attr
+		}
+
+		file := path.Join(path.Dir(rel), target)
+		if strings.HasPrefix(file, "../") {
+			return attr // outside the docs tree
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(file)))
+		if err != nil {
+			return attr
+		}
+
+		mimeType := mime.TypeByExtension(path.Ext(file))
+		if mimeType == "" {
+			mimeType = "application/octet-stream"
+		}
+		return `src="data:` + mimeType + `;base64,` + base64.StdEncoding.EncodeToString(data) + `"`
+	})
+}
+
+// isExternalLink reports whether a link target leaves the docs tree
+func isExternalLink(target string) bool {
+	return strings.Contains(target, "://") || strings.HasPrefix(target, "mailto:") ||
+		strings.HasPrefix(target, "data:") || strings.HasPrefix(target, "/")
+}
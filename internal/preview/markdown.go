@@ -0,0 +1,217 @@
+package preview
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var (
+	headingPattern     = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	orderedItemPattern = regexp.MustCompile(`^\d+[.)]\s+`)
+	tableDividerRow    = regexp.MustCompile(`^\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?$`)
+
+	imagePattern  = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]+)\)`)
+	linkPattern   = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	boldPattern   = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	italicPattern = regexp.MustCompile(`\*([^*]+)\*|\b_([^_]+)_\b`)
+)
+
+// RenderMarkdown converts markdown to HTML. It covers the subset of markdown
+// DocBrown generates: headings, paragraphs, fenced code, lists, blockquotes,
+// tables, rules and inline formatting.
+func RenderMarkdown(source string) string {
+	var sb strings.Builder
+
+	lines := strings.Split(strings.ReplaceAll(source, "\r\n", "\n"), "\n")
+	var paragraph []string
+	listTag := ""
+
+	flushParagraph := func() {
+		if len(paragraph) > 0 {
+			sb.WriteString("<p>" + renderInline(strings.Join(paragraph, " ")) + "</p>\n")
+			paragraph = nil
+		}
+	}
+	closeList := func() {
+		if listTag != "" {
+			sb.WriteString("</" + listTag + ">\n")
+			listTag = ""
+		}
+	}
+	openList := func(tag string) {
+		if listTag != tag {
+			closeList()
+			sb.WriteString("<" + tag + ">\n")
+			listTag = tag
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			flushParagraph()
+			closeList()
+
+			fence := trimmed[:3]
+			lang := strings.TrimSpace(strings.TrimLeft(trimmed, fence[:1]))
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), fence); i++ {
+				code = append(code, lines[i])
+			}
+
+			class := ""
+			if lang != "" {
+				class = fmt.Sprintf(` class="language-%s"`, html.EscapeString(lang))
+			}
+			sb.WriteString(fmt.Sprintf("<pre><code%s>%s</code></pre>\n", class, html.EscapeString(strings.Join(code, "\n"))))
+
+		case trimmed == "":
+			flushParagraph()
+			closeList()
+
+		case headingPattern.MatchString(trimmed):
+			flushParagraph()
+			closeList()
+
+			match := headingPattern.FindStringSubmatch(trimmed)
+			level := len(match[1])
+			sb.WriteString(fmt.Sprintf("<h%d id=\"%s\">%s</h%d>\n", level, slug(match[2]), renderInline(match[2]), level))
+
+		case trimmed == "---" || trimmed == "***" || trimmed == "___":
+			flushParagraph()
+			closeList()
+			sb.WriteString("<hr>\n")
+
+		case strings.HasPrefix(trimmed, ">"):
+			flushParagraph()
+			closeList()
+			sb.WriteString("<blockquote>" + renderInline(strings.TrimSpace(strings.TrimPrefix(trimmed, ">"))) + "</blockquote>\n")
+
+		case strings.HasPrefix(trimmed, "- ") || strings.HasPrefix(trimmed, "* ") || strings.HasPrefix(trimmed, "+ "):
+			flushParagraph()
+			openList("ul")
+			sb.WriteString("<li>" + renderInline(strings.TrimSpace(trimmed[2:])) + "</li>\n")
+
+		case orderedItemPattern.MatchString(trimmed):
+			flushParagraph()
+			openList("ol")
+			sb.WriteString("<li>" + renderInline(orderedItemPattern.ReplaceAllString(trimmed, "")) + "</li>\n")
+
+		case strings.HasPrefix(trimmed, "|") && i+1 < len(lines) && tableDividerRow.MatchString(strings.TrimSpace(lines[i+1])):
+			flushParagraph()
+			closeList()
+
+			sb.WriteString("<table>\n<thead><tr>")
+			for _, cell := range tableCells(trimmed) {
+				sb.WriteString("<th>" + renderInline(cell) + "</th>")
+			}
+			sb.WriteString("</tr></thead>\n<tbody>\n")
+
+			for i += 2; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), "|"); i++ {
+				sb.WriteString("<tr>")
+				for _, cell := range tableCells(strings.TrimSpace(lines[i])) {
+					sb.WriteString("<td>" + renderInline(cell) + "</td>")
+				}
+				sb.WriteString("</tr>\n")
+			}
+			i--
+
+			sb.WriteString("</tbody>\n</table>\n")
+
+		default:
+			closeList()
+			paragraph = append(paragraph, trimmed)
+		}
+	}
+
+	flushParagraph()
+	closeList()
+
+	return sb.String()
+}
+
+// tableCells splits a markdown table row into cells
+func tableCells(row string) []string {
+	row = strings.TrimSuffix(strings.TrimPrefix(row, "|"), "|")
+	cells := strings.Split(row, "|")
+	for i := range cells {
+		cells[i] = strings.TrimSpace(cells[i])
+	}
+	return cells
+}
+
+// renderInline renders inline code, images, links, bold and italic text.
+// Code spans are rendered first so their contents are left untouched.
+func renderInline(text string) string {
+	var sb strings.Builder
+
+	parts := strings.Split(text, "`")
+	for i, part := range parts {
+		if i%2 == 1 && i < len(parts)-1 {
+			sb.WriteString("<code>" + html.EscapeString(part) + "</code>")
+			continue
+		}
+		if i%2 == 1 {
+			part = "`" + part // Unmatched backtick
+		}
+
+		escaped := html.EscapeString(part)
+		escaped = imagePattern.ReplaceAllStringFunc(escaped, func(m string) string {
+			match := imagePattern.FindStringSubmatch(m)
+			return fmt.Sprintf(`<img src="%s" alt="%s">`, safeLink(match[2]), match[1])
+		})
+		escaped = linkPattern.ReplaceAllStringFunc(escaped, func(m string) string {
+			match := linkPattern.FindStringSubmatch(m)
+			return fmt.Sprintf(`<a href="%s">%s</a>`, safeLink(match[2]), match[1])
+		})
+		escaped = boldPattern.ReplaceAllString(escaped, "<strong>$1$2</strong>")
+		escaped = italicPattern.ReplaceAllString(escaped, "<em>$1$2</em>")
+
+		sb.WriteString(escaped)
+	}
+
+	return sb.String()
+}
+
+// safeSchemes are the URL schemes links and images may use; anything else,
+// such as javascript: or data:, is replaced
+var safeSchemes = map[string]bool{"http": true, "https": true, "mailto": true}
+
+// safeLink returns a link target if it is relative, a #fragment or uses a
+// safe scheme, and "#" otherwise. Schemes are matched case-insensitively,
+// as browsers do.
+func safeLink(target string) string {
+	target = strings.TrimSpace(target)
+
+	// A colon before any /, ? or # ends a scheme; otherwise the target is
+	// a relative path (which may still contain colons, e.g. a/b:c)
+	end := strings.IndexAny(target, ":/?#")
+	if end < 0 || target[end] != ':' {
+		return target
+	}
+
+	if safeSchemes[strings.ToLower(target[:end])] {
+		return target
+	}
+	return "#"
+}
+
+// slug converts heading text to a GitHub-style anchor
+func slug(text string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_':
+			sb.WriteRune(r)
+		case r == ' ':
+			sb.WriteRune('-')
+		}
+	}
+	return sb.String()
+}
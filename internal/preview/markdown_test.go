@@ -0,0 +1,46 @@
+package preview
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSafeLink(t *testing.T) {
+	tests := []struct {
+		target string
+		want   string
+	}{
+		{"https://example.com/a", "https://example.com/a"},
+		{"HTTP://example.com", "HTTP://example.com"},
+		{"mailto:team@example.com", "mailto:team@example.com"},
+		{"components/api.md", "components/api.md"},
+		{"../guides/setup.md#install", "../guides/setup.md#install"},
+		{"#overview", "#overview"},
+		{"/docs/index.md", "/docs/index.md"},
+		{"a/b:c.md", "a/b:c.md"},
+		{"  https://example.com  ", "https://example.com"},
+		{"javascript:alert(1)", "#"},
+		{"JavaScript:alert(1)", "#"},
+		{"  javascript:alert(1)", "#"},
+		{"java\tscript:alert(1)", "#"},
+		{"vbscript:msgbox(1)", "#"},
+		{"data:text/html;base64,PHNjcmlwdD4=", "#"},
+		{":nothing", "#"},
+	}
+
+	for _, tt := range tests {
+		if got := safeLink(tt.target); got != tt.want {
+			t.Errorf("safeLink(%q) = %q, want %q", tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestRenderMarkdownLinks(t *testing.T) {
+	got := RenderMarkdown("[ok](api.md) [bad](JAVASCRIPT:alert(1)) ![img](Data:image/png;base64,AAAA)")
+
+	for _, want := range []string{`<a href="api.md">ok</a>`, `<a href="#">bad</a>`, `<img src="#" alt="img">`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("rendered %q, missing %q", got, want)
+		}
+	}
+}
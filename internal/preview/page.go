@@ -0,0 +1,40 @@
+package preview
+
+import (
+	"fmt"
+	"io"
+)
+
+// pageTemplate wraps rendered markdown in a minimal, readable page. It is
+// shared by the preview server and the single-page export.
+const pageTemplate = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>%s</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; max-width: 900px; margin: 2rem auto; padding: 0 1rem; line-height: 1.6; color: #24292f; }
+pre { background: #f6f8fa; padding: 1rem; overflow: auto; border-radius: 6px; }
+code { background: #f6f8fa; padding: 0.1em 0.3em; border-radius: 4px; }
+pre code { padding: 0; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #d0d7de; padding: 0.4em 0.8em; }
+blockquote { color: #57606a; border-left: 4px solid #d0d7de; margin: 0; padding: 0 1em; }
+img { max-width: 100%%; }
+nav.breadcrumbs { font-size: 0.9em; margin-bottom: 1rem; }
+nav.toc { border-bottom: 1px solid #d0d7de; padding-bottom: 1rem; }
+section.page { border-bottom: 1px solid #d0d7de; padding: 1rem 0 2rem; }
+</style>
+</head>
+<body>
+%s
+%s
+</body>
+</html>
+`
+
+// writePage writes a page with the given escaped title, header and body HTML
+func writePage(w io.Writer, title, header, body string) error {
+	_, err := fmt.Fprintf(w, pageTemplate, title, header, body)
+	return err
+}
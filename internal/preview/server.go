@@ -0,0 +1,108 @@
+package preview
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Server serves a documentation directory, rendering markdown to HTML
+type Server struct {
+	root string
+}
+
+// NewServer creates a preview server rooted at dir
+func NewServer(dir string) *Server {
+	return &Server{root: dir}
+}
+
+// ServeHTTP renders markdown files and directory indexes; other files are
+// served as-is
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// path.Clean on a rooted path removes any ".." that would escape the root
+	urlPath := path.Clean("/" + r.URL.Path)
+	fsPath := filepath.Join(s.root, filepath.FromSlash(urlPath))
+
+	info, err := os.Stat(fsPath)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	if info.IsDir() {
+		// Links within a directory are relative, so it must end in a slash
+		if !strings.HasSuffix(r.URL.Path, "/") {
+			http.Redirect(w, r, r.URL.Path+"/", http.StatusMovedPermanently)
+			return
+		}
+
+		index := filepath.Join(fsPath, "index.md")
+		if _, err := os.Stat(index); err == nil {
+			s.serveMarkdown(w, index, urlPath)
+			return
+		}
+
+		s.serveListing(w, fsPath, urlPath)
+		return
+	}
+
+	if strings.HasSuffix(fsPath, ".md") {
+		s.serveMarkdown(w, fsPath, urlPath)
+		return
+	}
+
+	http.ServeFile(w, r, fsPath)
+}
+
+// serveMarkdown renders a markdown file as an HTML page
+func (s *Server) serveMarkdown(w http.ResponseWriter, file, urlPath string) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	writePage(w, html.EscapeString(filepath.Base(file)), breadcrumbs(urlPath), RenderMarkdown(string(content)))
+}
+
+// serveListing renders a list of a directory's markdown files and subdirectories
+func (s *Server) serveListing(w http.ResponseWriter, dir, urlPath string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var names []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() {
+			names = append(names, name+"/")
+		} else if strings.HasSuffix(name, ".md") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("<h1>%s</h1>\n<ul>\n", html.EscapeString(urlPath)))
+	for _, name := range names {
+		escaped := html.EscapeString(name)
+		sb.WriteString(fmt.Sprintf("<li><a href=\"%s\">%s</a></li>\n", escaped, escaped))
+	}
+	sb.WriteString("</ul>\n")
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	writePage(w, html.EscapeString(urlPath), breadcrumbs(urlPath), sb.String())
+}
+
+// breadcrumbs returns the navigation shown above a served page
+func breadcrumbs(urlPath string) string {
+	return `<nav class="breadcrumbs"><a href="/">Home</a> · ` + html.EscapeString(urlPath) + `</nav>`
+}
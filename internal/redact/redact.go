@@ -0,0 +1,113 @@
+package redact
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+)
+
+// Placeholder replaces each detected secret
+const Placeholder = "<REDACTED>"
+
+// secretGroup is the capture group name that limits redaction to part of a
+// match, e.g. the value of a "password = ..." assignment
+const secretGroup = "secret"
+
+// DefaultPatterns detect common secret formats
+var DefaultPatterns = []string{
+	// Private key blocks (PEM)
+	`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`,
+	// AWS access key IDs
+	`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`,
+	// GitHub, Slack, Anthropic and OpenAI tokens
+	`\bgh[pousr]_[A-Za-z0-9]{36,}\b`,
+	`\bxox[abprs]-[A-Za-z0-9-]{10,}`,
+	`\bsk-ant-[A-Za-z0-9_-]{20,}`,
+	`\bsk-[A-Za-z0-9]{20,}\b`,
+	// Quoted values assigned to password, secret, token and key names
+	`(?i)(?:password|passwd|pwd|secret|api[_-]?key|access[_-]?key|auth[_-]?token|access[_-]?token|client[_-]?secret|private[_-]?key)["']?\s*[:=]\s*["'](?P<secret>[^"'\s]{4,})["']`,
+}
+
+// highEntropyPattern finds quoted strings long enough to be generated tokens
+var highEntropyPattern = regexp.MustCompile(`["'](?P<secret>[A-Za-z0-9+/=_-]{32,})["']`)
+
+// minTokenEntropy is the Shannon entropy (bits per character) above which a
+// long quoted string is treated as a secret. Hex digests stay below it.
+const minTokenEntropy = 4.5
+
+// Redactor replaces secrets in file contents with a placeholder
+type Redactor struct {
+	patterns []*regexp.Regexp
+}
+
+// NewRedactor creates a redactor using the default patterns plus any extra
+// patterns. A pattern with a (?P<secret>...) group only redacts that group.
+func NewRedactor(extra []string) (*Redactor, error) {
+	r := &Redactor{}
+
+	for _, pattern := range append(append([]string{}, DefaultPatterns...), extra...) {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", pattern, err)
+		}
+		r.patterns = append(r.patterns, re)
+	}
+
+	return r, nil
+}
+
+// Redact replaces detected secrets in content and returns how many were found
+func (r *Redactor) Redact(content string) (string, int) {
+	count := 0
+
+	for _, re := range r.patterns {
+		content = replaceSecrets(re, content, func(string) bool { return true }, &count)
+	}
+	content = replaceSecrets(highEntropyPattern, content, isHighEntropy, &count)
+
+	return content, count
+}
+
+// replaceSecrets redacts matches of re accepted by keep, limiting the
+// replacement to the "secret" group when the pattern has one
+func replaceSecrets(re *regexp.Regexp, content string, keep func(string) bool, count *int) string {
+	group := re.SubexpIndex(secretGroup)
+
+	return re.ReplaceAllStringFunc(content, func(match string) string {
+		if group < 0 {
+			*count++
+			return Placeholder
+		}
+
+		// Re-match to locate the group within this match
+		loc := re.FindStringSubmatchIndex(match)
+		if loc == nil || loc[2*group] < 0 {
+			return match
+		}
+
+		start, end := loc[2*group], loc[2*group+1]
+		if match[start:end] == Placeholder || !keep(match[start:end]) {
+			return match
+		}
+
+		*count++
+		return match[:start] + Placeholder + match[end:]
+	})
+}
+
+// isHighEntropy reports whether s looks randomly generated
+func isHighEntropy(s string) bool {
+	freq := make(map[rune]float64)
+	for _, c := range s {
+		freq[c]++
+	}
+
+	entropy := 0.0
+	n := float64(len(s))
+	for _, f := range freq {
+		p := f / n
+		entropy -= p * math.Log2(p)
+	}
+
+	return entropy >= minTokenEntropy
+}
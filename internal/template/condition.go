@@ -0,0 +1,214 @@
+package template
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// evaluateCondition evaluates a template file condition against data.
+// Supported expressions:
+//
+//	HasTests                 field is truthy (non-zero, non-empty)
+//	!HasTests                field is falsy
+//	Type == "service"        comparison with a string, number or bool
+//	len(APIs) > 0            comparison on the length of a list, map or string
+//	HasTests && len(APIs)>0  conditions joined with && and ||
+//
+// An empty condition is always true.
+func evaluateCondition(condition string, data interface{}) (bool, error) {
+	condition = strings.TrimSpace(condition)
+	if condition == "" {
+		return true, nil
+	}
+
+	// || binds looser than &&. Empty terms are rejected up front, so a
+	// dangling operator is an error even when evaluation short-circuits.
+	var alts [][]string
+	for _, alt := range strings.Split(condition, "||") {
+		terms := strings.Split(alt, "&&")
+		for i := range terms {
+			terms[i] = strings.TrimSpace(terms[i])
+			if terms[i] == "" {
+				return false, fmt.Errorf("empty expression")
+			}
+		}
+		alts = append(alts, terms)
+	}
+
+	for _, terms := range alts {
+		matched := true
+		for _, term := range terms {
+			ok, err := evaluateTerm(term, data)
+			if err != nil {
+				return false, err
+			}
+			if !ok {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// conditionOperators are checked longest first so ">=" isn't read as ">"
+var conditionOperators = []string{"==", "!=", ">=", "<=", ">", "<"}
+
+// evaluateTerm evaluates a single comparison or truthiness check
+func evaluateTerm(term string, data interface{}) (bool, error) {
+	if term == "" {
+		return false, fmt.Errorf("empty expression")
+	}
+
+	for _, op := range conditionOperators {
+		idx := strings.Index(term, op)
+		if idx < 0 {
+			continue
+		}
+
+		left, err := resolveOperand(strings.TrimSpace(term[:idx]), data)
+		if err != nil {
+			return false, err
+		}
+		right, err := resolveOperand(strings.TrimSpace(term[idx+len(op):]), data)
+		if err != nil {
+			return false, err
+		}
+
+		return compareValues(left, right, op)
+	}
+
+	if strings.HasPrefix(term, "!") {
+		ok, err := evaluateTerm(strings.TrimSpace(term[1:]), data)
+		return !ok, err
+	}
+
+	value, err := resolveOperand(term, data)
+	if err != nil {
+		return false, err
+	}
+	return isTruthy(value), nil
+}
+
+// resolveOperand resolves a literal, len(Field) call or field reference
+func resolveOperand(operand string, data interface{}) (interface{}, error) {
+	switch {
+	case operand == "":
+		return nil, fmt.Errorf("missing operand")
+	case operand == "true":
+		return true, nil
+	case operand == "false":
+		return false, nil
+	case strings.HasPrefix(operand, `"`) || strings.HasPrefix(operand, "'"):
+		if len(operand) < 2 || operand[len(operand)-1] != operand[0] {
+			return nil, fmt.Errorf("unterminated string: %s", operand)
+		}
+		return operand[1 : len(operand)-1], nil
+	case strings.HasPrefix(operand, "len(") && strings.HasSuffix(operand, ")"):
+		value, err := lookupField(operand[len("len("):len(operand)-1], data)
+		if err != nil {
+			return nil, err
+		}
+		switch value.Kind() {
+		case reflect.Slice, reflect.Array, reflect.Map, reflect.String:
+			return float64(value.Len()), nil
+		}
+		return nil, fmt.Errorf("len of non-collection: %s", operand)
+	}
+
+	if n, err := strconv.ParseFloat(operand, 64); err == nil {
+		return n, nil
+	}
+
+	value, err := lookupField(operand, data)
+	if err != nil {
+		return nil, err
+	}
+
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), nil
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), nil
+	}
+	return value.Interface(), nil
+}
+
+// lookupField resolves a (possibly dotted) field path such as Architecture.Overview
+func lookupField(path string, data interface{}) (reflect.Value, error) {
+	value := reflect.ValueOf(data)
+
+	for _, name := range strings.Split(strings.TrimPrefix(strings.TrimSpace(path), "."), ".") {
+		for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+			value = value.Elem()
+		}
+
+		switch value.Kind() {
+		case reflect.Struct:
+			value = value.FieldByName(name)
+		case reflect.Map:
+			value = value.MapIndex(reflect.ValueOf(name))
+		default:
+			value = reflect.Value{}
+		}
+
+		if !value.IsValid() {
+			return reflect.Value{}, fmt.Errorf("unknown field: %s", path)
+		}
+	}
+
+	return value, nil
+}
+
+// compareValues compares two resolved operands
+func compareValues(left, right interface{}, op string) (bool, error) {
+	if l, ok := left.(float64); ok {
+		r, ok := right.(float64)
+		if !ok {
+			return false, fmt.Errorf("cannot compare number with %v", right)
+		}
+		switch op {
+		case "==":
+			return l == r, nil
+		case "!=":
+			return l != r, nil
+		case ">":
+			return l > r, nil
+		case "<":
+			return l < r, nil
+		case ">=":
+			return l >= r, nil
+		case "<=":
+			return l <= r, nil
+		}
+	}
+
+	switch op {
+	case "==":
+		return reflect.DeepEqual(left, right), nil
+	case "!=":
+		return !reflect.DeepEqual(left, right), nil
+	}
+
+	return false, fmt.Errorf("operator %s requires numbers", op)
+}
+
+// isTruthy reports whether a value is non-zero and non-empty
+func isTruthy(value interface{}) bool {
+	if value == nil {
+		return false
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.String:
+		return v.Len() > 0
+	}
+	return !v.IsZero()
+}
@@ -0,0 +1,58 @@
+package template
+
+import "testing"
+
+func TestEvaluateCondition(t *testing.T) {
+	comp := ComponentData{
+		Name:          "api",
+		Type:          "service",
+		HasTests:      true,
+		APIs:          []APIData{{Method: "GET", Path: "/"}},
+		Configuration: map[string]string{"PORT": "8080"},
+	}
+
+	tests := []struct {
+		condition string
+		want      bool
+	}{
+		{"", true},
+		{"HasTests", true},
+		{"!HasTests", false},
+		{"Description", false},
+		{`Type == "service"`, true},
+		{`Type != 'service'`, false},
+		{"len(APIs) > 0", true},
+		{"len(APIs)>=2", false},
+		{"len(Configuration) == 1", true},
+		{`HasTests && Type == "library"`, false},
+		{`Type == "library" || len(APIs) > 0`, true},
+		{"HasTests == true", true},
+	}
+
+	for _, tt := range tests {
+		got, err := evaluateCondition(tt.condition, comp)
+		if err != nil {
+			t.Errorf("%q: %v", tt.condition, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%q = %v, want %v", tt.condition, got, tt.want)
+		}
+	}
+}
+
+func TestEvaluateConditionErrors(t *testing.T) {
+	for _, condition := range []string{
+		"Missing",
+		"HasTests &&",
+		`Type == "service`,
+		"len(Name.Foo) > 0",
+		"len(HasTests) > 0",
+		`Type > "a"`,
+		"len(APIs) > true",
+	} {
+		if _, err := evaluateCondition(condition, ComponentData{}); err == nil {
+			t.Errorf("%q: expected an error", condition)
+		}
+	}
+}
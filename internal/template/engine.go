@@ -1,41 +1,114 @@
 package template
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
+	"sync"
 	"text/template"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/docbrown/cli/internal/fileutil"
+	"github.com/docbrown/cli/templates"
 )
 
-// Engine handles template loading and rendering
+// BuiltinLocation is reported as the location of templates built into the binary
+const BuiltinLocation = "built-in"
+
+// Engine handles template loading and rendering. It is safe for concurrent
+// use: components' pages are rendered in parallel as each one finishes.
 type Engine struct {
-	templatePath string
-	templates    map[string]*template.Template
+	roots []templateRoot // searched in order
+
+	mu        sync.RWMutex // guards templates
+	templates map[string]*template.Template
+}
+
+// templateRoot is a directory of templates, one subdirectory per template
+type templateRoot struct {
+	location string // directory path, or BuiltinLocation
+	fsys     fs.FS
 }
 
-// NewEngine creates a new template engine
-func NewEngine(templatePath string) *Engine {
+// NewEngine creates a new template engine. Templates are looked up in each
+// of templatePaths in order, then in the user template directory, then
+// among the templates built into the binary.
+func NewEngine(templatePaths ...string) *Engine {
+	var roots []templateRoot
+	for _, dir := range templatePaths {
+		if dir != "" {
+			roots = append(roots, dirRoot(dir))
+		}
+	}
+	if userDir, err := UserDir(); err == nil {
+		roots = append(roots, dirRoot(userDir))
+	}
+	roots = append(roots, templateRoot{location: BuiltinLocation, fsys: templates.FS})
+
+	return newEngine(roots...)
+}
+
+// newEngine creates an engine searching exactly the given roots
+func newEngine(roots ...templateRoot) *Engine {
 	return &Engine{
-		templatePath: templatePath,
-		templates:    make(map[string]*template.Template),
+		roots:     roots,
+		templates: make(map[string]*template.Template),
 	}
 }
 
+// dirRoot returns a template root for a directory on disk
+func dirRoot(dir string) templateRoot {
+	return templateRoot{location: dir, fsys: os.DirFS(dir)}
+}
+
+// findTemplate returns the files of the named template and where they
+// are, from the first root that has it
+func (e *Engine) findTemplate(name string) (fs.FS, string, error) {
+	if !fs.ValidPath(name) || strings.Contains(name, "/") {
+		return nil, "", fmt.Errorf("invalid template name: %s", name)
+	}
+
+	for _, root := range e.roots {
+		if _, err := fs.Stat(root.fsys, path.Join(name, "template.yaml")); err != nil {
+			continue
+		}
+		fsys, err := fs.Sub(root.fsys, name)
+		if err != nil {
+			return nil, "", err
+		}
+		location := root.location
+		if location != BuiltinLocation {
+			location = filepath.Join(location, name)
+		}
+		return fsys, location, nil
+	}
+
+	return nil, "", fmt.Errorf("template not found: %s (looked in %s)", name, e.describeRoots())
+}
+
+// describeRoots lists the template roots for error messages
+func (e *Engine) describeRoots() string {
+	locations := make([]string, len(e.roots))
+	for i, root := range e.roots {
+		locations[i] = root.location
+	}
+	return strings.Join(locations, ", ")
+}
+
 // LoadTemplate loads a template by name
 func (e *Engine) LoadTemplate(name string) (*Template, error) {
-	templateDir := filepath.Join(e.templatePath, name)
-
-	// Check if template exists
-	if _, err := os.Stat(templateDir); os.IsNotExist(err) {
-		return nil, fmt.Errorf("template not found: %s", name)
+	fsys, location, err := e.findTemplate(name)
+	if err != nil {
+		return nil, err
 	}
 
 	// Load template.yaml
-	configPath := filepath.Join(templateDir, "template.yaml")
-	data, err := os.ReadFile(configPath)
+	data, err := fs.ReadFile(fsys, "template.yaml")
 	if err != nil {
 		return nil, fmt.Errorf("failed to read template config: %w", err)
 	}
@@ -45,27 +118,87 @@ func (e *Engine) LoadTemplate(name string) (*Template, error) {
 		return nil, fmt.Errorf("failed to parse template config: %w", err)
 	}
 
-	tmpl.Path = templateDir
+	tmpl.Path = location
 
-	// Load template files
+	// Load template files. They are parsed before taking the lock so
+	// rendering isn't held up, and a template that fails to parse leaves
+	// the loaded ones untouched.
+	parsed := make(map[string]*template.Template, len(tmpl.Files))
 	for i := range tmpl.Files {
 		file := &tmpl.Files[i]
-		tmplPath := filepath.Join(templateDir, file.Template)
 
-		t, err := template.ParseFiles(tmplPath)
+		t, err := parseTemplateFile(fsys, file.Template)
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse template file %s: %w", file.Template, err)
 		}
 
-		e.templates[file.Name] = t
+		if err := parsePartials(t, fsys); err != nil {
+			return nil, err
+		}
+
+		parsed[file.Name] = t
+	}
+
+	e.mu.Lock()
+	for name, t := range parsed {
+		e.templates[name] = t
 	}
+	e.mu.Unlock()
 
 	return &tmpl, nil
 }
 
-// Render renders a template with the given data
+// parseTemplateFile parses one of a template's files
+func parseTemplateFile(fsys fs.FS, name string) (*template.Template, error) {
+	name = filepath.ToSlash(name)
+	content, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	return template.New(path.Base(name)).Funcs(funcMap).Parse(string(content))
+}
+
+// partialsDir holds templates shared by every file of a template
+const partialsDir = "partials"
+
+// parsePartials adds the template's partials to t. Each partial is named
+// after its file without extensions, so partials/header.md.tmpl is included
+// with {{template "header" .}}; partial files may also {{define}} more.
+func parsePartials(t *template.Template, fsys fs.FS) error {
+	files, err := fs.Glob(fsys, path.Join(partialsDir, "*"))
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		if info, err := fs.Stat(fsys, file); err == nil && info.IsDir() {
+			continue
+		}
+
+		content, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return fmt.Errorf("failed to read partial %s: %w", path.Base(file), err)
+		}
+
+		name := path.Base(file)
+		if i := strings.Index(name, "."); i > 0 {
+			name = name[:i]
+		}
+
+		if _, err := t.New(name).Parse(string(content)); err != nil {
+			return fmt.Errorf("failed to parse partial %s: %w", path.Base(file), err)
+		}
+	}
+
+	return nil
+}
+
+// Render renders a template with the given data. Parsed templates may be
+// executed concurrently, so no per-call copy is needed.
 func (e *Engine) Render(templateName string, data interface{}) (string, error) {
+	e.mu.RLock()
 	tmpl, ok := e.templates[templateName]
+	e.mu.RUnlock()
 	if !ok {
 		return "", fmt.Errorf("template not loaded: %s", templateName)
 	}
@@ -78,21 +211,40 @@ func (e *Engine) Render(templateName string, data interface{}) (string, error) {
 	return buf.String(), nil
 }
 
-// RenderToFile renders a template to a file
+// RenderText parses and renders a one-off template, such as a PR body from
+// config, with the same helper functions as documentation templates
+func RenderText(name, text string, data interface{}) (string, error) {
+	tmpl, err := template.New(name).Funcs(funcMap).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render %s: %w", name, err)
+	}
+
+	return buf.String(), nil
+}
+
+// RenderToFile renders a template to a file. The file is replaced
+// atomically, so concurrent renders of the same path never interleave; the
+// last one to finish wins.
 func (e *Engine) RenderToFile(templateName string, data interface{}, outputPath string) error {
 	content, err := e.Render(templateName, data)
 	if err != nil {
 		return err
 	}
 
-	// Ensure directory exists
+	// Ensure directory exists. MkdirAll tolerates other goroutines
+	// creating the same directories at the same time.
 	dir := filepath.Dir(outputPath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return fmt.Errorf("failed to create directory: %w", err)
 	}
 
 	// Write file
-	if err := os.WriteFile(outputPath, []byte(content), 0644); err != nil {
+	if err := fileutil.WriteFileAtomic(outputPath, []byte(content), 0644); err != nil {
 		return fmt.Errorf("failed to write file: %w", err)
 	}
 
@@ -104,41 +256,121 @@ func (e *Engine) RenderAll(tmpl *Template, data TemplateData, outputDir string)
 	var generatedFiles []string
 
 	for _, file := range tmpl.Files {
-		// Determine output path
-		outputPath := file.Output
+		files, err := e.renderFile(file, data, outputDir)
+		generatedFiles = append(generatedFiles, files...)
+		if err != nil {
+			return generatedFiles, err
+		}
+	}
+
+	return generatedFiles, nil
+}
+
+// RenderComponents renders only the per-component files of a template, for
+// the components in data. It lets each component's pages be written as soon
+// as that component is done.
+func (e *Engine) RenderComponents(tmpl *Template, data TemplateData, outputDir string) ([]string, error) {
+	var generatedFiles []string
+
+	for _, file := range tmpl.Files {
+		if file.Foreach != "components" {
+			continue
+		}
+
+		files, err := e.renderFile(file, data, outputDir)
+		generatedFiles = append(generatedFiles, files...)
+		if err != nil {
+			return generatedFiles, err
+		}
+	}
+
+	return generatedFiles, nil
+}
+
+// RenderRepository renders the files of a template that describe the whole
+// repository, i.e. all but the per-component ones. data should hold every
+// component, even when only some were regenerated.
+func (e *Engine) RenderRepository(tmpl *Template, data TemplateData, outputDir string) ([]string, error) {
+	var generatedFiles []string
+
+	for _, file := range tmpl.Files {
+		if file.Foreach == "components" {
+			continue
+		}
 
-		// Handle template variables in output path
-		if strings.Contains(outputPath, "{{") {
-			outputPath = e.expandPath(outputPath, data)
+		files, err := e.renderFile(file, data, outputDir)
+		generatedFiles = append(generatedFiles, files...)
+		if err != nil {
+			return generatedFiles, err
 		}
+	}
 
-		fullPath := filepath.Join(outputDir, outputPath)
+	return generatedFiles, nil
+}
+
+// renderFile renders one template file, once or for each of its foreach items
+func (e *Engine) renderFile(file TemplateFile, data TemplateData, outputDir string) ([]string, error) {
+	var generatedFiles []string
 
-		// Check if this is a foreach template
-		if file.Foreach != "" {
-			// Render multiple times for each item
-			items := e.getForEachItems(file.Foreach, data)
-			for _, item := range items {
-				itemPath := e.expandPath(outputPath, item)
-				fullItemPath := filepath.Join(outputDir, itemPath)
+	// Determine output path
+	outputPath := file.Output
 
-				if err := e.RenderToFile(file.Name, item, fullItemPath); err != nil {
-					return generatedFiles, err
-				}
+	// Handle template variables in output path
+	if strings.Contains(outputPath, "{{") {
+		outputPath = e.expandPath(outputPath, data)
+	}
 
-				generatedFiles = append(generatedFiles, fullItemPath)
+	fullPath := resolveOutputPath(outputDir, outputPath)
+
+	// Check if this is a foreach template
+	if file.Foreach != "" {
+		// Render multiple times for each item
+		items := e.getForEachItems(file.Foreach, data)
+		for _, item := range items {
+			// Skip items that don't meet the file's condition
+			ok, err := evaluateCondition(file.Condition, item)
+			if err != nil {
+				return generatedFiles, fmt.Errorf("invalid condition for %s: %w", file.Name, err)
+			}
+			if !ok {
+				continue
 			}
-		} else {
-			// Render once
-			if err := e.RenderToFile(file.Name, data, fullPath); err != nil {
+
+			itemPath := e.expandPath(outputPath, item)
+			fullItemPath := resolveOutputPath(outputDir, itemPath)
+
+			if err := e.RenderToFile(file.Name, item, fullItemPath); err != nil {
 				return generatedFiles, err
 			}
 
-			generatedFiles = append(generatedFiles, fullPath)
+			generatedFiles = append(generatedFiles, fullItemPath)
 		}
+		return generatedFiles, nil
 	}
 
-	return generatedFiles, nil
+	ok, err := evaluateCondition(file.Condition, data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid condition for %s: %w", file.Name, err)
+	}
+	if !ok {
+		return nil, nil
+	}
+
+	// Render once
+	if err := e.RenderToFile(file.Name, data, fullPath); err != nil {
+		return nil, err
+	}
+
+	return []string{fullPath}, nil
+}
+
+// resolveOutputPath places a template output path under outputDir. Absolute
+// output paths are used as-is rather than being nested under outputDir.
+func resolveOutputPath(outputDir, outputPath string) string {
+	if filepath.IsAbs(outputPath) {
+		return filepath.Clean(outputPath)
+	}
+	return filepath.Join(outputDir, outputPath)
 }
 
 // expandPath expands template variables in a path
@@ -150,14 +382,14 @@ func (e *Engine) expandPath(path string, data interface{}) string {
 	if comp, ok := data.(ComponentData); ok {
 		result = strings.ReplaceAll(result, "{{.Name}}", comp.Name)
 		result = strings.ReplaceAll(result, "{{.ComponentName}}", comp.Name)
-		return result
+		return e.executePath(result, data)
 	}
 
 	// Handle ServiceData directly
 	if svc, ok := data.(ServiceData); ok {
 		result = strings.ReplaceAll(result, "{{.Name}}", svc.Name)
 		result = strings.ReplaceAll(result, "{{.ServiceName}}", svc.Name)
-		return result
+		return e.executePath(result, data)
 	}
 
 	// Extract data as map (fallback)
@@ -170,7 +402,27 @@ func (e *Engine) expandPath(path string, data interface{}) string {
 		}
 	}
 
-	return result
+	return e.executePath(result, data)
+}
+
+// executePath renders any remaining template actions in a path, such as
+// {{.Name | slugify}}. The path is returned unchanged if it fails to render.
+func (e *Engine) executePath(path string, data interface{}) string {
+	if !strings.Contains(path, "{{") {
+		return path
+	}
+
+	t, err := template.New("path").Funcs(funcMap).Parse(path)
+	if err != nil {
+		return path
+	}
+
+	var buf strings.Builder
+	if err := t.Execute(&buf, data); err != nil {
+		return path
+	}
+
+	return buf.String()
 }
 
 // getForEachItems gets items for a foreach loop
@@ -196,22 +448,43 @@ func (e *Engine) getForEachItems(foreach string, data TemplateData) []interface{
 	return []interface{}{}
 }
 
-// ListTemplates lists available templates
-func (e *Engine) ListTemplates() ([]string, error) {
-	var templates []string
+// TemplateInfo describes an available template
+type TemplateInfo struct {
+	Name     string
+	Location string // directory, or BuiltinLocation
+}
 
-	entries, err := os.ReadDir(e.templatePath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read templates directory: %w", err)
-	}
+// ListTemplates lists available templates across the search roots. A
+// template in an earlier root hides one of the same name in a later root.
+// Roots that don't exist are skipped.
+func (e *Engine) ListTemplates() ([]TemplateInfo, error) {
+	var templates []TemplateInfo
+	seen := make(map[string]bool)
+
+	for _, root := range e.roots {
+		entries, err := fs.ReadDir(root.fsys, ".")
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read templates directory %s: %w", root.location, err)
+		}
 
-	for _, entry := range entries {
-		if entry.IsDir() {
+		for _, entry := range entries {
+			if !entry.IsDir() || seen[entry.Name()] {
+				continue
+			}
 			// Check if it has a template.yaml
-			configPath := filepath.Join(e.templatePath, entry.Name(), "template.yaml")
-			if _, err := os.Stat(configPath); err == nil {
-				templates = append(templates, entry.Name())
+			if _, err := fs.Stat(root.fsys, path.Join(entry.Name(), "template.yaml")); err != nil {
+				continue
+			}
+
+			location := root.location
+			if location != BuiltinLocation {
+				location = filepath.Join(location, entry.Name())
 			}
+			templates = append(templates, TemplateInfo{Name: entry.Name(), Location: location})
+			seen[entry.Name()] = true
 		}
 	}
 
@@ -0,0 +1,253 @@
+package template
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+	"testing/fstest"
+)
+
+// testEngine returns an engine whose only template root is files, and
+// loads the template named "test" from it
+func testEngine(t *testing.T, files fstest.MapFS) (*Engine, *Template) {
+	t.Helper()
+
+	engine := newEngine(templateRoot{location: "test", fsys: files})
+	tmpl, err := engine.LoadTemplate("test")
+	if err != nil {
+		t.Fatalf("LoadTemplate: %v", err)
+	}
+	return engine, tmpl
+}
+
+// relPaths returns paths relative to dir, sorted and slash-separated
+func relPaths(t *testing.T, dir string, paths []string) []string {
+	t.Helper()
+
+	rel := make([]string, len(paths))
+	for i, path := range paths {
+		r, err := filepath.Rel(dir, path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rel[i] = filepath.ToSlash(r)
+	}
+	sort.Strings(rel)
+	return rel
+}
+
+func TestRenderAllConditions(t *testing.T) {
+	files := fstest.MapFS{
+		"test/template.yaml": {Data: []byte(`name: test
+files:
+  - name: index
+    template: index.md.tmpl
+    output: index.md
+  - name: services
+    template: services.md.tmpl
+    output: services.md
+    condition: len(Services) > 0
+  - name: component
+    template: component.md.tmpl
+    output: "{{.Name}}.md"
+    foreach: components
+  - name: tests
+    template: tests.md.tmpl
+    output: "{{.Name}}-tests.md"
+    foreach: components
+    condition: HasTests && Type == "service"
+`)},
+		"test/index.md.tmpl":     {Data: []byte("index")},
+		"test/services.md.tmpl":  {Data: []byte("services")},
+		"test/component.md.tmpl": {Data: []byte("{{.Name}}")},
+		"test/tests.md.tmpl":     {Data: []byte("{{.Name}} tests")},
+	}
+	engine, tmpl := testEngine(t, files)
+
+	components := []ComponentData{
+		{Name: "api", Type: "service", HasTests: true},
+		{Name: "worker", Type: "service"},
+		{Name: "lib", Type: "library", HasTests: true},
+	}
+
+	tests := []struct {
+		name string
+		data TemplateData
+		want []string
+	}{
+		{
+			name: "plain condition false",
+			data: TemplateData{Components: components},
+			want: []string{"api-tests.md", "api.md", "index.md", "lib.md", "worker.md"},
+		},
+		{
+			name: "plain condition true",
+			data: TemplateData{Components: components, Services: []ServiceData{{Name: "orders"}}},
+			want: []string{"api-tests.md", "api.md", "index.md", "lib.md", "services.md", "worker.md"},
+		},
+		{
+			name: "no components",
+			data: TemplateData{},
+			want: []string{"index.md"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			generated, err := engine.RenderAll(tmpl, tt.data, dir)
+			if err != nil {
+				t.Fatalf("RenderAll: %v", err)
+			}
+
+			got := relPaths(t, dir, generated)
+			if len(got) != len(tt.want) {
+				t.Fatalf("generated %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("generated %v, want %v", got, tt.want)
+				}
+			}
+
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != len(tt.want) {
+				t.Errorf("wrote %d files, want %d", len(entries), len(tt.want))
+			}
+		})
+	}
+}
+
+func TestRenderAllInvalidCondition(t *testing.T) {
+	for _, foreach := range []string{"", "components"} {
+		files := fstest.MapFS{
+			"test/template.yaml": {Data: []byte(`name: test
+files:
+  - name: page
+    template: page.md.tmpl
+    output: "{{.Name}}.md"
+    foreach: ` + foreach + `
+    condition: NoSuchField
+`)},
+			"test/page.md.tmpl": {Data: []byte("page")},
+		}
+		engine, tmpl := testEngine(t, files)
+
+		data := TemplateData{RepoName: "repo", Components: []ComponentData{{Name: "api"}}}
+		if _, err := engine.RenderAll(tmpl, data, t.TempDir()); err == nil {
+			t.Errorf("foreach %q: expected an error for an unknown field", foreach)
+		}
+	}
+}
+
+func TestRenderSharedPartial(t *testing.T) {
+	files := fstest.MapFS{
+		"test/template.yaml": {Data: []byte(`name: test
+files:
+  - name: index
+    template: index.md.tmpl
+    output: index.md
+  - name: component
+    template: component.md.tmpl
+    output: "components/{{.Name}}.md"
+    foreach: components
+`)},
+		"test/index.md.tmpl":            {Data: []byte(`# {{.RepoName}}{{template "footer" .}}`)},
+		"test/component.md.tmpl":        {Data: []byte(`# {{.Name}}{{template "footer" .}}{{template "badge" .Type}}`)},
+		"test/partials/footer.md.tmpl":  {Data: []byte("\n---\n{{.GeneratedBy}}\n")},
+		"test/partials/helpers.md.tmpl": {Data: []byte(`{{define "badge"}}[{{. | title}}]{{end}}`)},
+	}
+	engine, tmpl := testEngine(t, files)
+
+	data := TemplateData{
+		RepoName:    "shop",
+		GeneratedBy: "Generated by DocBrown",
+		Components:  []ComponentData{{Name: "api", Type: "service", GeneratedBy: "Generated by DocBrown"}},
+	}
+
+	dir := t.TempDir()
+	if _, err := engine.RenderAll(tmpl, data, dir); err != nil {
+		t.Fatalf("RenderAll: %v", err)
+	}
+
+	want := map[string]string{
+		"index.md":          "# shop\n---\nGenerated by DocBrown\n",
+		"components/api.md": "# api\n---\nGenerated by DocBrown\n[Service]",
+	}
+	for name, content := range want {
+		got, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != content {
+			t.Errorf("%s = %q, want %q", name, got, content)
+		}
+	}
+}
+
+// Run with -race: components' pages are rendered from parallel goroutines
+// while the template may be reloaded
+func TestRenderConcurrent(t *testing.T) {
+	files := fstest.MapFS{
+		"test/template.yaml": {Data: []byte(`name: test
+files:
+  - name: component
+    template: component.md.tmpl
+    output: "components/{{.Type}}/{{.Name}}.md"
+    foreach: components
+`)},
+		"test/component.md.tmpl":       {Data: []byte(`# {{.Name}}{{template "footer" .}}`)},
+		"test/partials/footer.md.tmpl": {Data: []byte("\n{{.Type}}\n")},
+	}
+	engine, tmpl := testEngine(t, files)
+
+	const n = 64
+	dir := t.TempDir()
+	types := []string{"service", "library", "frontend"}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 2*n)
+	for i := 0; i < n; i++ {
+		wg.Add(2)
+
+		go func(i int) {
+			defer wg.Done()
+			data := TemplateData{Components: []ComponentData{
+				{Name: fmt.Sprintf("comp%d", i), Type: types[i%len(types)]},
+			}}
+			if _, err := engine.RenderComponents(tmpl, data, dir); err != nil {
+				errs <- err
+			}
+		}(i)
+
+		go func() {
+			defer wg.Done()
+			if _, err := engine.LoadTemplate("test"); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+
+	for i := 0; i < n; i++ {
+		name, typ := fmt.Sprintf("comp%d", i), types[i%len(types)]
+		got, err := os.ReadFile(filepath.Join(dir, "components", typ, name+".md"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := "# " + name + "\n" + typ + "\n"; string(got) != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+}
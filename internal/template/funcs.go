@@ -0,0 +1,72 @@
+package template
+
+import (
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+)
+
+// funcMap contains the helper functions available to all templates
+var funcMap = template.FuncMap{
+	"lower":   strings.ToLower,
+	"upper":   strings.ToUpper,
+	"slugify": slugify,
+	"title":   title,
+	"date":    date,
+	"join":    join,
+	"cell":    cell,
+}
+
+// slugify converts text to a lowercase, URL-safe slug
+func slugify(s string) string {
+	var sb strings.Builder
+	dash := false
+
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			sb.WriteRune(r)
+			dash = false
+		} else if !dash && sb.Len() > 0 {
+			sb.WriteRune('-')
+			dash = true
+		}
+	}
+
+	return strings.TrimSuffix(sb.String(), "-")
+}
+
+// title uppercases the first letter of each word
+func title(s string) string {
+	runes := []rune(s)
+	start := true
+
+	for i, r := range runes {
+		if unicode.IsSpace(r) || r == '-' || r == '_' {
+			start = true
+			continue
+		}
+		if start {
+			runes[i] = unicode.ToUpper(r)
+			start = false
+		}
+	}
+
+	return string(runes)
+}
+
+// date formats a time with a Go layout, e.g. {{.Timestamp | date "2006-01-02"}}
+func date(layout string, t time.Time) string {
+	return t.Format(layout)
+}
+
+// join joins a list with a separator, e.g. {{.Architecture.Technologies | join ", "}}
+func join(sep string, items []string) string {
+	return strings.Join(items, sep)
+}
+
+// cell makes text safe inside a markdown table cell, e.g. {{.Type | cell}}
+func cell(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,129 @@
+package template
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// UserDir returns the directory templates are installed into and shared
+// across repositories, ~/.docbrown/templates
+func UserDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".docbrown", "templates"), nil
+}
+
+// ValidName reports whether name can name an installed template: a single
+// path element, so the template stays inside the user template directory
+func ValidName(name string) bool {
+	return fs.ValidPath(name) && name != "." && !strings.ContainsAny(name, `/\`)
+}
+
+// Install copies the template in srcDir into the user template directory
+// as name and returns where it was installed. The template must load
+// cleanly. An installed template of the same name is only replaced with force.
+func Install(srcDir, name string, force bool) (string, error) {
+	if !ValidName(name) {
+		return "", fmt.Errorf("invalid template name %q", name)
+	}
+
+	srcDir, err := filepath.Abs(srcDir)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(filepath.Join(srcDir, "template.yaml")); err != nil {
+		return "", fmt.Errorf("no template.yaml in %s", srcDir)
+	}
+
+	check := newEngine(dirRoot(filepath.Dir(srcDir)))
+	if _, err := check.LoadTemplate(filepath.Base(srcDir)); err != nil {
+		return "", fmt.Errorf("invalid template: %w", err)
+	}
+
+	userDir, err := UserDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find home directory: %w", err)
+	}
+	dest := filepath.Join(userDir, name)
+
+	if _, err := os.Stat(dest); err == nil && !force {
+		return "", fmt.Errorf("template %s is already installed in %s (use --force to replace it)", name, dest)
+	}
+
+	if err := os.MkdirAll(userDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", userDir, err)
+	}
+
+	// Copy next to the destination first so a failed copy leaves any
+	// installed version intact
+	staging, err := os.MkdirTemp(userDir, "."+name+"-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	defer os.RemoveAll(staging)
+
+	if err := copyDir(srcDir, staging); err != nil {
+		return "", fmt.Errorf("failed to copy template: %w", err)
+	}
+
+	if err := os.RemoveAll(dest); err != nil {
+		return "", fmt.Errorf("failed to remove installed template: %w", err)
+	}
+	if err := os.Rename(staging, dest); err != nil {
+		return "", fmt.Errorf("failed to install template: %w", err)
+	}
+
+	return dest, nil
+}
+
+// copyDir copies the files under src into dst, skipping .git
+func copyDir(src, dst string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return os.MkdirAll(target, 0755)
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		return copyFile(path, target, info.Mode().Perm())
+	})
+}
+
+// copyFile copies a single file
+func copyFile(src, dst string, perm os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,32 @@
+package template
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestInstallRejectsInvalidNames(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	// Something to lose if a name resolved to the user template directory
+	// or above it
+	keep := filepath.Join(home, ".docbrown", "config.yaml")
+	if err := os.MkdirAll(filepath.Dir(keep), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keep, []byte("llm:\n  provider: mock\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"", ".", "..", "../x", "a/b", `a\b`, "/abs"} {
+		if _, err := Install("../../templates/backstage", name, true); err == nil {
+			t.Errorf("Install(%q) succeeded", name)
+		}
+	}
+
+	if _, err := os.Stat(keep); err != nil {
+		t.Errorf("config removed: %v", err)
+	}
+}
@@ -4,12 +4,24 @@ import "time"
 
 // Template represents a documentation template
 type Template struct {
-	Name        string         `yaml:"name"`
-	Version     string         `yaml:"version"`
-	Description string         `yaml:"description"`
-	Path        string         `yaml:"-"`
-	Files       []TemplateFile `yaml:"files"`
+	Name        string            `yaml:"name"`
+	Version     string            `yaml:"version"`
+	Description string            `yaml:"description"`
+	Path        string            `yaml:"-"`
+	Files       []TemplateFile    `yaml:"files"`
 	Prompts     map[string]string `yaml:"prompts,omitempty"`
+	Quality     *QualitySpec      `yaml:"quality,omitempty"`
+}
+
+// QualitySpec declares which generated files satisfy the validator's coverage
+// checks. Paths are relative to the output directory; an empty path means the
+// template doesn't produce that kind of document.
+type QualitySpec struct {
+	Overview       string   `yaml:"overview"`
+	Architecture   string   `yaml:"architecture"`
+	GettingStarted string   `yaml:"getting_started"`
+	APIDocs        []string `yaml:"api_docs"` // files or directories
+	Catalog        bool     `yaml:"catalog"`  // a Backstage catalog-info.yaml is produced
 }
 
 // TemplateFile represents a file to be generated from a template
@@ -36,6 +48,9 @@ type TemplateData struct {
 	Libraries  []LibraryData
 	Frontends  []FrontendData
 
+	// Backstage API entities, one per API definition file
+	APIs []APIEntityData
+
 	// Architecture
 	Architecture ArchitectureData
 
@@ -51,19 +66,34 @@ type TemplateData struct {
 
 // ComponentData represents component data for templates
 type ComponentData struct {
-	Name          string
-	Type          string
-	Language      string
-	Path          string
-	Description   string
-	Overview      string
-	APIs          []APIData
-	Dependencies  []DependencyData
-	UsageExample  string
-	Architecture  string
-	Configuration map[string]string
-	HasTests      bool
-	TestCoverage  float64
+	Name     string
+	Type     string
+	Language string
+	// Runtime and minimum RuntimeVersion, e.g. "Go" and "1.22"; empty if unknown
+	Runtime        string
+	RuntimeVersion string
+	Path           string
+	Description    string
+	Overview       string
+	APIs           []APIData
+	Functions      []FunctionData
+	Dependencies   []DependencyData
+	// Framework and client-side Routes of frontends
+	Framework string
+	Routes    []RouteData
+	// External dependencies from the component's manifest, split by scope
+	DirectDependencies     []DependencyData
+	TransitiveDependencies []DependencyData
+	DevDependencies        []DependencyData
+	Ports                  []int
+	BaseImage              string
+	Image                  string
+	UsageExample           string
+	Architecture           string
+	Configuration          map[string]string
+	HasTests               bool
+	TestCoverage           float64
+	GeneratedBy            string
 }
 
 // ServiceData represents service data for templates
@@ -72,10 +102,22 @@ type ServiceData struct {
 	Type         string
 	Description  string
 	Endpoints    []APIData
-	Port         int
+	Port         int // first port the service listens on, 0 if unknown
+	Ports        []int
+	BaseImage    string
+	Image        string
 	Dependencies []DependencyData
 }
 
+// APIEntityData represents a Backstage API entity backed by a definition file
+type APIEntityData struct {
+	Name        string
+	Type        string // openapi, grpc, graphql
+	Description string
+	Component   string
+	Definition  string // path of the definition file, relative to the output directory
+}
+
 // LibraryData represents library data for templates
 type LibraryData struct {
 	Name        string
@@ -94,30 +136,30 @@ type FrontendData struct {
 
 // APIData represents API endpoint data
 type APIData struct {
-	Method          string
-	Path            string
-	Description     string
-	Parameters      []ParameterData
-	RequestExample  string
-	ResponseExample string
-	ErrorCodes      []ErrorCodeData
-	Authentication  string
+	Method          string          `json:"method"`
+	Path            string          `json:"path"`
+	Description     string          `json:"description,omitempty"`
+	Parameters      []ParameterData `json:"parameters,omitempty"`
+	RequestExample  string          `json:"request_example,omitempty"`
+	ResponseExample string          `json:"response_example,omitempty"`
+	ErrorCodes      []ErrorCodeData `json:"error_codes,omitempty"`
+	Authentication  string          `json:"authentication,omitempty"`
 }
 
 // ParameterData represents parameter data
 type ParameterData struct {
-	Name        string
-	Type        string
-	Required    bool
-	Description string
-	Example     string
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Required    bool   `json:"required"`
+	Description string `json:"description,omitempty"`
+	Example     string `json:"example,omitempty"`
 }
 
 // ErrorCodeData represents error code data
 type ErrorCodeData struct {
-	Code        int
-	Message     string
-	Description string
+	Code        int    `json:"code"`
+	Message     string `json:"message"`
+	Description string `json:"description,omitempty"`
 }
 
 // DependencyData represents dependency data
@@ -126,6 +168,7 @@ type DependencyData struct {
 	Version string
 	Purpose string
 	Type    string
+	Scope   string // direct, indirect, dev (external dependencies only)
 }
 
 // ArchitectureData represents architecture information
@@ -133,18 +176,29 @@ type ArchitectureData struct {
 	Overview     string
 	Components   []string
 	Patterns     []string
-	Technologies []string
+	Technologies []string // most prevalent first
 	Diagram      string
+
+	PrimaryLanguage string
+	Languages       []LanguageData // most prevalent first
+}
+
+// LanguageData represents a language's share of the source files
+type LanguageData struct {
+	Name        string
+	Files       int
+	Percent     float64 // by file count
+	BytePercent float64 // by size
 }
 
 // FunctionData represents function documentation
 type FunctionData struct {
-	Name        string
-	Signature   string
-	Description string
-	Parameters  []ParameterData
-	Returns     string
-	Example     string
+	Name        string          `json:"name"`
+	Signature   string          `json:"signature"`
+	Description string          `json:"description,omitempty"`
+	Parameters  []ParameterData `json:"parameters,omitempty"`
+	Returns     string          `json:"returns,omitempty"`
+	Example     string          `json:"example,omitempty"`
 }
 
 // RouteData represents frontend route data
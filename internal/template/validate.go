@@ -0,0 +1,188 @@
+package template
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"reflect"
+	"text/template"
+	"text/template/parse"
+
+	"gopkg.in/yaml.v3"
+)
+
+// forEachTypes maps the collections a file can iterate over to their item type
+var forEachTypes = map[string]reflect.Type{
+	"components": reflect.TypeOf(ComponentData{}),
+	"services":   reflect.TypeOf(ServiceData{}),
+}
+
+// legacyPathFields are output path placeholders substituted by expandPath
+// rather than resolved as fields
+var legacyPathFields = map[string]bool{
+	"ComponentName": true,
+	"ServiceName":   true,
+}
+
+// ValidateTemplate checks a template for problems that would otherwise only
+// surface at generation time. It returns every problem found; the error is
+// only set if template.yaml itself can't be read.
+func (e *Engine) ValidateTemplate(name string) ([]string, error) {
+	fsys, _, err := e.findTemplate(name)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := fs.ReadFile(fsys, "template.yaml")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template config: %w", err)
+	}
+
+	var tmpl Template
+	if err := yaml.Unmarshal(data, &tmpl); err != nil {
+		return nil, fmt.Errorf("failed to parse template config: %w", err)
+	}
+
+	var problems []string
+	report := func(file TemplateFile, format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf("%s: %s", file.Name, fmt.Sprintf(format, args...)))
+	}
+
+	if len(tmpl.Files) == 0 {
+		problems = append(problems, "template.yaml: no files defined")
+	}
+
+	names := make(map[string]bool)
+	for _, file := range tmpl.Files {
+		if file.Name == "" {
+			file.Name = file.Template
+			report(file, "missing name")
+		} else if names[file.Name] {
+			report(file, "duplicate name")
+		}
+		names[file.Name] = true
+
+		if file.Output == "" {
+			report(file, "missing output path")
+		}
+
+		// The data the file is rendered with
+		dataType := reflect.TypeOf(TemplateData{})
+		if file.Foreach != "" {
+			itemType, ok := forEachTypes[file.Foreach]
+			if !ok {
+				report(file, "unknown foreach collection %q (use components or services)", file.Foreach)
+				continue
+			}
+			dataType = itemType
+		}
+
+		for _, problem := range checkOutputPath(file.Output, dataType) {
+			report(file, "output %q: %s", file.Output, problem)
+		}
+
+		if _, err := evaluateCondition(file.Condition, reflect.Zero(dataType).Interface()); err != nil {
+			report(file, "condition %q: %v", file.Condition, err)
+		}
+
+		if file.Template == "" {
+			report(file, "missing template file")
+			continue
+		}
+
+		if _, err := fs.Stat(fsys, filepath.ToSlash(file.Template)); err != nil {
+			report(file, "template file %s not found", file.Template)
+			continue
+		}
+
+		t, err := parseTemplateFile(fsys, file.Template)
+		if err != nil {
+			report(file, "%v", err)
+			continue
+		}
+
+		if err := parsePartials(t, fsys); err != nil {
+			report(file, "%v", err)
+			continue
+		}
+
+		// Rendering empty data catches references to fields that don't exist
+		if err := t.Execute(io.Discard, reflect.Zero(dataType).Interface()); err != nil {
+			report(file, "%v", err)
+		}
+	}
+
+	return problems, nil
+}
+
+// checkOutputPath checks that the fields referenced by an output path exist
+// on the data it is rendered with
+func checkOutputPath(output string, dataType reflect.Type) []string {
+	t, err := template.New("output").Funcs(funcMap).Parse(output)
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	var problems []string
+	var walk func(node parse.Node)
+	walk = func(node parse.Node) {
+		switch n := node.(type) {
+		case *parse.ListNode:
+			if n == nil {
+				return
+			}
+			for _, child := range n.Nodes {
+				walk(child)
+			}
+		case *parse.ActionNode:
+			walk(n.Pipe)
+		case *parse.PipeNode:
+			for _, cmd := range n.Cmds {
+				for _, arg := range cmd.Args {
+					walk(arg)
+				}
+			}
+		case *parse.IfNode:
+			walk(n.Pipe)
+			walk(n.List)
+			walk(n.ElseList)
+		case *parse.FieldNode:
+			if len(n.Ident) == 1 && legacyPathFields[n.Ident[0]] {
+				return
+			}
+			if err := checkFieldPath(dataType, n.Ident); err != nil {
+				problems = append(problems, err.Error())
+			}
+		}
+	}
+	walk(t.Tree.Root)
+
+	return problems
+}
+
+// checkFieldPath checks that a field chain such as .Architecture.Overview exists on typ
+func checkFieldPath(typ reflect.Type, idents []string) error {
+	for _, ident := range idents {
+		for typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+
+		switch typ.Kind() {
+		case reflect.Struct:
+			field, ok := typ.FieldByName(ident)
+			if !ok {
+				if _, ok := reflect.PointerTo(typ).MethodByName(ident); ok {
+					return nil // Method call; its result isn't checked further
+				}
+				return fmt.Errorf("unknown field .%s on %s", ident, typ.Name())
+			}
+			typ = field.Type
+		case reflect.Map:
+			return nil // Any key is valid
+		default:
+			return fmt.Errorf("cannot access .%s on %s", ident, typ)
+		}
+	}
+	return nil
+}
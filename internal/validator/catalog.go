@@ -0,0 +1,152 @@
+package validator
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// entityNameRe is Backstage's rule for metadata.name: letters and digits
+// joined by single [-_.] separators
+var entityNameRe = regexp.MustCompile(`^([A-Za-z0-9]+[-_.])*[A-Za-z0-9]+$`)
+
+// entityKinds maps each known Backstage kind to its required spec fields
+var entityKinds = map[string][]string{
+	"Component": {"type", "lifecycle", "owner"},
+	"API":       {"type", "lifecycle", "owner", "definition"},
+	"System":    {"owner"},
+	"Domain":    {"owner"},
+	"Resource":  {"type", "owner"},
+	"Group":     {"type", "children"},
+	"User":      {},
+	"Location":  {},
+	"Template":  {"type"},
+}
+
+// validateCatalog validates the Backstage catalog file and returns every
+// problem found (empty if the catalog is valid)
+func (v *Validator) validateCatalog() []string {
+	// Check in docs directory first (standard location)
+	catalogPath := filepath.Join(v.docsDir, "catalog-info.yaml")
+
+	// Fall back to root if not found in docs
+	if !v.fileExists(catalogPath) {
+		catalogPath = "catalog-info.yaml"
+	}
+
+	if !v.fileExists(catalogPath) {
+		return []string{"catalog-info.yaml not found in docs/ or root"}
+	}
+
+	content, err := os.ReadFile(catalogPath)
+	if err != nil {
+		return []string{fmt.Sprintf("failed to read catalog: %v", err)}
+	}
+
+	// A catalog file may hold several entities (e.g. a Component and the
+	// APIs it provides) as separate YAML documents
+	var entities []map[string]interface{}
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	for {
+		var entity map[string]interface{}
+		if err := decoder.Decode(&entity); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return []string{fmt.Sprintf("invalid YAML: %v", err)}
+		}
+		if entity != nil {
+			entities = append(entities, entity)
+		}
+	}
+
+	if len(entities) == 0 {
+		return []string{"catalog contains no entities"}
+	}
+
+	var problems []string
+	for i, entity := range entities {
+		for _, problem := range validateEntity(entity) {
+			if len(entities) > 1 {
+				problem = fmt.Sprintf("entity %d: %s", i+1, problem)
+			}
+			problems = append(problems, problem)
+		}
+	}
+
+	return problems
+}
+
+// validateEntity checks a single catalog entity against the Backstage schema
+func validateEntity(entity map[string]interface{}) []string {
+	var problems []string
+
+	if apiVersion, _ := entity["apiVersion"].(string); apiVersion == "" {
+		problems = append(problems, "missing apiVersion")
+	} else if !strings.HasPrefix(apiVersion, "backstage.io/") {
+		problems = append(problems, fmt.Sprintf("unexpected apiVersion %q (expected backstage.io/v1alpha1)", apiVersion))
+	}
+
+	kind, _ := entity["kind"].(string)
+	required, known := entityKinds[kind]
+	switch {
+	case kind == "":
+		problems = append(problems, "missing kind")
+	case !known:
+		problems = append(problems, fmt.Sprintf("unknown kind %q (expected one of %s)", kind, knownKinds()))
+	}
+
+	metadata, ok := entity["metadata"].(map[string]interface{})
+	if !ok {
+		problems = append(problems, "missing metadata")
+	} else if name, _ := metadata["name"].(string); name == "" {
+		problems = append(problems, "missing metadata.name")
+	} else if len(name) > 63 || !entityNameRe.MatchString(name) {
+		problems = append(problems, fmt.Sprintf("invalid metadata.name %q (letters, digits and single -_. separators, at most 63 characters)", name))
+	}
+
+	if kind == "" || !known || len(required) == 0 {
+		return problems
+	}
+
+	spec, ok := entity["spec"].(map[string]interface{})
+	if !ok {
+		return append(problems, fmt.Sprintf("%s is missing spec", kind))
+	}
+
+	for _, field := range required {
+		if isEmptyValue(spec[field]) {
+			problems = append(problems, fmt.Sprintf("%s is missing spec.%s", kind, field))
+		}
+	}
+
+	return problems
+}
+
+// isEmptyValue reports whether a YAML value is absent or blank
+func isEmptyValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(v) == ""
+	}
+	return false
+}
+
+// knownKinds lists the known entity kinds for error messages
+func knownKinds() string {
+	kinds := make([]string, 0, len(entityKinds))
+	for kind := range entityKinds {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return strings.Join(kinds, ", ")
+}
@@ -0,0 +1,42 @@
+package validator
+
+import "github.com/docbrown/cli/internal/template"
+
+// Layout describes where a documentation template puts the files the
+// validator checks for. Paths are relative to the docs directory; an empty
+// path means the template doesn't produce that file and it isn't scored.
+type Layout struct {
+	Overview       string
+	Architecture   string
+	GettingStarted string
+	APIDocs        []string // files or directories
+	Catalog        bool     // a Backstage catalog-info.yaml is expected
+}
+
+// BackstageLayout is the Backstage TechDocs (docs/docs/) layout
+func BackstageLayout() Layout {
+	return Layout{
+		Overview:       "docs/index.md",
+		Architecture:   "docs/architecture/overview.md",
+		GettingStarted: "docs/guides/getting-started.md",
+		APIDocs:        []string{"docs/api", "api"},
+		Catalog:        true,
+	}
+}
+
+// LayoutForTemplate returns the layout declared in a template's quality
+// section. Templates that don't declare one (or a nil template) get the
+// Backstage layout.
+func LayoutForTemplate(tmpl *template.Template) Layout {
+	if tmpl == nil || tmpl.Quality == nil {
+		return BackstageLayout()
+	}
+
+	return Layout{
+		Overview:       tmpl.Quality.Overview,
+		Architecture:   tmpl.Quality.Architecture,
+		GettingStarted: tmpl.Quality.GettingStarted,
+		APIDocs:        tmpl.Quality.APIDocs,
+		Catalog:        tmpl.Quality.Catalog,
+	}
+}
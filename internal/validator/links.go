@@ -0,0 +1,126 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/docbrown/cli/internal/httpclient"
+)
+
+// externalLinkWorkers bounds the number of concurrent external link requests
+const externalLinkWorkers = 8
+
+// externalLink is an http(s) link found in a markdown file
+type externalLink struct {
+	Source string
+	Target string
+	Line   int
+}
+
+// EnableExternalLinks turns on checking of http(s) links with HEAD requests
+// (falling back to GET). Each request is bounded by timeout.
+func (v *Validator) EnableExternalLinks(timeout time.Duration) {
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	v.checkExternal = true
+	v.linkTimeout = timeout
+}
+
+// checkExternalLinks requests each unique URL once using a bounded worker pool
+// and returns a BrokenLink for every occurrence of a URL that failed
+func (v *Validator) checkExternalLinks(links []externalLink) []BrokenLink {
+	// Each URL is only requested once per run
+	var urls []string
+	seen := make(map[string]bool)
+	for _, link := range links {
+		if !seen[link.Target] {
+			seen[link.Target] = true
+			urls = append(urls, link.Target)
+		}
+	}
+
+	client := httpclient.New(v.linkTimeout)
+	failures := make(map[string]string, len(urls))
+	var mu sync.Mutex
+
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+
+	for w := 0; w < externalLinkWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for url := range jobs {
+				if reason := checkURL(client, url); reason != "" {
+					mu.Lock()
+					failures[url] = reason
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	for _, url := range urls {
+		jobs <- url
+	}
+	close(jobs)
+	wg.Wait()
+
+	var broken []BrokenLink
+	for _, link := range links {
+		if reason, ok := failures[link.Target]; ok {
+			broken = append(broken, BrokenLink{
+				Source: link.Source,
+				Target: link.Target,
+				Line:   link.Line,
+				Reason: reason,
+			})
+		}
+	}
+
+	return broken
+}
+
+// checkURL requests a URL and returns why it is broken, or "" if it is reachable.
+// Servers that reject HEAD are retried with GET.
+func checkURL(client *http.Client, url string) string {
+	status, err := requestURL(client, http.MethodHead, url)
+	if err != nil || status >= 400 {
+		status, err = requestURL(client, http.MethodGet, url)
+	}
+
+	if err != nil {
+		return err.Error()
+	}
+	if status >= 400 {
+		return fmt.Sprintf("status %d", status)
+	}
+	return ""
+}
+
+// requestURL sends a request and returns the response status code
+func requestURL(client *http.Client, method, url string) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), client.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return 0, fmt.Errorf("invalid URL: %w", err)
+	}
+	req.Header.Set("User-Agent", "docbrown-link-checker")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return 0, fmt.Errorf("timeout after %s", client.Timeout)
+		}
+		return 0, fmt.Errorf("request failed: %w", err)
+	}
+	resp.Body.Close()
+
+	return resp.StatusCode, nil
+}
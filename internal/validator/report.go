@@ -0,0 +1,236 @@
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// sarifSchema is the SARIF 2.1.0 JSON schema URL
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+// FormatJSON formats validation results as indented JSON
+func (v *Validator) FormatJSON(results *ValidationResults) ([]byte, error) {
+	return json.MarshalIndent(results, "", "  ")
+}
+
+// FormatMarkdown formats a short summary of the results for a PR
+// description: the score, a check per category and issue counts
+func (v *Validator) FormatMarkdown(results *ValidationResults) string {
+	var sb strings.Builder
+
+	sb.WriteString("## Documentation Quality\n\n")
+	sb.WriteString(fmt.Sprintf("**Quality score: %.1f/10** (%s)\n\n", results.QualityScore, Grade(results.QualityScore)))
+
+	sb.WriteString("| Check | Result |\n")
+	sb.WriteString("|-------|--------|\n")
+	sb.WriteString(fmt.Sprintf("| Markdown | %s |\n", countCheck(len(results.MarkdownErrors), "issue", "issues")))
+	sb.WriteString(fmt.Sprintf("| Links | %s |\n", countCheck(len(results.BrokenLinks), "broken link", "broken links")))
+	if v.spellCheck {
+		sb.WriteString(fmt.Sprintf("| Spelling | %s |\n", countCheck(len(results.SpellingErrors), "issue", "issues")))
+	}
+	if v.layout.Catalog {
+		sb.WriteString(fmt.Sprintf("| Backstage catalog | %s |\n", boolCheck(results.CatalogValid)))
+	}
+	sb.WriteString(fmt.Sprintf("| Overview | %s |\n", coverageCheck(v.layout.Overview != "", results.HasOverview)))
+	sb.WriteString(fmt.Sprintf("| API docs | %s |\n", coverageCheck(len(v.layout.APIDocs) > 0, results.HasAPIDocs)))
+	sb.WriteString(fmt.Sprintf("| Architecture | %s |\n", coverageCheck(v.layout.Architecture != "", results.HasArchitecture)))
+	sb.WriteString(fmt.Sprintf("| Getting started | %s |\n", coverageCheck(v.layout.GettingStarted != "", results.HasGettingStarted)))
+
+	return sb.String()
+}
+
+// countCheck marks a category with no problems, or counts them
+func countCheck(n int, singular, plural string) string {
+	switch n {
+	case 0:
+		return "✓"
+	case 1:
+		return "✗ 1 " + singular
+	default:
+		return fmt.Sprintf("✗ %d %s", n, plural)
+	}
+}
+
+// savedResults is the file written by SaveResults
+type savedResults struct {
+	Generated time.Time          `json:"generated"`
+	Results   *ValidationResults `json:"results"`
+}
+
+// SaveResults records validation results for later commands (e.g. pr),
+// along with the generation time of the docs they describe
+func SaveResults(path string, generated time.Time, results *ValidationResults) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(savedResults{Generated: generated, Results: results}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal validation results: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write validation results: %w", err)
+	}
+	return nil
+}
+
+// LoadResults reads results written by SaveResults and the generation time
+// they were recorded for
+func LoadResults(path string) (time.Time, *ValidationResults, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+
+	var saved savedResults
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return time.Time{}, nil, fmt.Errorf("failed to parse validation results: %w", err)
+	}
+	if saved.Results == nil {
+		return time.Time{}, nil, fmt.Errorf("failed to parse validation results: no results")
+	}
+	return saved.Generated, saved.Results, nil
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// FormatSARIF formats markdown errors and broken links as a SARIF 2.1.0 log,
+// which GitHub code scanning can show as annotations on pull requests
+func (v *Validator) FormatSARIF(results *ValidationResults) ([]byte, error) {
+	rules := make(map[string]string)
+	var sarifResults []sarifResult
+
+	issues := append(append([]ValidationError{}, results.MarkdownErrors...), results.SpellingErrors...)
+	for _, issue := range issues {
+		rules[issue.Type] = "Markdown issue: " + issue.Type
+
+		level := "error"
+		if issue.IsWarning() {
+			level = "warning"
+		}
+
+		sarifResults = append(sarifResults, newSARIFResult(issue.Type, level, issue.Message, issue.File, issue.Line))
+	}
+
+	for _, link := range results.BrokenLinks {
+		rules["broken-link"] = "Link target does not exist"
+
+		message := "Broken link: " + link.Target
+		if link.Reason != "" {
+			message += " (" + link.Reason + ")"
+		}
+
+		sarifResults = append(sarifResults, newSARIFResult("broken-link", "error", message, link.Source, link.Line))
+	}
+
+	ruleIDs := make([]string, 0, len(rules))
+	for id := range rules {
+		ruleIDs = append(ruleIDs, id)
+	}
+	sort.Strings(ruleIDs)
+
+	driver := sarifDriver{
+		Name:           "docbrown",
+		InformationURI: "https://github.com/docbrown/cli",
+		Rules:          []sarifRule{},
+	}
+	for _, id := range ruleIDs {
+		driver.Rules = append(driver.Rules, sarifRule{
+			ID:               id,
+			ShortDescription: sarifMessage{Text: rules[id]},
+		})
+	}
+
+	if sarifResults == nil {
+		sarifResults = []sarifResult{}
+	}
+
+	log := sarifLog{
+		Schema:  sarifSchema,
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool:    sarifTool{Driver: driver},
+			Results: sarifResults,
+		}},
+	}
+
+	return json.MarshalIndent(log, "", "  ")
+}
+
+// newSARIFResult builds a SARIF result located at a file and line
+func newSARIFResult(ruleID, level, message, file string, line int) sarifResult {
+	// SARIF lines are 1-based
+	if line < 1 {
+		line = 1
+	}
+
+	return sarifResult{
+		RuleID:  ruleID,
+		Level:   level,
+		Message: sarifMessage{Text: message},
+		Locations: []sarifLocation{{
+			PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(file)},
+				Region:           sarifRegion{StartLine: line},
+			},
+		}},
+	}
+}
@@ -0,0 +1,101 @@
+package validator
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+//go:embed misspellings.txt
+var misspellingsData string
+
+// misspellings maps commonly misspelled words to their corrections
+var misspellings = parseMisspellings(misspellingsData)
+
+var (
+	inlineCodePattern = regexp.MustCompile("`[^`]*`")
+	urlPattern        = regexp.MustCompile(`(?:https?|ftp)://\S+|www\.\S+`)
+	linkTargetPattern = regexp.MustCompile(`\]\([^)]*\)`)
+	htmlTagPattern    = regexp.MustCompile(`<[^>]+>`)
+	wordPattern       = regexp.MustCompile(`[A-Za-z]+(?:'[A-Za-z]+)?`)
+)
+
+// parseMisspellings parses "misspelling correction" lines, skipping comments
+func parseMisspellings(data string) map[string]string {
+	words := make(map[string]string)
+
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.SplitN(line, " ", 2)
+		if len(fields) == 2 {
+			words[fields[0]] = strings.TrimSpace(fields[1])
+		}
+	}
+
+	return words
+}
+
+// spellCheckFile reports misspelled words in the prose of a markdown file,
+// skipping front-matter, code blocks, inline code and URLs
+func (v *Validator) spellCheckFile(path string) []ValidationError {
+	var errors []ValidationError
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return errors
+	}
+
+	lines := strings.Split(string(content), "\n")
+	inCodeBlock := false
+	inFrontMatter := len(lines) > 0 && strings.TrimSpace(lines[0]) == "---"
+
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		if inFrontMatter {
+			if i > 0 && trimmed == "---" {
+				inFrontMatter = false
+			}
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inCodeBlock = !inCodeBlock
+			continue
+		}
+		if inCodeBlock {
+			continue
+		}
+
+		prose := inlineCodePattern.ReplaceAllString(line, " ")
+		prose = linkTargetPattern.ReplaceAllString(prose, "]")
+		prose = urlPattern.ReplaceAllString(prose, " ")
+		prose = htmlTagPattern.ReplaceAllString(prose, " ")
+
+		for _, word := range wordPattern.FindAllString(prose, -1) {
+			lower := strings.ToLower(word)
+			if v.spellAllowlist[lower] {
+				continue
+			}
+
+			if correction, ok := misspellings[lower]; ok {
+				errors = append(errors, ValidationError{
+					File:    path,
+					Line:    i + 1,
+					Type:    "spelling",
+					Message: fmt.Sprintf("Possible misspelling %q (did you mean %q?)", word, correction),
+				})
+			}
+		}
+	}
+
+	return errors
+}
@@ -2,18 +2,24 @@ package validator
 
 import (
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
-
-	"gopkg.in/yaml.v3"
+	"time"
+	"unicode"
 )
 
 // Validator validates documentation quality
 type Validator struct {
-	strictMode bool
-	docsDir    string
+	strictMode     bool
+	docsDir        string
+	spellCheck     bool
+	spellAllowlist map[string]bool
+	checkExternal  bool
+	linkTimeout    time.Duration
+	layout         Layout
 }
 
 // NewValidator creates a new validator
@@ -21,35 +27,60 @@ func NewValidator(docsDir string, strictMode bool) *Validator {
 	return &Validator{
 		docsDir:    docsDir,
 		strictMode: strictMode,
+		layout:     BackstageLayout(),
+	}
+}
+
+// SetLayout sets the documentation layout to check required files against
+func (v *Validator) SetLayout(layout Layout) {
+	v.layout = layout
+}
+
+// EnableSpellCheck turns on spell-checking of markdown prose. Words in the
+// allowlist (case-insensitive) are never reported.
+func (v *Validator) EnableSpellCheck(allowlist []string) {
+	v.spellCheck = true
+	v.spellAllowlist = make(map[string]bool, len(allowlist))
+	for _, word := range allowlist {
+		v.spellAllowlist[strings.ToLower(word)] = true
 	}
 }
 
 // ValidationResults contains validation results
 type ValidationResults struct {
-	MarkdownErrors    []ValidationError
-	BrokenLinks       []BrokenLink
-	CatalogValid      bool
-	CatalogError      string
-	HasOverview       bool
-	HasAPIDocs        bool
-	HasArchitecture   bool
-	HasGettingStarted bool
-	QualityScore      float64
+	MarkdownErrors    []ValidationError `json:"markdown_errors"`
+	SpellingErrors    []ValidationError `json:"spelling_errors,omitempty"`
+	BrokenLinks       []BrokenLink      `json:"broken_links"`
+	CatalogValid      bool              `json:"catalog_valid"`
+	CatalogError      string            `json:"catalog_error,omitempty"`
+	CatalogErrors     []string          `json:"catalog_errors,omitempty"`
+	HasOverview       bool              `json:"has_overview"`
+	HasAPIDocs        bool              `json:"has_api_docs"`
+	HasArchitecture   bool              `json:"has_architecture"`
+	HasGettingStarted bool              `json:"has_getting_started"`
+	QualityScore      float64           `json:"quality_score"`
 }
 
 // ValidationError represents a validation error
 type ValidationError struct {
-	File    string
-	Line    int
-	Type    string
-	Message string
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Type     string `json:"type"`
+	Message  string `json:"message"`
+	Severity string `json:"severity,omitempty"` // "error" (default) or "warning"
+}
+
+// IsWarning reports whether the issue is informational outside strict mode
+func (e ValidationError) IsWarning() bool {
+	return e.Severity == "warning"
 }
 
 // BrokenLink represents a broken link
 type BrokenLink struct {
-	Source string
-	Target string
-	Line   int
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Line   int    `json:"line"`
+	Reason string `json:"reason,omitempty"` // e.g. "status 404" or a missing anchor
 }
 
 // Validate performs comprehensive validation
@@ -66,20 +97,28 @@ func (v *Validator) Validate() (*ValidationResults, error) {
 	for _, file := range mdFiles {
 		errors := v.validateMarkdownFile(file)
 		results.MarkdownErrors = append(results.MarkdownErrors, errors...)
+
+		if v.spellCheck {
+			results.SpellingErrors = append(results.SpellingErrors, v.spellCheckFile(file)...)
+		}
 	}
 
 	// Check for broken links
 	results.BrokenLinks = v.checkLinks(mdFiles)
 
-	// Check for required files (Backstage/MkDocs uses docs/docs/ structure)
-	docsSubdir := filepath.Join(v.docsDir, "docs")
-	results.HasOverview = v.fileExists(filepath.Join(docsSubdir, "index.md"))
-	results.HasArchitecture = v.fileExists(filepath.Join(docsSubdir, "architecture", "overview.md"))
-	results.HasGettingStarted = v.fileExists(filepath.Join(docsSubdir, "guides", "getting-started.md"))
+	// Check for required files in the template's layout
+	results.HasOverview = v.layoutFileExists(v.layout.Overview)
+	results.HasArchitecture = v.layoutFileExists(v.layout.Architecture)
+	results.HasGettingStarted = v.layoutFileExists(v.layout.GettingStarted)
 	results.HasAPIDocs = v.hasAPIFiles()
 
-	// Validate Backstage catalog
-	results.CatalogValid, results.CatalogError = v.validateCatalog()
+	// Validate Backstage catalog; templates without one have nothing to fail
+	results.CatalogValid = true
+	if v.layout.Catalog {
+		results.CatalogErrors = v.validateCatalog()
+		results.CatalogValid = len(results.CatalogErrors) == 0
+		results.CatalogError = strings.Join(results.CatalogErrors, "; ")
+	}
 
 	// Calculate quality score
 	results.QualityScore = v.calculateQualityScore(results)
@@ -127,12 +166,25 @@ func (v *Validator) validateMarkdownFile(path string) []ValidationError {
 	codeBlockStart := 0
 
 	for i, line := range lines {
-		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+		// TrimSpace also handles fences indented inside list items
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
 			if inCodeBlock {
 				inCodeBlock = false
 			} else {
 				inCodeBlock = true
 				codeBlockStart = i + 1
+
+				// Opening fences should name a language for syntax highlighting
+				if strings.TrimSpace(strings.TrimLeft(trimmed, "`")) == "" {
+					errors = append(errors, ValidationError{
+						File:     path,
+						Line:     i + 1,
+						Type:     "missing-code-lang",
+						Message:  "Code block has no language hint",
+						Severity: "warning",
+					})
+				}
 			}
 		}
 	}
@@ -146,9 +198,14 @@ func (v *Validator) validateMarkdownFile(path string) []ValidationError {
 		})
 	}
 
-	// Check heading hierarchy (skip lines in code blocks)
+	// Check heading hierarchy and duplicates (skip lines in code blocks).
+	// Duplicates are only reported among siblings, so repeated subsections
+	// like "### Parameters" under different parents are allowed.
 	lastLevel := 0
 	inCodeBlock2 := false
+	headingCount := 0
+	parents := make([]string, 7) // normalized heading text by level (h1-h6)
+	seen := make(map[string]int) // parent path + heading -> first line
 	for i, line := range lines {
 		// Track code blocks for heading check
 		if strings.HasPrefix(strings.TrimSpace(line), "```") {
@@ -181,15 +238,57 @@ func (v *Validator) validateMarkdownFile(path string) []ValidationError {
 			}
 
 			lastLevel = level
+			headingCount++
+
+			if level <= 6 {
+				text := normalizeHeading(line)
+				key := strings.Join(parents[1:level], "/") + "/" + text
+
+				if first, ok := seen[key]; ok {
+					errors = append(errors, ValidationError{
+						File:     path,
+						Line:     i + 1,
+						Type:     "duplicate-heading",
+						Message:  fmt.Sprintf("Duplicate heading %q (first on line %d)", strings.TrimSpace(strings.Trim(line, "# ")), first),
+						Severity: "warning",
+					})
+				} else {
+					seen[key] = i + 1
+				}
+
+				parents[level] = text
+				for l := level + 1; l < len(parents); l++ {
+					parents[l] = ""
+				}
+			}
 		}
 	}
 
+	if headingCount == 0 {
+		errors = append(errors, ValidationError{
+			File:     path,
+			Line:     1,
+			Type:     "empty-document",
+			Message:  "Document has no headings",
+			Severity: "warning",
+		})
+	}
+
 	return errors
 }
 
+// normalizeHeading returns a heading's text lowercased with markers and
+// extra whitespace removed, for comparing headings
+func normalizeHeading(line string) string {
+	text := strings.Trim(strings.TrimSpace(line), "#")
+	return strings.ToLower(strings.Join(strings.Fields(text), " "))
+}
+
 // checkLinks checks for broken internal links
 func (v *Validator) checkLinks(files []string) []BrokenLink {
 	var broken []BrokenLink
+	var external []externalLink
+	anchors := make(map[string]map[string]bool) // file -> heading slugs
 
 	linkRe := regexp.MustCompile(`\[([^\]]+)\]\(([^)]+)\)`)
 
@@ -206,93 +305,129 @@ func (v *Validator) checkLinks(files []string) []BrokenLink {
 				if len(match) > 2 {
 					target := match[2]
 
-					// Skip external links
+					// External links are only checked on request
 					if strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://") {
+						if v.checkExternal {
+							external = append(external, externalLink{Source: file, Target: target, Line: i + 1})
+						}
 						continue
 					}
 
-					// Check if target exists
-					targetPath := filepath.Join(filepath.Dir(file), target)
+					// Skip mail links
+					if strings.HasPrefix(target, "mailto:") {
+						continue
+					}
 
-					// Handle anchors
-					if strings.Contains(target, "#") {
-						parts := strings.Split(target, "#")
-						targetPath = filepath.Join(filepath.Dir(file), parts[0])
+					// Split off the anchor; "#section" refers to this file
+					targetFile, anchor, _ := strings.Cut(target, "#")
+					targetPath := file
+					if targetFile != "" {
+						targetPath = filepath.Join(filepath.Dir(file), targetFile)
 					}
 
+					// Check if target exists
 					if !v.fileExists(targetPath) {
 						broken = append(broken, BrokenLink{
 							Source: file,
 							Target: target,
 							Line:   i + 1,
 						})
+						continue
+					}
+
+					// Check the anchor matches a heading in markdown targets
+					if anchor == "" || !strings.HasSuffix(targetPath, ".md") {
+						continue
+					}
+
+					slugs, ok := anchors[targetPath]
+					if !ok {
+						slugs = headingSlugs(targetPath)
+						anchors[targetPath] = slugs
+					}
+
+					if !slugs[strings.ToLower(anchor)] {
+						broken = append(broken, BrokenLink{
+							Source: file,
+							Target: target,
+							Line:   i + 1,
+							Reason: fmt.Sprintf("no heading matches anchor #%s in %s", anchor, filepath.Base(targetPath)),
+						})
 					}
 				}
 			}
 		}
 	}
 
+	if len(external) > 0 {
+		broken = append(broken, v.checkExternalLinks(external)...)
+	}
+
 	return broken
 }
 
-// validateCatalog validates the Backstage catalog file
-func (v *Validator) validateCatalog() (bool, string) {
-	// Check in docs directory first (standard location)
-	catalogPath := filepath.Join(v.docsDir, "catalog-info.yaml")
-
-	// Fall back to root if not found in docs
-	if !v.fileExists(catalogPath) {
-		catalogPath = "catalog-info.yaml"
-	}
-
-	if !v.fileExists(catalogPath) {
-		return false, "catalog-info.yaml not found in docs/ or root"
-	}
+// headingSlugs returns the GitHub-style anchor slugs of every heading in a
+// markdown file. Repeated headings get -1, -2, ... suffixes as on GitHub.
+func headingSlugs(path string) map[string]bool {
+	slugs := make(map[string]bool)
 
-	content, err := os.ReadFile(catalogPath)
+	content, err := os.ReadFile(path)
 	if err != nil {
-		return false, fmt.Sprintf("failed to read catalog: %v", err)
+		return slugs
 	}
 
-	var catalog map[string]interface{}
-	if err := yaml.Unmarshal(content, &catalog); err != nil {
-		return false, fmt.Sprintf("invalid YAML: %v", err)
-	}
+	counts := make(map[string]int)
+	inCodeBlock := false
 
-	// Check required fields
-	if _, ok := catalog["apiVersion"]; !ok {
-		return false, "missing apiVersion"
-	}
+	for _, line := range strings.Split(string(content), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inCodeBlock = !inCodeBlock
+			continue
+		}
+		if inCodeBlock || !strings.HasPrefix(line, "#") {
+			continue
+		}
 
-	if _, ok := catalog["kind"]; !ok {
-		return false, "missing kind"
+		slug := headingSlug(strings.TrimSpace(strings.Trim(strings.TrimSpace(line), "#")))
+		if n := counts[slug]; n > 0 {
+			slugs[fmt.Sprintf("%s-%d", slug, n)] = true
+		} else {
+			slugs[slug] = true
+		}
+		counts[slug]++
 	}
 
-	metadata, ok := catalog["metadata"].(map[string]interface{})
-	if !ok {
-		return false, "missing metadata"
-	}
+	return slugs
+}
 
-	if _, ok := metadata["name"]; !ok {
-		return false, "missing metadata.name"
+// headingSlug converts heading text to a GitHub-style anchor: lowercased,
+// punctuation removed and spaces replaced with hyphens
+func headingSlug(text string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_':
+			sb.WriteRune(r)
+		case r == ' ':
+			sb.WriteRune('-')
+		}
 	}
-
-	return true, ""
+	return sb.String()
 }
 
-// hasAPIFiles checks if API documentation exists
+// hasAPIFiles checks if any of the layout's API doc files or directories exist
 func (v *Validator) hasAPIFiles() bool {
-	// Check in docs/docs/api (Backstage/MkDocs structure)
-	apiDir := filepath.Join(v.docsDir, "docs", "api")
-	info, err := os.Stat(apiDir)
-	if err == nil && info.IsDir() {
-		return true
+	for _, path := range v.layout.APIDocs {
+		if v.layoutFileExists(path) {
+			return true
+		}
 	}
+	return false
+}
 
-	// Fall back to docs/api
-	apiDir = filepath.Join(v.docsDir, "api")
-	info, err = os.Stat(apiDir)
-	return err == nil && info.IsDir()
+// layoutFileExists checks if a layout file exists; unused files never do
+func (v *Validator) layoutFileExists(path string) bool {
+	return path != "" && v.fileExists(filepath.Join(v.docsDir, path))
 }
 
 // fileExists checks if a file exists
@@ -305,12 +440,19 @@ func (v *Validator) fileExists(path string) bool {
 func (v *Validator) calculateQualityScore(results *ValidationResults) float64 {
 	score := 0.0
 	maxScore := 10.0
+	possible := maxScore // points available for this layout
 
-	// Markdown syntax (2 points)
-	if len(results.MarkdownErrors) == 0 {
+	// Markdown syntax (2 points); warnings only count in strict mode
+	markdownErrors := 0
+	for _, err := range results.MarkdownErrors {
+		if v.strictMode || !err.IsWarning() {
+			markdownErrors++
+		}
+	}
+	if markdownErrors == 0 {
 		score += 2.0
 	} else {
-		score += 2.0 * (1.0 - float64(len(results.MarkdownErrors))/10.0)
+		score += 2.0 * (1.0 - float64(markdownErrors)/10.0)
 	}
 
 	// Links valid (1.5 points)
@@ -320,29 +462,32 @@ func (v *Validator) calculateQualityScore(results *ValidationResults) float64 {
 		score += 1.5 * (1.0 - float64(len(results.BrokenLinks))/5.0)
 	}
 
-	// Has overview (1 point)
-	if results.HasOverview {
-		score += 1.0
-	}
-
-	// Has API docs (1.5 points)
-	if results.HasAPIDocs {
-		score += 1.5
+	// Files the layout doesn't produce aren't scored
+	layoutChecks := []struct {
+		applies bool
+		passed  bool
+		points  float64
+	}{
+		{v.layout.Overview != "", results.HasOverview, 1.0},
+		{len(v.layout.APIDocs) > 0, results.HasAPIDocs, 1.5},
+		{v.layout.Architecture != "", results.HasArchitecture, 1.0},
+		{v.layout.GettingStarted != "", results.HasGettingStarted, 1.0},
+		{v.layout.Catalog, results.CatalogValid, 2.0},
 	}
-
-	// Has architecture (1 point)
-	if results.HasArchitecture {
-		score += 1.0
+	for _, check := range layoutChecks {
+		if !check.applies {
+			possible -= check.points
+		} else if check.passed {
+			score += check.points
+		}
 	}
 
-	// Has getting started (1 point)
-	if results.HasGettingStarted {
-		score += 1.0
-	}
+	// Scale to the full score when some checks don't apply
+	score = score * maxScore / possible
 
-	// Catalog valid (2 points)
-	if results.CatalogValid {
-		score += 2.0
+	// Spelling (up to -0.5 points)
+	if len(results.SpellingErrors) > 0 {
+		score -= math.Min(0.5, 0.05*float64(len(results.SpellingErrors)))
 	}
 
 	if score < 0 {
@@ -367,7 +512,23 @@ func (v *Validator) FormatResults(results *ValidationResults) string {
 	} else {
 		sb.WriteString(fmt.Sprintf("✗ %d markdown issues:\n", len(results.MarkdownErrors)))
 		for _, err := range results.MarkdownErrors {
-			sb.WriteString(fmt.Sprintf("  %s:%d - %s\n", err.File, err.Line, err.Message))
+			if err.IsWarning() {
+				sb.WriteString(fmt.Sprintf("  %s:%d - warning: %s\n", err.File, err.Line, err.Message))
+			} else {
+				sb.WriteString(fmt.Sprintf("  %s:%d - %s\n", err.File, err.Line, err.Message))
+			}
+		}
+	}
+
+	// Spelling
+	if v.spellCheck {
+		if len(results.SpellingErrors) == 0 {
+			sb.WriteString("✓ No spelling issues\n")
+		} else {
+			sb.WriteString(fmt.Sprintf("✗ %d spelling issues:\n", len(results.SpellingErrors)))
+			for _, err := range results.SpellingErrors {
+				sb.WriteString(fmt.Sprintf("  %s:%d - %s\n", err.File, err.Line, err.Message))
+			}
 		}
 	}
 
@@ -377,43 +538,65 @@ func (v *Validator) FormatResults(results *ValidationResults) string {
 	} else {
 		sb.WriteString(fmt.Sprintf("✗ %d broken links:\n", len(results.BrokenLinks)))
 		for _, link := range results.BrokenLinks {
-			sb.WriteString(fmt.Sprintf("  %s:%d - %s\n", link.Source, link.Line, link.Target))
+			if link.Reason != "" {
+				sb.WriteString(fmt.Sprintf("  %s:%d - %s (%s)\n", link.Source, link.Line, link.Target, link.Reason))
+			} else {
+				sb.WriteString(fmt.Sprintf("  %s:%d - %s\n", link.Source, link.Line, link.Target))
+			}
 		}
 	}
 
-	// Catalog
-	if results.CatalogValid {
-		sb.WriteString("✓ Backstage catalog valid\n")
-	} else {
-		sb.WriteString(fmt.Sprintf("✗ Backstage catalog invalid: %s\n", results.CatalogError))
+	// Catalog (only for templates that produce one)
+	if v.layout.Catalog {
+		if results.CatalogValid {
+			sb.WriteString("✓ Backstage catalog valid\n")
+		} else {
+			sb.WriteString(fmt.Sprintf("✗ Backstage catalog invalid (%d problems):\n", len(results.CatalogErrors)))
+			for _, problem := range results.CatalogErrors {
+				sb.WriteString(fmt.Sprintf("  %s\n", problem))
+			}
+		}
 	}
 
 	// Coverage
 	sb.WriteString("\nCoverage:\n")
-	sb.WriteString(fmt.Sprintf("  Overview: %s\n", boolCheck(results.HasOverview)))
-	sb.WriteString(fmt.Sprintf("  API Docs: %s\n", boolCheck(results.HasAPIDocs)))
-	sb.WriteString(fmt.Sprintf("  Architecture: %s\n", boolCheck(results.HasArchitecture)))
-	sb.WriteString(fmt.Sprintf("  Getting Started: %s\n", boolCheck(results.HasGettingStarted)))
+	sb.WriteString(fmt.Sprintf("  Overview: %s\n", coverageCheck(v.layout.Overview != "", results.HasOverview)))
+	sb.WriteString(fmt.Sprintf("  API Docs: %s\n", coverageCheck(len(v.layout.APIDocs) > 0, results.HasAPIDocs)))
+	sb.WriteString(fmt.Sprintf("  Architecture: %s\n", coverageCheck(v.layout.Architecture != "", results.HasArchitecture)))
+	sb.WriteString(fmt.Sprintf("  Getting Started: %s\n", coverageCheck(v.layout.GettingStarted != "", results.HasGettingStarted)))
 
 	// Score
 	sb.WriteString(fmt.Sprintf("\nQuality Score: %.1f/10.0\n", results.QualityScore))
-
-	if results.QualityScore >= 9.0 {
-		sb.WriteString("Grade: Excellent ⭐\n")
-	} else if results.QualityScore >= 7.0 {
-		sb.WriteString("Grade: Good ✓\n")
-	} else if results.QualityScore >= 5.0 {
-		sb.WriteString("Grade: Acceptable ⚠\n")
-	} else {
-		sb.WriteString("Grade: Needs Improvement ✗\n")
-	}
+	sb.WriteString(fmt.Sprintf("Grade: %s\n", Grade(results.QualityScore)))
 
 	return sb.String()
 }
 
+// Grade describes a quality score
+func Grade(score float64) string {
+	switch {
+	case score >= 9.0:
+		return "Excellent ⭐"
+	case score >= 7.0:
+		return "Good ✓"
+	case score >= 5.0:
+		return "Acceptable ⚠"
+	default:
+		return "Needs Improvement ✗"
+	}
+}
+
 func boolCheck(b bool) string {
 	if b {
 		return "✓"
 	}
 	return "✗"
 }
+
+// coverageCheck marks a coverage item, or "n/a" if the layout doesn't include it
+func coverageCheck(applies, b bool) string {
+	if !applies {
+		return "n/a"
+	}
+	return boolCheck(b)
+}
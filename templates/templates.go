@@ -0,0 +1,8 @@
+package templates
+
+import "embed"
+
+// FS holds the built-in templates, one directory per template
+//
+//go:embed backstage markdown
+var FS embed.FS